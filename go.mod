@@ -1,5 +1,16 @@
 module github.com/mindship/uniparse
 
-go 1.13
+go 1.24.0
 
-require github.com/mitchellh/mapstructure v1.1.2
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/linkedin/goavro/v2 v2.12.0
+	github.com/mitchellh/mapstructure v1.1.2
+	golang.org/x/crypto v0.45.0
+	golang.org/x/text v0.31.0
+)
+
+require (
+	github.com/golang/snappy v0.0.1 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+)
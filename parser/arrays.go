@@ -0,0 +1,259 @@
+package parser
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArrayIndexMode is the handling of the indexes missing from the arrays (ex: tags.0 & tags.2 without tags.1)
+type ArrayIndexMode int
+
+// Array index modes
+// ArrayIndexesContiguous reads the arrays up to their first missing index. This is the default mode
+// ArrayIndexesCompact reads all the indexes of the arrays in order, without the missing ones
+// ArrayIndexesSparse keeps the elements at their index, with nil for the missing & empty elements. The arrays of values are then []interface{}. Arrays over 1024 elements long & 16 times as long as their number of elements are invalid
+const (
+	ArrayIndexesContiguous ArrayIndexMode = iota
+	ArrayIndexesCompact
+	ArrayIndexesSparse
+)
+
+// splitArrayKey splits an array type column name at its index position into its key, index & sub key (ex: company.0.name gives company, 0 & name)
+func (c *csv) splitArrayKey(k string) (string, int, string, bool) {
+	if c.keys != nil {
+		if split, ok := c.keys.arrayKeys[k]; ok {
+			return split.key, split.index, split.subKey, split.ok
+		}
+	}
+	split := c.parseArrayKey(k)
+	return split.key, split.index, split.subKey, split.ok
+}
+
+func (c *csv) parseArrayKey(k string) arrayKey {
+	keyParts := c.splitKey(k)
+	indexPos := c.indexPos(k, keyParts)
+
+	if len(keyParts) <= indexPos {
+		return arrayKey{}
+	}
+	index, err := strconv.Atoi(keyParts[indexPos])
+	if err != nil {
+		return arrayKey{}
+	}
+
+	key := strings.Join(keyParts[0:indexPos], c.options.ArrayDelimiter)
+	subKey := strings.Join(keyParts[indexPos+1:], c.options.ArrayDelimiter)
+	return arrayKey{key: key, index: index, subKey: subKey, ok: true}
+}
+
+// splitKey splits a column name into its parts, on the ArrayDelimiter & the ArrayDelimiters
+func (c *csv) splitKey(k string) []string {
+	if c.keys != nil {
+		if parts, ok := c.keys.parts[k]; ok {
+			return parts
+		}
+	}
+	return c.parseKey(k)
+}
+
+func (c *csv) parseKey(k string) []string {
+	if len(c.options.ArrayDelimiters) == 0 {
+		return strings.Split(k, c.options.ArrayDelimiter)
+	}
+
+	var parts []string
+	start := 0
+	for i := 0; i < len(k); {
+		delimiter := c.delimiterAt(k, i)
+		if delimiter == "" {
+			i++
+			continue
+		}
+		parts = append(parts, k[start:i])
+		i += len(delimiter)
+		start = i
+	}
+	return append(parts, k[start:])
+}
+
+// delimiterAt returns the delimiter found at the position i of a column name, the longest one when several match, or "" if none does
+func (c *csv) delimiterAt(k string, i int) string {
+	found := ""
+	if strings.HasPrefix(k[i:], c.options.ArrayDelimiter) {
+		found = c.options.ArrayDelimiter
+	}
+	for _, delimiter := range c.options.ArrayDelimiters {
+		if delimiter != "" && len(delimiter) > len(found) && strings.HasPrefix(k[i:], delimiter) {
+			found = delimiter
+		}
+	}
+	return found
+}
+
+// indexPos returns the index position of a column name: the one of the longest matching prefix of IndexPositions, of the first matching rule of IndexRules, or else IndexPos
+func (c *csv) indexPos(k string, keyParts []string) int {
+	indexPos, longest := c.options.IndexPos, -1
+	for prefix, pos := range c.options.IndexPositions {
+		// The prefix must be whole parts of the column name
+		prefixParts := c.splitKey(prefix)
+		if len(prefixParts) <= len(keyParts) && len(prefixParts) > longest && equalParts(prefixParts, keyParts) {
+			indexPos, longest = pos, len(prefixParts)
+		}
+	}
+	if longest >= 0 {
+		return indexPos
+	}
+
+	for _, rule := range c.options.IndexRules {
+		if rule.Pattern.MatchString(k) {
+			return rule.IndexPos
+		}
+	}
+	return indexPos
+}
+
+// getArrayValues returns the values of the array type keys of the record structure, by key, index & sub key
+func (c *csv) getArrayValues(recordStructure RecordStructure, record map[string]string) map[string]map[int]map[string]string {
+	arrays := make(map[string]map[int]map[string]string)
+	for k, val := range record {
+		key, index, subKey, ok := c.splitArrayKey(k)
+		if !ok || index < 0 || !hasSubKey(recordStructure[key], subKey) {
+			continue
+		}
+
+		if arrays[key] == nil {
+			arrays[key] = make(map[int]map[string]string)
+		}
+		if arrays[key][index] == nil {
+			arrays[key][index] = make(map[string]string)
+		}
+		arrays[key][index][subKey] = val
+	}
+	return arrays
+}
+
+// equalParts checks if the parts of a prefix are the first parts of a column name
+func equalParts(prefixParts []string, keyParts []string) bool {
+	for i, part := range prefixParts {
+		if keyParts[i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+func hasSubKey(subKeys []string, subKey string) bool {
+	for _, rec := range subKeys {
+		if rec == subKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits of the length of the sparse arrays, which is taken from their largest index
+// They are at most minSparseLength elements long, or sparseLengthFactor times as long as their number of elements, so that a single large index doesn't exhaust the memory
+const (
+	minSparseLength    = 1 << 10
+	sparseLengthFactor = 16
+)
+
+// arrayIndexes returns the indexes of the elements of an array in order, as selected by the ArrayIndexes mode
+// Missing indexes of the sparse arrays are -1
+func (c *csv) arrayIndexes(key string, elements map[int]map[string]string) ([]int, error) {
+	var indexes []int
+	switch c.options.ArrayIndexes {
+	case ArrayIndexesCompact:
+		for index := range elements {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+	case ArrayIndexesSparse:
+		length := 0
+		for index := range elements {
+			if index >= length {
+				length = index + 1
+			}
+		}
+		if length > max(minSparseLength, sparseLengthFactor*len(elements)) {
+			return nil, errors.New("Sparse array index too large: " + key + c.options.ArrayDelimiter + strconv.Itoa(length-1))
+		}
+		for index := 0; index < length; index++ {
+			if elements[index] == nil {
+				indexes = append(indexes, -1)
+			} else {
+				indexes = append(indexes, index)
+			}
+		}
+	default:
+		for index := 0; elements[index] != nil; index++ {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
+// buildValues builds an array of values, without the empty values unless the array is sparse
+func (c *csv) buildValues(key string, elements map[int]map[string]string) (interface{}, error) {
+	indexes, err := c.arrayIndexes(key, elements)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.options.ArrayIndexes == ArrayIndexesSparse {
+		keyData := make([]interface{}, len(indexes))
+		for i, index := range indexes {
+			if val := elements[index][""]; val != "" {
+				keyData[i] = val
+			}
+		}
+		return keyData, nil
+	}
+
+	keyData := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		if val := elements[index][""]; val != "" {
+			keyData = append(keyData, val)
+		}
+	}
+	return keyData, nil
+}
+
+// buildObjects builds an array of nested objects, without the empty objects unless the array is sparse
+func (c *csv) buildObjects(indexes []int, elements map[int]map[string]string) ([]map[string]interface{}, error) {
+	var keyData []map[string]interface{}
+	for _, index := range indexes {
+		if isEmpty(elements[index]) {
+			if c.options.ArrayIndexes == ArrayIndexesSparse {
+				keyData = append(keyData, nil)
+			}
+			continue
+		}
+
+		object := make(map[string]interface{}, len(elements[index]))
+		if err := c.setValues(object, elements[index]); err != nil {
+			return nil, err
+		}
+		keyData = append(keyData, object)
+	}
+	return keyData, nil
+}
+
+// isEmpty checks if all the values of an element are empty
+func isEmpty(element map[string]string) bool {
+	for _, v := range element {
+		if v != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexRule is the index position of the column names matching a pattern (ex: {regexp.MustCompile(`^a-b-`), 2})
+type IndexRule struct {
+	Pattern  *regexp.Regexp
+	IndexPos int
+}
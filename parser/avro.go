@@ -0,0 +1,422 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroRecordName is the name of the record type of the derived Avro schemas, which prefixes the names of their nested record types
+const avroRecordName = "Record"
+
+// ToAvro parses CSV into an Avro object container file written to w, with an Avro schema of record type
+// The schema is derived from the parsed records when empty: every field is nullable, & its type is inferred from the kinds of its values (ex: long for KindInt, string by default)
+// The names of the fields are the keys of the records, with underscores in place of the characters Avro names don't allow (ex: unit_price for "unit price")
+func (c *csv) ToAvro(ctx context.Context, csvData []map[string]string, w io.Writer, schema string) error {
+	convertedToMap, err := c.ToMap(ctx, csvData)
+	if err != nil {
+		return err
+	}
+
+	if schema == "" {
+		derived, err := c.avroSchema(convertedToMap)
+		if err != nil {
+			return err
+		}
+		schema = derived
+	}
+
+	var parsedSchema interface{}
+	if err = json.Unmarshal([]byte(schema), &parsedSchema); err != nil {
+		return errors.New("Invalid Avro schema: " + err.Error())
+	}
+
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: w, Schema: schema})
+	if err != nil {
+		return err
+	}
+
+	records := make([]interface{}, 0, len(convertedToMap))
+	for _, recordMap := range convertedToMap {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		record, err := avroNative(parsedSchema, recordMap, "")
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	// An empty block isn't readable, so the container of no records only has its header
+	if len(records) == 0 {
+		return nil
+	}
+	return writer.Append(records)
+}
+
+// avroSchema derives the Avro schema of the parsed records
+func (c *csv) avroSchema(records []map[string]interface{}) (string, error) {
+	values := make([]interface{}, len(records))
+	for i, record := range records {
+		values[i] = record
+	}
+
+	recordType, err := c.avroRecordType(avroRecordName, values)
+	if err != nil {
+		return "", err
+	}
+	schema, err := json.Marshal(recordType)
+	if err != nil {
+		return "", err
+	}
+	return string(schema), nil
+}
+
+// avroRecordType returns the Avro record type of the objects, with the fields of all their keys
+func (c *csv) avroRecordType(name string, objects []interface{}) (map[string]interface{}, error) {
+	// The values of the fields by key
+	fieldValues := make(map[string][]interface{})
+	for _, object := range objects {
+		for key, value := range toObject(object) {
+			fieldValues[key] = append(fieldValues[key], value)
+		}
+	}
+
+	keys := make([]string, 0, len(fieldValues))
+	for key := range fieldValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]interface{}, 0, len(keys))
+	names := make(map[string]string, len(keys))
+	for _, key := range keys {
+		fieldName := avroName(key)
+		if other, ok := names[fieldName]; ok {
+			return nil, errors.New("Conflicting column names: " + other + ", " + key)
+		}
+		names[fieldName] = key
+
+		fieldType, err := c.avroType(name+"_"+fieldName, fieldValues[key])
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, map[string]interface{}{
+			"name":    fieldName,
+			"type":    []interface{}{"null", fieldType},
+			"default": nil,
+		})
+	}
+
+	return map[string]interface{}{
+		"type":   "record",
+		"name":   name,
+		"fields": fields,
+	}, nil
+}
+
+// avroType returns the Avro type of the values of a field, from its first non nil value. Record types are named after name
+func (c *csv) avroType(name string, values []interface{}) (interface{}, error) {
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			return "string", nil
+		case int64:
+			return "long", nil
+		case float64:
+			return "double", nil
+		case bool:
+			return "boolean", nil
+		case time.Time:
+			return map[string]interface{}{"type": "long", "logicalType": "timestamp-millis"}, nil
+		case map[string]interface{}, map[string]string:
+			return c.avroRecordType(name, values)
+		case []string, []interface{}, []map[string]string, []map[string]interface{}:
+			// The items of all the arrays
+			var items []interface{}
+			for _, array := range values {
+				items = append(items, toArray(array)...)
+			}
+			itemType, err := c.avroType(name, items)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{"type": "array", "items": []interface{}{"null", itemType}}, nil
+		default:
+			return nil, errors.New("Unsupported Avro value: " + string(mustJSON(v)))
+		}
+	}
+	// The fields without values are strings
+	return "string", nil
+}
+
+// avroNative converts a value of the parsed records to the native Go form of the goavro codecs, for an Avro type
+// namespace is the namespace enclosing the type
+func avroNative(schema interface{}, value interface{}, namespace string) (interface{}, error) {
+	switch s := schema.(type) {
+	case string:
+		return avroPrimitive(s, value)
+	case []interface{}:
+		// Unions take the first type accepting the value, or null for the nil & empty values
+		var err error
+		nullable := false
+		for _, branch := range s {
+			if branch == "null" {
+				nullable = true
+				continue
+			}
+			var native interface{}
+			if native, err = avroNative(branch, value, namespace); err == nil {
+				return goavro.Union(avroTypeName(branch, namespace), native), nil
+			}
+		}
+		if nullable && (value == nil || value == "") {
+			return nil, nil
+		}
+		if err == nil {
+			err = errors.New("Invalid Avro value for a null type")
+		}
+		return nil, err
+	case map[string]interface{}:
+		if logicalType, ok := s["logicalType"].(string); ok && (logicalType == "timestamp-millis" || logicalType == "timestamp-micros") {
+			return avroTime(value)
+		}
+
+		typeName, _ := s["type"].(string)
+		switch typeName {
+		case "record":
+			if ns, ok := s["namespace"].(string); ok {
+				namespace = ns
+			}
+			object := toObject(value)
+			if object == nil {
+				return nil, errors.New("Invalid Avro record value")
+			}
+			// The keys of the values are matched by Avro name
+			byName := make(map[string]interface{}, len(object))
+			for key, v := range object {
+				byName[avroName(key)] = v
+			}
+
+			fields, _ := s["fields"].([]interface{})
+			record := make(map[string]interface{}, len(fields))
+			for _, f := range fields {
+				field, _ := f.(map[string]interface{})
+				fieldName, _ := field["name"].(string)
+				v, ok := byName[fieldName]
+				if !ok {
+					// The missing fields take their default value
+					continue
+				}
+				native, err := avroNative(field["type"], v, namespace)
+				if err != nil {
+					return nil, errors.New("Invalid Avro value of " + fieldName + ": " + err.Error())
+				}
+				record[fieldName] = native
+			}
+			return record, nil
+		case "array":
+			if value == nil {
+				return nil, errors.New("Invalid Avro array value")
+			}
+			items := toArray(value)
+			natives := make([]interface{}, len(items))
+			for i, item := range items {
+				native, err := avroNative(s["items"], item, namespace)
+				if err != nil {
+					return nil, err
+				}
+				natives[i] = native
+			}
+			return natives, nil
+		case "map":
+			object := toObject(value)
+			if object == nil {
+				return nil, errors.New("Invalid Avro map value")
+			}
+			natives := make(map[string]interface{}, len(object))
+			for key, v := range object {
+				native, err := avroNative(s["values"], v, namespace)
+				if err != nil {
+					return nil, err
+				}
+				natives[key] = native
+			}
+			return natives, nil
+		case "enum":
+			return avroPrimitive("string", value)
+		}
+		return avroNative(s["type"], value, namespace)
+	}
+	return nil, errors.New("Unsupported Avro type: " + string(mustJSON(schema)))
+}
+
+// avroPrimitive converts a value to a primitive Avro type. The strings are parsed for the other types
+func avroPrimitive(typeName string, value interface{}) (interface{}, error) {
+	text, isString := value.(string)
+	switch typeName {
+	case "null":
+		if value == nil {
+			return nil, nil
+		}
+	case "string":
+		if isString {
+			return text, nil
+		}
+	case "bytes":
+		if isString {
+			return []byte(text), nil
+		}
+	case "boolean":
+		if isString {
+			return strconv.ParseBool(text)
+		}
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "int", "long":
+		if isString {
+			return strconv.ParseInt(text, 10, 64)
+		}
+		if v, ok := value.(int64); ok {
+			return v, nil
+		}
+	case "float", "double":
+		if isString {
+			return strconv.ParseFloat(text, 64)
+		}
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		}
+	default:
+		return nil, errors.New("Unsupported Avro type: " + typeName)
+	}
+	return nil, errors.New("Invalid Avro " + typeName + " value: " + string(mustJSON(value)))
+}
+
+// avroTime converts a value to a time of a timestamp logical type
+func avroTime(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	}
+	return nil, errors.New("Invalid Avro timestamp value: " + string(mustJSON(value)))
+}
+
+// avroTypeName returns the name of a type in the unions, in the namespace enclosing it
+func avroTypeName(schema interface{}, namespace string) string {
+	switch s := schema.(type) {
+	case string:
+		return s
+	case map[string]interface{}:
+		typeName, _ := s["type"].(string)
+		if logicalType, ok := s["logicalType"].(string); ok {
+			return typeName + "." + logicalType
+		}
+		name, ok := s["name"].(string)
+		if !ok || strings.Contains(name, ".") {
+			if ok {
+				return name
+			}
+			return typeName
+		}
+		if ns, ok := s["namespace"].(string); ok {
+			namespace = ns
+		}
+		if namespace != "" {
+			return namespace + "." + name
+		}
+		return name
+	}
+	return ""
+}
+
+// avroName turns a key into a valid Avro name, replacing the invalid characters with underscores
+func avroName(key string) string {
+	var name strings.Builder
+	for i, r := range key {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || r == '_'):
+		case i > 0 && r < unicode.MaxASCII && unicode.IsDigit(r):
+		case i == 0 && r < unicode.MaxASCII && unicode.IsDigit(r):
+			name.WriteRune('_')
+		default:
+			r = '_'
+		}
+		name.WriteRune(r)
+	}
+	if name.Len() == 0 {
+		return "_"
+	}
+	return name.String()
+}
+
+// toObject returns the keys & values of an object of the parsed records, or nil if the value isn't an object
+func toObject(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case map[string]string:
+		object := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			object[key] = val
+		}
+		return object
+	}
+	return nil
+}
+
+// toArray returns the elements of an array of the parsed records, or nil if the value isn't an array
+func toArray(value interface{}) []interface{} {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case []map[string]string:
+		for _, item := range v {
+			if item == nil {
+				items = append(items, nil)
+				continue
+			}
+			items = append(items, item)
+		}
+	case []map[string]interface{}:
+		for _, item := range v {
+			if item == nil {
+				items = append(items, nil)
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mustJSON returns the JSON of a value for the error messages
+func mustJSON(value interface{}) []byte {
+	text, err := json.Marshal(value)
+	if err != nil {
+		return []byte("?")
+	}
+	return text
+}
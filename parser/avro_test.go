@@ -0,0 +1,28 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+func TestToAvroEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCSV(CSVOptions{}).ToAvro(context.Background(), []map[string]string{}, &buf, ""); err != nil {
+		t.Fatalf("ToAvro() error = %v", err)
+	}
+
+	reader, err := goavro.NewOCFReader(&buf)
+	if err != nil {
+		t.Fatalf("NewOCFReader() error = %v", err)
+	}
+	for reader.Scan() {
+		record, err := reader.Read()
+		t.Errorf("Read() = %v, %v, want no records", record, err)
+	}
+	if err = reader.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+}
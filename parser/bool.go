@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+)
+
+// Default boolean values, used when neither TrueValues nor FalseValues are set
+var (
+	defaultTrueValues  = []string{"true", "t", "yes", "y", "1", "on"}
+	defaultFalseValues = []string{"false", "f", "no", "n", "0", "off"}
+)
+
+// parseBool parses a boolean value from the TrueValues & FalseValues, ignoring their case
+func (c *csv) parseBool(value string) (bool, error) {
+	trueValues, falseValues := c.options.TrueValues, c.options.FalseValues
+	if len(trueValues) == 0 && len(falseValues) == 0 {
+		trueValues, falseValues = defaultTrueValues, defaultFalseValues
+	}
+
+	value = strings.TrimSpace(value)
+	if hasName(trueValues, value) {
+		return true, nil
+	}
+	if hasName(falseValues, value) {
+		return false, nil
+	}
+	return false, errors.New("Invalid boolean: " + value)
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Kind is the type a column is coerced to
+type Kind string
+
+// Kinds
+// KindInt values are int64, KindFloat values float64, KindBool values bool, KindDate values time.Time parsed with the TimeLayouts (default layouts are 2006-01-02 & RFC3339) & KindString values are kept as they are
+const (
+	KindInt    Kind = "int"
+	KindFloat  Kind = "float"
+	KindBool   Kind = "bool"
+	KindDate   Kind = "date"
+	KindString Kind = "string"
+)
+
+// coerceRecord validates the values of the record map with the rules & the enumerations, replaces the enumerated values with their canonical values & coerces them to the kinds of their columns, & represents their empty values as selected by the EmptyValues mode
+// The values which are invalid or can't be coerced are returned as ParseErrors
+func (c *csv) coerceRecord(recordMap map[string]interface{}) error {
+	if len(c.options.ColumnKinds) == 0 && len(c.options.Rules) == 0 && len(c.options.Enums) == 0 && c.options.EmptyValues == EmptyKeep {
+		return nil
+	}
+
+	var errs ParseErrors
+	for key, val := range recordMap {
+		coerced := c.coerce(val, key, &errs)
+		if _, ok := coerced.(omitted); ok {
+			delete(recordMap, key)
+			continue
+		}
+		recordMap[key] = coerced
+	}
+	if len(errs) > 0 {
+		// The map keys are unordered, so the errors are sorted by column
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Column < errs[j].Column })
+		return errs
+	}
+	return nil
+}
+
+// coerce coerces a value of the record map & the values nested in it, by the path of their column name without its indexes (ex: company.name for company.0.name)
+// The arrays & objects of strings are converted to arrays & objects of values
+func (c *csv) coerce(val interface{}, path string, errs *ParseErrors) interface{} {
+	switch v := val.(type) {
+	case string:
+		if err := c.validate(v, path); err != nil {
+			*errs = append(*errs, err)
+			return v
+		}
+		kind := c.options.ColumnKinds[path]
+		if v == "" {
+			return c.emptyValue(kind)
+		}
+		if enum, ok := c.options.Enums[path]; ok {
+			canonical, err := c.enumValue(v, path, enum)
+			if err != nil {
+				*errs = append(*errs, err)
+				return v
+			}
+			// The codes aren't coerced
+			if v, ok = canonical.(string); !ok {
+				return canonical
+			}
+		}
+		if kind == "" {
+			return v
+		}
+		coerced, err := c.coerceValue(v, path, kind)
+		if err != nil {
+			*errs = append(*errs, err)
+		}
+		return coerced
+	case []string:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = value
+		}
+		return c.coerce(values, path, errs)
+	case []interface{}:
+		for i, value := range v {
+			coerced := c.coerce(value, path, errs)
+			// The elements of the arrays keep their index
+			if _, ok := coerced.(omitted); ok {
+				coerced = nil
+			}
+			v[i] = coerced
+		}
+		return v
+	case map[string]string:
+		object := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			object[key] = value
+		}
+		return c.coerce(object, path, errs)
+	case map[string]interface{}:
+		for key, value := range v {
+			coerced := c.coerce(value, path+c.options.ArrayDelimiter+key, errs)
+			if _, ok := coerced.(omitted); ok {
+				delete(v, key)
+				continue
+			}
+			v[key] = coerced
+		}
+		return v
+	case []map[string]string:
+		objects := make([]map[string]interface{}, len(v))
+		for i, value := range v {
+			if value != nil {
+				objects[i] = c.coerce(value, path, errs).(map[string]interface{})
+			}
+		}
+		return objects
+	case []map[string]interface{}:
+		for _, value := range v {
+			c.coerce(value, path, errs)
+		}
+		return v
+	}
+	return val
+}
+
+// coerceValue converts a non empty value to a kind
+func (c *csv) coerceValue(value string, column string, kind Kind) (interface{}, *ParseError) {
+	var coerced interface{}
+	var err error
+	switch kind {
+	case KindString:
+		return value, nil
+	case KindInt:
+		coerced, err = strconv.ParseInt(c.normalizeNumber(value), 10, 64)
+	case KindFloat:
+		coerced, err = strconv.ParseFloat(c.normalizeNumber(value), 64)
+	case KindBool:
+		coerced, err = c.parseBool(value)
+	case KindDate:
+		coerced, err = c.parseTime(value, []string{time.DateOnly, time.RFC3339})
+	default:
+		err = errors.New("Unknown kind: " + string(kind))
+	}
+	if err != nil {
+		c.options.Logger.Warn("Coercion failed", "column", column, "value", value, "kind", string(kind), "error", err)
+		return nil, &ParseError{Column: column, Value: value, Reason: "Invalid " + string(kind) + " value", Err: err}
+	}
+	return coerced, nil
+}
@@ -3,8 +3,10 @@ package parser
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
 	"reflect"
-	"strconv"
 	"strings"
 	"time"
 
@@ -12,142 +14,272 @@ import (
 )
 
 // CSVOptions consists of the parser options available
+// ColumnAliases renames the columns before they are parsed, so that the keys differ from the column names (ex: map[string]string{"Item SKU 1": "items.0.sku", "E-mail": "email"}). The other options refer to the columns by their alias
 // ArrayDelimiter is the delimiter for array type column names. Default value is "."
+// ArrayDelimiters are the other delimiters accepted in the column names, for headers mixing naming styles (ex: []string{"."} with the ArrayDelimiter "-" for items-0-sku & meta.notes). Keys built from several parts are joined with the ArrayDelimiter
 // IndexPos is the position of the index (0-indexed) in array type column names. This can't be at the end or starting of the column name. Default value is 1
 // Ex:
 //		company-0-name is a valid column name but company-name-0 is not
 //		In the case of `company-0-name`, the arrayDelimiter will be `-` & indexPos will be `1`
+// IndexPositions overrides the IndexPos of the column names starting with a prefix, made of whole parts of the names (ex: map[string]int{"a-b": 2} for a-b-0-c). The longest matching prefix applies
+// IndexRules overrides the IndexPos of the column names matching a pattern, when no prefix of IndexPositions matches. The first matching rule applies
 // StructTag is the tag of the struct for struct mapping. Default value is `json`
+// NestObjects splits the column names on the ArrayDelimiter into nested objects (ex: company.address.city gives {"company": {"address": {"city": ...}}}), including the keys of the objects of arrays. By default, the column names are kept as they are
+// ArrayIndexes is the handling of the indexes missing from the arrays. See ArrayIndexMode. Default value is ArrayIndexesContiguous
+// NestArrays reads the indexes found past the IndexPos as nested arrays, at any depth (ex: orders.0.items.2.sku gives {"orders": [{"items": [..., {"sku": ...}]}]}). By default, they are part of the keys
+// Mapping routes columns to output paths, bypassing the naming conventions of the column names (ex: map[string]string{"billing street": "address.billing.street", "item sku 1": "items[0].sku"}). The mapped columns are left out of the other keys
+// ColumnKinds coerces the values of columns to kinds in the parsed records, by column name without its indexes (ex: map[string]Kind{"age": KindInt, "company.founded": KindDate} for age & company.0.founded). Empty values become nil. By default, the values are strings
+// TimeLayouts are the layouts of the time values, tried in order, for the time.Time fields of ToStruct & the KindDate columns (ex: []string{"2006-01-02", "01/02/2006 15:04", LayoutUnix}). Default value is RFC3339 for the fields
+// ParseDurations decodes the strings into the time.Duration fields of ToStruct, either as Go durations (ex: 1h30m, 90s) or as bare seconds (ex: 90, 1.5)
+// DecimalSeparator & ThousandsSeparator are the separators of the numbers of the KindInt & KindFloat columns and of the numeric fields of ToStruct (ex: ',' & '.' for 1.234,56). Default values are '.' & none, with which the numeric fields are only decoded from numbers
+// ParseCurrency decodes the money values (ex: "$1,299.00", "EUR 45,10") into the Money & numeric fields of ToStruct, which get their amount. See Money
+// Transformers are the chains of transformers normalizing the values of columns before the null values, defaults, rules & kinds apply, by column name or column name without its indexes (ex: map[string][]Transformer{"sku": {Trim, Upper}, "price": {StripCurrency}}). See Transformer
+// NullValues are the values read as empty values (ex: []string{"NULL", "N/A", "-"})
+// Defaults are the values of the empty & null cells, by column name (ex: map[string]string{"country": "US", "items.0.qty": "1"}). They are coerced like the values of the cells
+// EmptyValues is the representation of the empty values. See EmptyMode. Default value is EmptyKeep
+// ErrorUnused fails the struct mapping of the columns without fields, & the parsing of the columns without ColumnKinds when they are set
+// ErrorUnset fails the struct mapping of the fields without columns, & the parsing of the ColumnKinds without columns when they are set
+// Rules are the validation rules of the values of columns, by column name without its indexes like the ColumnKinds. The invalid values are returned as ParseErrors. See Rule
+// JSONIndent is the indentation of the nested values of ToJSON (ex: "  "). By default, the JSON is on a single line
+// JSONNoEscapeHTML keeps the characters <, > and & as they are in the JSON strings of ToJSON & ToNDJSON, instead of escaping them
+// JSONRoot wraps the array of records of ToJSON in an object, under the JSONRoot key (ex: {"records": [...]})
+// DerivedColumns are the keys computed from the parsed values of the records, in order (ex: a total from the qty & price). See DerivedColumn
+// IncludeKeys are the only keys kept in the parsed records, if any, & ExcludeKeys the keys removed from them, by path without indexes like the ColumnKinds (ex: []string{"ssn", "company.tax_id"}). The keys of an included or excluded key are too
+// Headers are the column names in the order of the CSV (ex: the header row). When set, the keys of the JSON of ToJSON & ToNDJSON are in the order of their first column instead of sorted by name
+// FieldMatching is the matching of the keys with the fields of the structs of ToStruct. See FieldMatchMode. Default value is FieldMatchExact
+// FieldMatches collects the keys matched with fields which don't have their name, if set
+// SquashEmbedded decodes the fields of the embedded structs of ToStruct from the columns of the record, like the squashed structs (ex: the street column into the Street field of an embedded Address), if true
+// DynamicColumns collects the columns which aren't fields of the structs of ToStruct into their map fields, by field name & column prefix (ex: {"attributes": "attr_"} collects attr_color into attributes["color"]). The "" prefix collects all of them
+// TrueValues & FalseValues are the values of the booleans of KindBool & of the bool fields of ToStruct, ignoring their case. Default values are true, t, yes, y, 1 & on, and false, f, no, n, 0 & off
+// Enums are the enumerations of the columns, by column name without indexes. See Enum
+// Quotes is the cleanup of the double quotes of the values. See QuoteMode. Default value is QuotesRemove
+// KeepInput leaves the records of csvData unmodified, instead of cleaning up their quotes in place, if true. Their quotes are cleaned up in copies, which costs an allocation per record
+// Workers is the number of goroutines converting the records of ToMap & of the conversions based on it, when above 1. The records keep their order
+// The Transformers, the Func of the Rules & the DerivedColumns are then called concurrently, so they must be safe for concurrent use
+// Mode is the handling of the records with invalid cells. See ParseMode. Default value is ParseStrict
+// Report collects the invalid cells of the records skipped in the ParseLenient mode, if set
+// Location is the time zone of the time values without one. Default value is UTC
+// Logger receives the warning events of the parsing (ex: coercion failures). Disabled by default
 type CSVOptions struct {
-	ArrayDelimiter string
-	IndexPos       int
-	StructTag      string
+	ColumnAliases      map[string]string
+	ArrayDelimiter     string
+	ArrayDelimiters    []string
+	IndexPos           int
+	IndexPositions     map[string]int
+	IndexRules         []IndexRule
+	StructTag          string
+	Logger             *slog.Logger
+	NestObjects        bool
+	NestArrays         bool
+	ArrayIndexes       ArrayIndexMode
+	Mapping            map[string]string
+	ColumnKinds        map[string]Kind
+	TimeLayouts        []string
+	Location           *time.Location
+	ParseDurations     bool
+	DecimalSeparator   rune
+	ThousandsSeparator rune
+	ParseCurrency      bool
+	Transformers       map[string][]Transformer
+	NullValues         []string
+	Defaults           map[string]string
+	EmptyValues        EmptyMode
+	ErrorUnused        bool
+	ErrorUnset         bool
+	Rules              map[string]Rule
+	JSONIndent         string
+	JSONNoEscapeHTML   bool
+	JSONRoot           string
+	DerivedColumns     []DerivedColumn
+	IncludeKeys        []string
+	ExcludeKeys        []string
+	Headers            []string
+	FieldMatching      FieldMatchMode
+	FieldMatches       *FieldMatches
+	SquashEmbedded     bool
+	DynamicColumns     map[string]string
+	TrueValues         []string
+	FalseValues        []string
+	Enums              map[string]Enum
+	Quotes             QuoteMode
+	KeepInput          bool
+	Workers            int
+	Mode               ParseMode
+	Report             *ParseReport
 }
 
+// RecordStructure is the structure of the records of a CSV, by key. Single valued keys have no sub keys, arrays have an empty sub key & arrays of objects have the keys of their objects
+// Ex: {"name": [], "tags": [""], "company": ["name", "city"]} for the columns name, tags.0, company.0.name & company.0.city
+type RecordStructure map[string][]string
+
 // CSV is the interface the for csv parser
 type CSV interface {
 	ToMap(ctx context.Context, csvData []map[string]string) ([]map[string]interface{}, error)
 	ToJSON(ctx context.Context, csvData []map[string]string) (string, error)
+	ToNDJSON(ctx context.Context, csvData []map[string]string, w io.Writer) error
+	ToXML(ctx context.Context, csvData []map[string]string, rootElement string, recordElement string) (string, error)
+	ToAvro(ctx context.Context, csvData []map[string]string, w io.Writer, schema string) error
+	ToParquet(ctx context.Context, csvData []map[string]string, w io.Writer, schema string) error
 	ToStruct(ctx context.Context, csvData []map[string]string, res interface{}) error
+	Structure(ctx context.Context, example map[string]string) (RecordStructure, error)
+	ParseRecord(ctx context.Context, structure RecordStructure, record map[string]string) (map[string]interface{}, error)
 }
 
 type csv struct {
 	options CSVOptions
+	// keys is the parsing of the column names of the structure being parsed, if any
+	keys *structureKeys
 }
 
 // ToMap parses CSV into a map
 func (c *csv) ToMap(ctx context.Context, csvData []map[string]string) ([]map[string]interface{}, error) {
+	res, _, err := c.toMap(ctx, csvData)
+	return res, err
+}
+
+// toMap parses CSV into a map, along with the rows of the parsed records in the CSV data
+func (c *csv) toMap(ctx context.Context, csvData []map[string]string) ([]map[string]interface{}, []int, error) {
 	var res []map[string]interface{}
+	var rows []int
 
-	for _, record := range csvData {
+	// The CSV data without records has no structure
+	if len(csvData) == 0 {
+		return []map[string]interface{}{}, rows, nil
+	}
 
-		// Cleanup quotes in the record values
-		for k, v := range record {
-			record[k] = strings.Replace(v, "\"", "", -1)
+	if !c.options.KeepInput {
+		for _, record := range csvData {
+			if err := ctx.Err(); err != nil {
+				return res, rows, err
+			}
+
+			// Cleanup quotes in the record values
+			for k, v := range record {
+				record[k] = c.cleanQuotes(v)
+			}
 		}
 	}
 
 	recordStructure, err := c.getCSVStructure(ctx, csvData[0])
 	if err != nil {
-		return res, err
+		return res, rows, err
 	}
 
 	// Create the map
-	for _, record := range csvData {
-
-		recordMap, err := c.recordToMap(ctx, recordStructure, record)
+	// The invalid cells of all the records are returned together, unless the records are skipped
+	recordMaps, errs := c.withKeys(csvData[0], recordStructure).convertRecords(ctx, recordStructure, csvData)
+	var parseErrs ParseErrors
+	for i, err := range errs {
+		if c.skipRecord(err) {
+			continue
+		}
+		var recordErrs ParseErrors
+		if errors.As(err, &recordErrs) {
+			parseErrs = append(parseErrs, recordErrs...)
+			continue
+		}
 		if err != nil {
-			return res, err
+			return res, rows, err
 		}
-		res = append(res, recordMap)
+		res = append(res, recordMaps[i])
+		rows = append(rows, i)
+	}
+	if len(parseErrs) > 0 {
+		return res, rows, parseErrs
 	}
 
-	return res, nil
+	return res, rows, nil
 }
 
-func (c *csv) getCSVStructure(ctx context.Context, example map[string]string) (map[string][]string, error) {
-	recordStructure := map[string][]string{}
+// Structure returns the structure of the records of a CSV from one of its records, which can be used to parse its records one at a time with ParseRecord
+func (c *csv) Structure(ctx context.Context, example map[string]string) (RecordStructure, error) {
+	return c.getCSVStructure(ctx, example)
+}
+
+// ParseRecord parses a single record of a CSV into a map, with the structure of the CSV. The record isn't modified
+func (c *csv) ParseRecord(ctx context.Context, structure RecordStructure, record map[string]string) (map[string]interface{}, error) {
+	return c.recordToMap(ctx, structure, c.cleanRecord(record))
+}
 
-	indexPos := c.options.IndexPos
+func (c *csv) getCSVStructure(ctx context.Context, example map[string]string) (RecordStructure, error) {
+	recordStructure := RecordStructure{}
+
+	example, err := c.aliasColumns(example)
+	if err != nil {
+		return nil, err
+	}
 
 	for k := range example {
-		keyParts := strings.Split(k, c.options.ArrayDelimiter)
+		// Mapped columns are set at their path
+		if _, ok := c.options.Mapping[k]; ok {
+			continue
+		}
 
-		if len(keyParts) <= indexPos {
+		// Check if it is an array type record
+		key, _, subKey, ok := c.splitArrayKey(k)
+		if !ok {
 			// It is a single valued record
 			recordStructure[k] = []string{}
 			continue
 		}
 
-		// Check if it is an array type record
-		_, err := strconv.Atoi(keyParts[indexPos])
-		if err == nil {
-			// It is an array type record
-			key := strings.Join(keyParts[0:indexPos], c.options.ArrayDelimiter)
-			subKey := strings.Join(keyParts[indexPos+1:len(keyParts)], c.options.ArrayDelimiter)
-
-			// Check if key exists in the record structure
-			_, ok := recordStructure[key]
-			if !ok {
-				recordStructure[key] = []string{subKey}
-			} else {
-				// Append the subkey into the key's record structure if it doesn't already have it
-				isSubkeyPresent := false
-				for _, rec := range recordStructure[key] {
-					if rec == subKey {
-						isSubkeyPresent = true
-						break
-					}
-				}
-				if !isSubkeyPresent {
-					recordStructure[key] = append(recordStructure[key], subKey)
+		// Check if key exists in the record structure
+		_, ok = recordStructure[key]
+		if !ok {
+			recordStructure[key] = []string{subKey}
+		} else {
+			// Append the subkey into the key's record structure if it doesn't already have it
+			isSubkeyPresent := false
+			for _, rec := range recordStructure[key] {
+				if rec == subKey {
+					isSubkeyPresent = true
+					break
 				}
 			}
-		} else {
-			recordStructure[k] = []string{}
+			if !isSubkeyPresent {
+				recordStructure[key] = append(recordStructure[key], subKey)
+			}
 		}
 	}
 
-	return recordStructure, nil
+	return recordStructure, c.checkColumns(recordStructure)
 
 }
 
-func (c *csv) recordToMap(ctx context.Context, recordStructure map[string][]string, record map[string]string) (map[string]interface{}, error) {
-	recordMap := make(map[string]interface{})
+func (c *csv) recordToMap(ctx context.Context, recordStructure RecordStructure, record map[string]string) (map[string]interface{}, error) {
+	recordMap := make(map[string]interface{}, len(recordStructure))
+	record, err := c.aliasColumns(record)
+	if err != nil {
+		return nil, err
+	}
+	record = c.cleanValues(record)
 
 	// Add Single valued keys
+	singleValued := make(map[string]string, len(recordStructure))
 	for key, subKeys := range recordStructure {
 		if len(subKeys) != 0 {
 			continue
 		}
 		// This is a single valued key
-		recordMap[key] = record[key]
+		singleValued[key] = record[key]
 	}
+	if err := c.setValues(recordMap, singleValued); err != nil {
+		return nil, err
+	}
+
+	arrays := c.getArrayValues(recordStructure, record)
 
 	// Add array based keys
 	for key, subKeys := range recordStructure {
 		// All the array based keys will have just one element which is an empty string in the record structure
 		if len(subKeys) == 1 && subKeys[0] == "" {
-			keyData := make([]string, 0)
-
-			run := true
-			index := 0
-			for run == true {
-				recordKey := strings.Join([]string{key, strconv.Itoa(index)}, c.options.ArrayDelimiter)
-
-				val, ok := record[recordKey]
-				if !ok {
-					// We have reached the end index for this key
-					run = false
-					break
-				}
-				if val != "" {
-					keyData = append(keyData, val)
-				}
-				index++
+			keyData, err := c.buildValues(key, arrays[key])
+			if err != nil {
+				return nil, err
+			}
+			if err := c.setValue(recordMap, key, keyData); err != nil {
+				return nil, err
 			}
-
-			recordMap[key] = keyData
 		}
 	}
 
@@ -160,66 +292,54 @@ func (c *csv) recordToMap(ctx context.Context, recordStructure map[string][]stri
 			continue
 		}
 
-		// Find the length of slice for the key
-		length := 0
-		run := true
-		subKey := subKeys[0]
-		for run == true {
-			recordKey := strings.Join([]string{key, strconv.Itoa(length), subKey}, c.options.ArrayDelimiter)
-
-			_, ok := record[recordKey]
-			if !ok {
-				// We have reached the end index for this key & subkey combination
-				run = false
-			} else {
-				length++
-			}
+		elements := arrays[key]
+		indexes, err := c.arrayIndexes(key, elements)
+		if err != nil {
+			return nil, err
 		}
 
-		// Handle array type keys
-		keyData := make([]map[string]string, length)
-		for _, subKey := range subKeys {
-
-			run := true
-			index := 0
-			for run == true {
-				recordKey := strings.Join([]string{key, strconv.Itoa(index), subKey}, c.options.ArrayDelimiter)
-
-				val, ok := record[recordKey]
-				if !ok {
-					// We have reached the end index for this key & subkey combination
-					run = false
-					break
-				}
-				if len(keyData[index]) == 0 {
-					keyData[index] = make(map[string]string, len(subKeys))
+		if !c.options.NestObjects && !c.options.NestArrays {
+			var keyData []map[string]string
+			for _, index := range indexes {
+				// Clean up empty values from the map
+				// This will remove all the values in the key array which has empty values
+				if isEmpty(elements[index]) {
+					if c.options.ArrayIndexes == ArrayIndexesSparse {
+						keyData = append(keyData, nil)
+					}
+					continue
 				}
+				keyData = append(keyData, elements[index])
+			}
 
-				keyData[index][subKey] = val
-				index++
+			if err := c.setValue(recordMap, key, keyData); err != nil {
+				return nil, err
 			}
+			continue
 		}
 
-		var sanitizedKeyData []map[string]string
-		// Clean up empty values from the map
-		// This will remove all the values in the key array which has empty values
-		for _, data := range keyData {
-			isMapEmpty := true
+		// The sub keys of the objects are nested too
+		keyData, err := c.buildObjects(indexes, elements)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.setValue(recordMap, key, keyData); err != nil {
+			return nil, err
+		}
+	}
 
-			for _, v := range data {
-				if v != "" {
-					isMapEmpty = false
-					break
-				}
-			}
+	if err := c.setMappedValues(recordMap, record); err != nil {
+		return nil, err
+	}
 
-			if !isMapEmpty {
-				sanitizedKeyData = append(sanitizedKeyData, data)
-			}
-		}
+	if err := c.coerceRecord(recordMap); err != nil {
+		return nil, err
+	}
 
-		recordMap[key] = sanitizedKeyData
+	if err := c.setDerivedValues(recordMap); err != nil {
+		return nil, err
 	}
+	c.filterKeys(recordMap)
 
 	return recordMap, nil
 }
@@ -231,35 +351,101 @@ func (c *csv) ToJSON(ctx context.Context, csvData []map[string]string) (string,
 		return "", err
 	}
 
-	convertedToJSON, err := json.Marshal(convertedToMap)
-	if err != nil {
+	var converted interface{} = convertedToMap
+	if len(c.options.Headers) > 0 {
+		converted = c.ordered(convertedToMap, "", c.columnOrder())
+	}
+	if c.options.JSONRoot != "" {
+		converted = map[string]interface{}{c.options.JSONRoot: converted}
+	}
+
+	var convertedToJSON strings.Builder
+	encoder := json.NewEncoder(&convertedToJSON)
+	encoder.SetIndent("", c.options.JSONIndent)
+	encoder.SetEscapeHTML(!c.options.JSONNoEscapeHTML)
+	if err = encoder.Encode(converted); err != nil {
 		return "", err
 	}
 
-	return string(convertedToJSON), nil
+	// Encode ends the JSON with a line break
+	return strings.TrimSuffix(convertedToJSON.String(), "\n"), nil
 }
 
-// ToStruct parses CSV into a Struct/Interface
-func (c *csv) ToStruct(ctx context.Context, csvData []map[string]string, res interface{}) error {
-	convertedToMap, err := c.ToMap(ctx, csvData)
+// ToNDJSON parses CSV into newline delimited JSON written to w, one record per line as the records are parsed, so that the JSON is never held in memory
+// The records of csvData aren't modified
+func (c *csv) ToNDJSON(ctx context.Context, csvData []map[string]string, w io.Writer) error {
+	if len(csvData) == 0 {
+		return nil
+	}
+
+	recordStructure, err := c.Structure(ctx, csvData[0])
 	if err != nil {
 		return err
 	}
 
-	stringToDateTimeHook := func(
-		f reflect.Type,
-		t reflect.Type,
-		data interface{}) (interface{}, error) {
-		if t == reflect.TypeOf(time.Time{}) && f == reflect.TypeOf("") {
-			return time.Parse(time.RFC3339, data.(string))
+	parser := c.withKeys(csvData[0], recordStructure)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(!c.options.JSONNoEscapeHTML)
+	var order map[string]int
+	if len(c.options.Headers) > 0 {
+		order = c.columnOrder()
+	}
+	for i, record := range csvData {
+		if err = ctx.Err(); err != nil {
+			return err
 		}
 
-		return data, nil
+		recordMap, err := parser.ParseRecord(ctx, recordStructure, record)
+		err = withRow(err, i)
+		if c.skipRecord(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var converted interface{} = recordMap
+		if order != nil {
+			converted = c.ordered(recordMap, "", order)
+		}
+
+		// Encode writes the line break after each record
+		if err = encoder.Encode(converted); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ToStruct parses CSV into a Struct/Interface
+func (c *csv) ToStruct(ctx context.Context, csvData []map[string]string, res interface{}) error {
+	convertedToMap, rows, err := c.toMap(ctx, csvData)
+	if err != nil {
+		return err
+	}
+
+	if c.options.Mode == ParseLenient {
+		return c.decodeLenient(ctx, convertedToMap, rows, res)
 	}
+	return c.decode(ctx, convertedToMap, res)
+}
+
+// decode maps the parsed records into a Struct/Interface
+func (c *csv) decode(ctx context.Context, input interface{}, res interface{}) error {
 	config := mapstructure.DecoderConfig{
-		DecodeHook: stringToDateTimeHook,
-		Result:     res,
-		TagName:    c.options.StructTag,
+		// The mapping stops once cancelled, which is checked for each struct
+		DecodeHook: func(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+			if t.Kind() == reflect.Struct {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+			return c.decodeHook(f, t, data)
+		},
+		ErrorUnused: c.options.ErrorUnused,
+		Result:      res,
+		TagName:     c.options.StructTag,
 	}
 
 	decoder, err := mapstructure.NewDecoder(&config)
@@ -267,9 +453,13 @@ func (c *csv) ToStruct(ctx context.Context, csvData []map[string]string, res int
 		return err
 	}
 
-	err = decoder.Decode(convertedToMap)
+	err = decoder.Decode(input)
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
 	if err != nil {
-		return err
+		c.options.Logger.Warn("Struct mapping failed", "error", err)
+		return c.decodeErrors(err, input)
 	}
 
 	return nil
@@ -286,6 +476,15 @@ func NewCSV(options CSVOptions) CSV {
 	if options.StructTag == "" {
 		options.StructTag = "json"
 	}
+	if options.DecimalSeparator == 0 {
+		options.DecimalSeparator = '.'
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
 	return &csv{
 		options: options,
 	}
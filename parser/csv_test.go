@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestEmptyInput(t *testing.T) {
+	for _, csvData := range [][]map[string]string{nil, {}} {
+		c := NewCSV(CSVOptions{})
+
+		res, err := c.ToMap(context.Background(), csvData)
+		if err != nil || len(res) != 0 {
+			t.Errorf("ToMap() = %v, %v, want no records", res, err)
+		}
+
+		json, err := c.ToJSON(context.Background(), csvData)
+		if err != nil || json != "[]" {
+			t.Errorf("ToJSON() = %s, %v, want []", json, err)
+		}
+
+		var records []struct{ Name string }
+		if err = c.ToStruct(context.Background(), csvData, &records); err != nil || len(records) != 0 {
+			t.Errorf("ToStruct() = %v, %v, want no records", records, err)
+		}
+	}
+}
+
+func TestSparseArrayIndexes(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   map[string]string
+		options  CSVOptions
+		expected map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "values",
+			record:   map[string]string{"tags.0": "a", "tags.2": "c"},
+			expected: map[string]interface{}{"tags": []interface{}{"a", nil, "c"}},
+		},
+		{
+			name:     "objects",
+			record:   map[string]string{"items.1.sku": "b"},
+			expected: map[string]interface{}{"items": []map[string]string{nil, {"sku": "b"}}},
+		},
+		{
+			name:     "short array",
+			record:   map[string]string{"tags.1000": "a"},
+			expected: map[string]interface{}{"tags": append(make([]interface{}, 1000), "a")},
+		},
+		{
+			name:   "large index",
+			record: map[string]string{"tags.0": "a", "tags.2000000000": "b"},
+			err:    "Sparse array index too large: tags.2000000000",
+		},
+		{
+			name:    "large nested index",
+			record:  map[string]string{"orders.0.items.0.sku": "a", "orders.0.items.2000000000.sku": "b"},
+			options: CSVOptions{NestArrays: true},
+			err:     "Sparse array index too large: items.2000000000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.options.ArrayIndexes = ArrayIndexesSparse
+			c := NewCSV(tt.options)
+
+			res, err := c.ToMap(context.Background(), []map[string]string{tt.record})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("ToMap() error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res[0], tt.expected) {
+				t.Errorf("ToMap() = %v, want %v", res[0], tt.expected)
+			}
+		})
+	}
+}
+
+// wideRecords returns records with single valued columns, arrays & arrays of objects, like a wide export
+func wideRecords(count int) []map[string]string {
+	records := make([]map[string]string, count)
+	for i := range records {
+		record := make(map[string]string)
+		for j := 0; j < 40; j++ {
+			record["field"+strconv.Itoa(j)] = strconv.Itoa(i * j)
+		}
+		for j := 0; j < 10; j++ {
+			for index := 0; index < 5; index++ {
+				record["tags"+strconv.Itoa(j)+"."+strconv.Itoa(index)] = "tag" + strconv.Itoa(index)
+			}
+		}
+		for j := 0; j < 5; j++ {
+			for index := 0; index < 3; index++ {
+				for _, subKey := range []string{"sku", "name", "qty", "price"} {
+					record["items"+strconv.Itoa(j)+"."+strconv.Itoa(index)+"."+subKey] = subKey + strconv.Itoa(i)
+				}
+			}
+		}
+		records[i] = record
+	}
+	return records
+}
+
+func TestToMapWideHeader(t *testing.T) {
+	c := NewCSV(CSVOptions{NestObjects: true})
+	records := wideRecords(3)
+
+	res, err := c.ToMap(context.Background(), records)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The column names parsed once for all the records give the records parsed one at a time
+	structure, err := c.Structure(context.Background(), records[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, record := range records {
+		expected, err := c.ParseRecord(context.Background(), structure, record)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(res[i], expected) {
+			t.Errorf("ToMap()[%d] = %v, want %v", i, res[i], expected)
+		}
+	}
+}
+
+func BenchmarkToMap(b *testing.B) {
+	c := NewCSV(CSVOptions{NestObjects: true, KeepInput: true})
+	records := wideRecords(1000)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := c.ToMap(context.Background(), records); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
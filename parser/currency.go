@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money with its currency code, decoded from the values like "$1,299.00" or "EUR 45,10" when ParseCurrency is set
+// Currency is the ISO 4217 code of the currency, or "" if the value has none
+type Money struct {
+	Amount   float64
+	Currency string
+}
+
+// currencySymbols are the common currency symbols & their currency codes, longest symbols first
+var currencySymbols = []struct {
+	symbol string
+	code   string
+}{
+	{"R$", "BRL"},
+	{"$", "USD"},
+	{"€", "EUR"},
+	{"£", "GBP"},
+	{"¥", "JPY"},
+	{"₹", "INR"},
+	{"₩", "KRW"},
+	{"₽", "RUB"},
+}
+
+// splitCurrency splits a money value into its amount & currency code, which is either a symbol or a 3 letters code, before or after the amount
+func splitCurrency(value string) (string, string) {
+	value = strings.TrimSpace(value)
+
+	// A minus sign may precede the currency (ex: -$5.00)
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", strings.TrimSpace(value[1:])
+	}
+
+	for _, currency := range currencySymbols {
+		if amount, ok := strings.CutPrefix(value, currency.symbol); ok {
+			return sign + strings.TrimSpace(amount), currency.code
+		}
+		if amount, ok := strings.CutSuffix(value, currency.symbol); ok {
+			return sign + strings.TrimSpace(amount), currency.code
+		}
+	}
+
+	if len(value) > 3 && isCurrencyCode(value[:3]) {
+		return sign + strings.TrimSpace(value[3:]), value[:3]
+	}
+	if len(value) > 3 && isCurrencyCode(value[len(value)-3:]) {
+		return sign + strings.TrimSpace(value[:len(value)-3]), value[len(value)-3:]
+	}
+	return sign + value, ""
+}
+
+// isCurrencyCode checks if a string looks like an ISO 4217 code
+func isCurrencyCode(code string) bool {
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMoney parses a money value into its amount & currency code
+// The amount is read with the DecimalSeparator & ThousandsSeparator when either is set. Otherwise, the last '.' or ',' followed by 1 or 2 digits is the decimal separator & the other ones are thousands separators
+func (c *csv) parseMoney(value string) (Money, error) {
+	amount, code := splitCurrency(value)
+	if c.localizedNumbers() {
+		amount = c.normalizeNumber(amount)
+	} else {
+		amount = normalizeAmount(amount)
+	}
+
+	parsed, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return Money{}, errors.New("Invalid money value: " + value)
+	}
+	return Money{Amount: parsed, Currency: code}, nil
+}
+
+// normalizeAmount rewrites an amount with the Go separators, guessing its decimal separator
+func normalizeAmount(amount string) string {
+	decimal := strings.LastIndexAny(amount, ".,")
+	if decimal < 0 || len(amount)-decimal-1 > 2 {
+		decimal = -1
+	}
+
+	var normalized strings.Builder
+	for i, r := range amount {
+		switch {
+		case i == decimal:
+			normalized.WriteRune('.')
+		case r == '.' || r == ',' || r == ' ' || r == '\'':
+		default:
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String()
+}
+
+// convertMoney converts a money value to the type of a struct field: Money, or the amount for the numeric fields
+func (c *csv) convertMoney(value string, t reflect.Type) (interface{}, error) {
+	money, err := c.parseMoney(value)
+	if err != nil {
+		return nil, err
+	}
+	if t == reflect.TypeOf(Money{}) {
+		return money, nil
+	}
+	return money.Amount, nil
+}
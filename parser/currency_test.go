@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSplitCurrency(t *testing.T) {
+	tests := []struct {
+		value    string
+		amount   string
+		currency string
+	}{
+		{value: "$1,299.00", amount: "1,299.00", currency: "USD"},
+		{value: "-$5.00", amount: "-5.00", currency: "USD"},
+		{value: "45,10 €", amount: "45,10", currency: "EUR"},
+		{value: "R$ 10,00", amount: "10,00", currency: "BRL"},
+		{value: " EUR 45,10 ", amount: "45,10", currency: "EUR"},
+		{value: "45.10GBP", amount: "45.10", currency: "GBP"},
+		{value: "12.5", amount: "12.5", currency: ""},
+		{value: "USD", amount: "USD", currency: ""},
+		{value: "usd 12", amount: "usd 12", currency: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			amount, currency := splitCurrency(tt.value)
+			if amount != tt.amount || currency != tt.currency {
+				t.Errorf("splitCurrency() = %q, %q, want %q, %q", amount, currency, tt.amount, tt.currency)
+			}
+		})
+	}
+}
+
+func TestToStructCurrency(t *testing.T) {
+	type record struct {
+		Price  Money
+		Amount float64
+	}
+
+	tests := []struct {
+		name     string
+		options  CSVOptions
+		value    string
+		expected Money
+		err      bool
+	}{
+		{name: "symbol", value: "$1,299.00", expected: Money{Amount: 1299, Currency: "USD"}},
+		{name: "code", value: "EUR 45,10", expected: Money{Amount: 45.1, Currency: "EUR"}},
+		{name: "symbol after the amount", value: "1.234,5 €", expected: Money{Amount: 1234.5, Currency: "EUR"}},
+		{name: "apostrophe thousands", value: "CHF 1'234.50", expected: Money{Amount: 1234.5, Currency: "CHF"}},
+		{name: "negative", value: "-$5", expected: Money{Amount: -5, Currency: "USD"}},
+		// 3 digits after the last separator are thousands
+		{name: "thousands only", value: "1,234", expected: Money{Amount: 1234}},
+		// The separators aren't guessed when they are set
+		{name: "decimal separator", options: CSVOptions{DecimalSeparator: ','}, value: "12,345 €", expected: Money{Amount: 12.345, Currency: "EUR"}},
+		{name: "thousands separator", options: CSVOptions{DecimalSeparator: ',', ThousandsSeparator: '.'}, value: "€1.234,56", expected: Money{Amount: 1234.56, Currency: "EUR"}},
+		{name: "invalid amount", value: "$abc", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := tt.options
+			options.ParseCurrency = true
+			csvData := []map[string]string{{"Price": tt.value, "Amount": tt.value}}
+
+			var records []record
+			err := NewCSV(options).ToStruct(context.Background(), csvData, &records)
+			if tt.err {
+				if err == nil {
+					t.Errorf("ToStruct() = %v, want an error", records)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The numeric fields get the amount
+			if expected := (record{Price: tt.expected, Amount: tt.expected.Amount}); len(records) != 1 || records[0] != expected {
+				t.Errorf("ToStruct() = %v, want %v", records, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package parser
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// isDecimal checks if a type holds exact numbers: big.Int, big.Float, big.Rat or the Decimal of github.com/shopspring/decimal, which is recognized without being imported
+func isDecimal(t reflect.Type) bool {
+	switch t {
+	case reflect.TypeOf(big.Int{}), reflect.TypeOf(big.Float{}), reflect.TypeOf(big.Rat{}):
+		return true
+	}
+	return t.PkgPath() == "github.com/shopspring/decimal" && t.Name() == "Decimal"
+}
+
+// parseDecimal parses a number into an exact numeric type, with the DecimalSeparator & ThousandsSeparator, or as an amount of money with ParseCurrency. Empty values are zero
+func (c *csv) parseDecimal(value string, t reflect.Type) (interface{}, error) {
+	if strings.TrimSpace(value) == "" {
+		return reflect.Zero(t).Interface(), nil
+	}
+
+	number := c.normalizeNumber(value)
+	if c.options.ParseCurrency {
+		amount, _ := splitCurrency(value)
+		if c.localizedNumbers() {
+			number = c.normalizeNumber(amount)
+		} else {
+			number = normalizeAmount(amount)
+		}
+	}
+
+	if t == reflect.TypeOf(big.Float{}) {
+		// The precision fits the digits of the number, instead of the default 64 bits
+		parsed, _, err := big.ParseFloat(number, 10, max(64, uint(len(number))*4), big.ToNearestEven)
+		if err != nil {
+			return nil, errors.New("Invalid decimal: " + value)
+		}
+		return *parsed, nil
+	}
+	return unmarshalValue(number, t)
+}
@@ -0,0 +1,23 @@
+package parser
+
+// DerivedColumn is a key computed from the parsed values of a record (ex: full_name from first_name & last_name)
+// Func receives the record map, with the values coerced to their kinds & the keys of the previous derived columns. Its errors are returned as ParseErrors
+type DerivedColumn struct {
+	Key  string
+	Func func(record map[string]interface{}) (interface{}, error)
+}
+
+// setDerivedValues sets the values of the DerivedColumns in the record map, in order
+func (c *csv) setDerivedValues(recordMap map[string]interface{}) error {
+	for _, derived := range c.options.DerivedColumns {
+		value, err := derived.Func(recordMap)
+		if err != nil {
+			c.options.Logger.Warn("Derived column failed", "column", derived.Key, "error", err)
+			return ParseErrors{{Column: derived.Key, Reason: err.Error(), Err: err}}
+		}
+		if err = c.setValue(recordMap, derived.Key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// collectColumns moves the values of the keys which aren't fields of a struct into its map fields listed in DynamicColumns, without their prefix
+// The longest prefix collects a key when several match
+func (c *csv) collectColumns(t reflect.Type, data interface{}) interface{} {
+	object := toObject(data)
+	if len(c.options.DynamicColumns) == 0 || object == nil {
+		return data
+	}
+
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type.Kind() != reflect.Map {
+			continue
+		}
+		for _, name := range c.structFieldNames(field) {
+			if _, ok := c.options.DynamicColumns[name]; ok {
+				fields = append(fields, name)
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return data
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		prefixI, prefixJ := c.options.DynamicColumns[fields[i]], c.options.DynamicColumns[fields[j]]
+		if len(prefixI) != len(prefixJ) {
+			return len(prefixI) > len(prefixJ)
+		}
+		return fields[i] < fields[j]
+	})
+
+	names := c.fieldNames(t)
+	collected := make(map[string]interface{}, len(object))
+	columns := make(map[string]map[string]interface{}, len(fields))
+	for _, field := range fields {
+		columns[field] = make(map[string]interface{})
+	}
+	for key, value := range object {
+		if hasName(names, key) {
+			collected[key] = value
+			continue
+		}
+		found := false
+		for _, field := range fields {
+			prefix := c.options.DynamicColumns[field]
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			columns[field][strings.TrimPrefix(key, prefix)] = value
+			found = true
+			break
+		}
+		if !found {
+			collected[key] = value
+		}
+	}
+	for _, field := range fields {
+		// A value with the name of the field itself is kept (ex: a nested object)
+		if _, ok := collected[field]; !ok {
+			collected[field] = columns[field]
+		}
+	}
+	return collected
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+)
+
+// embeddedStruct returns the struct type of an embedded field decoded from the record with SquashEmbedded, which has no name in its StructTag
+func (c *csv) embeddedStruct(field reflect.StructField) (reflect.Type, bool) {
+	if !c.options.SquashEmbedded || !field.Anonymous || field.PkgPath != "" {
+		return nil, false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get(c.options.StructTag), ",")
+	if name != "" {
+		return nil, false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t, t.Kind() == reflect.Struct
+}
+
+// squashEmbedded moves the values of the fields of the embedded structs of a struct into objects named after them, so that they are decoded from the record itself
+func (c *csv) squashEmbedded(t reflect.Type, data interface{}) interface{} {
+	object := toObject(data)
+	if !c.options.SquashEmbedded || object == nil {
+		return data
+	}
+
+	// The keys of the fields of the struct itself aren't moved
+	var direct []string
+	type embedded struct {
+		name    string
+		pointer bool
+		fields  []string
+	}
+	var embeds []embedded
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if et, ok := c.embeddedStruct(field); ok {
+			embeds = append(embeds, embedded{field.Name, field.Type.Kind() == reflect.Ptr, c.fieldNames(et)})
+			continue
+		}
+		direct = append(direct, c.structFieldNames(field)...)
+	}
+	if len(embeds) == 0 {
+		return data
+	}
+
+	squashed := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		squashed[key] = value
+	}
+	for _, embed := range embeds {
+		if _, ok := object[embed.name]; ok {
+			continue
+		}
+		values := make(map[string]interface{})
+		empty := true
+		for key, value := range squashed {
+			if hasName(embed.fields, key) && !hasName(direct, key) {
+				values[key] = value
+				delete(squashed, key)
+				empty = empty && (value == nil || value == "")
+			}
+		}
+		// The embedded pointers stay nil when all their values are empty
+		if len(values) > 0 && !(embed.pointer && empty) {
+			squashed[embed.name] = values
+		}
+	}
+	return squashed
+}
+
+// hasName checks if a key is one of the names, ignoring their case like the struct mapping
+func hasName(names []string, key string) bool {
+	for _, name := range names {
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+	return false
+}
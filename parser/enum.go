@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+)
+
+// Enum is the enumeration of the values allowed in a column. The other non empty values are invalid
+// Values are the allowed values, which are kept. Mapping maps other allowed values to their canonical values (ex: {"A": "active"}) or codes (ex: {"active": 1})
+// IgnoreCase allows the values regardless of their case. They are replaced by the Values they match
+type Enum struct {
+	Values     []string
+	Mapping    map[string]interface{}
+	IgnoreCase bool
+}
+
+// enumValue returns the canonical value of a non empty value of an enumerated column
+func (c *csv) enumValue(value string, column string, enum Enum) (interface{}, *ParseError) {
+	for _, allowed := range enum.Values {
+		if value == allowed || (enum.IgnoreCase && strings.EqualFold(value, allowed)) {
+			return allowed, nil
+		}
+	}
+	if mapped, ok := enum.Mapping[value]; ok {
+		return mapped, nil
+	}
+	if enum.IgnoreCase {
+		for allowed, mapped := range enum.Mapping {
+			if strings.EqualFold(value, allowed) {
+				return mapped, nil
+			}
+		}
+	}
+
+	c.options.Logger.Warn("Validation failed", "column", column, "value", value, "reason", "Value not allowed")
+	return nil, &ParseError{Column: column, Value: value, Reason: "Value not allowed, expected one of " + strings.Join(enum.allowed(), ", ")}
+}
+
+// allowed returns the values allowed by an enumeration, the Values first
+func (e Enum) allowed() []string {
+	mapped := make([]string, 0, len(e.Mapping))
+	for value := range e.Mapping {
+		mapped = append(mapped, value)
+	}
+	sort.Strings(mapped)
+	return append(append([]string{}, e.Values...), mapped...)
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ParseError is an invalid cell of a CSV
+// Row is the index (0-indexed) of the record in the CSV data, or in the stream of records. Column is the name of the column, without its indexes for the columns of ColumnKinds
+// Value is the value of the cell, if known, Reason what is wrong with it & Err the underlying error
+type ParseError struct {
+	Row    int
+	Column string
+	Value  string
+	Reason string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return e.Reason + " in row " + strconv.Itoa(e.Row) + ", column " + e.Column + ": " + e.Value
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors are the invalid cells of a CSV, in the order of the records
+// errors.Is & errors.As match each of them
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, parseErr := range e {
+		messages[i] = parseErr.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, parseErr := range e {
+		errs[i] = parseErr
+	}
+	return errs
+}
+
+// withRow sets the row of the ParseErrors of an error
+func withRow(err error, row int) error {
+	var parseErrs ParseErrors
+	if errors.As(err, &parseErrs) {
+		for _, parseErr := range parseErrs {
+			parseErr.Row = row
+		}
+	}
+	return err
+}
+
+// mappingError matches the errors of the struct mapping, with the name of the field & the reason (ex: error decoding '[0].age': ...)
+var mappingError = regexp.MustCompile(`^(?:error decoding )?'([^']*)'[: ] ?(.*)$`)
+
+// mappingIndex matches the indexes of the names of the fields (ex: [0] in [0].company[0].name)
+var mappingIndex = regexp.MustCompile(`\[(\d+)\]`)
+
+// decodeErrors turns the errors of the struct mapping of the input into ParseErrors, with the values of their cells
+// The errors which aren't about a field are returned as they are
+func (c *csv) decodeErrors(err error, input interface{}) error {
+	var mappingErr *mapstructure.Error
+	if !errors.As(err, &mappingErr) {
+		return err
+	}
+
+	var parseErrs ParseErrors
+	for _, message := range mappingErr.Errors {
+		matches := mappingError.FindStringSubmatch(message)
+		if matches == nil || matches[1] == "" {
+			return err
+		}
+
+		// The name of the field is the path of the cell in the input (ex: [0].company[0].name)
+		path := strings.Split(mappingIndex.ReplaceAllString(matches[1], ".$1"), ".")
+		if path[0] == "" {
+			path = path[1:]
+		}
+		parseErr := &ParseError{Reason: matches[2], Err: errors.New(message)}
+		if value, ok := lookup(input, path).(string); ok {
+			parseErr.Value = value
+		}
+		// The records of the CSV data are indexed by row
+		if _, ok := input.([]map[string]interface{}); ok {
+			parseErr.Row, _ = strconv.Atoi(path[0])
+			path = path[1:]
+		}
+		parseErr.Column = strings.Join(path, c.options.ArrayDelimiter)
+		parseErrs = append(parseErrs, parseErr)
+	}
+	return parseErrs
+}
+
+// lookup returns the value at a path of keys & indexes in the parsed records, or nil if there is none
+func lookup(node interface{}, path []string) interface{} {
+	for _, step := range path {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			node = v[step]
+		case map[string]string:
+			node = v[step]
+		default:
+			index, err := strconv.Atoi(step)
+			value := reflect.ValueOf(node)
+			if err != nil || (value.Kind() != reflect.Slice) || index >= value.Len() {
+				return nil
+			}
+			node = value.Index(index).Interface()
+		}
+	}
+	return node
+}
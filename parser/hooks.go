@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"reflect"
+	"time"
+)
+
+// decodeHook converts the string values to the types of the struct fields they are decoded into
+func (c *csv) decodeHook(f reflect.Type, t reflect.Type, data interface{}) (interface{}, error) {
+	if t.Kind() == reflect.Struct && f.Kind() == reflect.Map {
+		data = c.collectColumns(t, c.matchFields(t, data))
+		if c.options.ErrorUnset {
+			if err := c.checkFields(t, data); err != nil {
+				return data, err
+			}
+		}
+		return c.squashEmbedded(t, data), nil
+	}
+
+	// The empty values leave the pointer fields nil, so that they aren't mistaken for zero values
+	if t.Kind() == reflect.Ptr && data == "" {
+		return nil, nil
+	}
+	// The scanners decode the values coerced to kinds too. The exact numbers are parsed as localized numbers instead
+	if isScanner(t) && !isDecimal(t) && f.Kind() != reflect.Map && f.Kind() != reflect.Slice && f.Kind() != reflect.Struct {
+		converted, err := c.scanValue(data, t)
+		if err != nil {
+			c.options.Logger.Warn("Coercion failed", "value", data, "type", t.String(), "error", err)
+		}
+		return converted, err
+	}
+	if f != reflect.TypeOf("") {
+		return data, nil
+	}
+
+	var converted interface{}
+	var err error
+	switch {
+	case t == reflect.TypeOf(time.Time{}):
+		converted, err = c.parseTime(data.(string), []string{time.RFC3339})
+	// The exact numbers aren't parsed through float64
+	case isDecimal(t):
+		converted, err = c.parseDecimal(data.(string), t)
+	// The custom types decode themselves (ex: uuid.UUID), after the times which are parsed with the TimeLayouts
+	case isUnmarshaler(t):
+		converted, err = unmarshalValue(data.(string), t)
+	case t == reflect.TypeOf(time.Duration(0)) && c.options.ParseDurations:
+		converted, err = parseDuration(data.(string))
+	case (t == reflect.TypeOf(Money{}) || isNumber(t)) && c.options.ParseCurrency:
+		converted, err = c.convertMoney(data.(string), t)
+	case isNumber(t) && c.localizedNumbers():
+		converted, err = c.parseNumber(data.(string), t)
+	case t.Kind() == reflect.Bool:
+		converted, err = c.parseBool(data.(string))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		c.options.Logger.Warn("Coercion failed", "value", data, "type", t.String(), "error", err)
+	}
+	return converted, err
+}
@@ -0,0 +1,48 @@
+package parser
+
+// structureKeys holds the parsing of the column names of a record structure, computed once for all its records instead of for each of them
+// It isn't modified once computed, so that it is shared by the Workers without locking. The names missing from it are parsed on the fly
+type structureKeys struct {
+	parts     map[string][]string
+	arrayKeys map[string]arrayKey
+	paths     map[string]string
+}
+
+// arrayKey is a column name split at its index position into its key, index & sub key, if ok
+type arrayKey struct {
+	key    string
+	index  int
+	subKey string
+	ok     bool
+}
+
+// withKeys returns a copy of the parser with the column names of the example record & the keys of its structure parsed
+// The parsed parts must not be modified
+func (c *csv) withKeys(example map[string]string, structure RecordStructure) *csv {
+	names := make([]string, 0, len(example)+len(structure))
+	if aliased, err := c.aliasColumns(example); err == nil {
+		example = aliased
+	}
+	for k := range example {
+		names = append(names, k)
+	}
+	for key := range structure {
+		names = append(names, key)
+	}
+
+	keys := &structureKeys{
+		parts:     make(map[string][]string, len(names)),
+		arrayKeys: make(map[string]arrayKey, len(names)),
+		paths:     make(map[string]string, len(names)),
+	}
+	withKeys := &csv{options: c.options, keys: keys}
+	// The parts are parsed first, since the array keys & paths are parsed from them
+	for _, k := range names {
+		keys.parts[k] = c.parseKey(k)
+	}
+	for _, k := range names {
+		keys.arrayKeys[k] = withKeys.parseArrayKey(k)
+		keys.paths[k] = withKeys.parseColumnPath(k)
+	}
+	return withKeys
+}
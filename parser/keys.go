@@ -0,0 +1,84 @@
+package parser
+
+import "strings"
+
+// filterKeys removes the keys of the record map which are excluded, or not included when IncludeKeys are set
+func (c *csv) filterKeys(recordMap map[string]interface{}) {
+	if len(c.options.IncludeKeys) == 0 && len(c.options.ExcludeKeys) == 0 {
+		return
+	}
+	c.filterObject(recordMap, "", len(c.options.IncludeKeys) == 0)
+}
+
+// filterObject removes the keys of an object at a path, by the paths of the keys without indexes like the ColumnKinds
+// The keys of an included object are all included, except the excluded ones
+func (c *csv) filterObject(object map[string]interface{}, path string, included bool) {
+	for key, value := range object {
+		keep, whole := c.keepKey(c.childPath(path, key), included)
+		if !keep {
+			delete(object, key)
+			continue
+		}
+		if !c.filterValue(value, c.childPath(path, key), whole) {
+			delete(object, key)
+		}
+	}
+}
+
+// filterValue removes the keys of the objects of a value at a path
+// It returns false when the value is partially included but has no keys to filter
+func (c *csv) filterValue(value interface{}, path string, included bool) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c.filterObject(v, path, included)
+	case map[string]string:
+		for key := range v {
+			if keep, whole := c.keepKey(c.childPath(path, key), included); !keep || !whole {
+				delete(v, key)
+			}
+		}
+	case []interface{}:
+		for _, element := range v {
+			c.filterValue(element, path, included)
+		}
+	case []map[string]interface{}:
+		for _, element := range v {
+			c.filterObject(element, path, included)
+		}
+	case []map[string]string:
+		for _, element := range v {
+			c.filterValue(element, path, included)
+		}
+	default:
+		return included
+	}
+	return true
+}
+
+// keepKey checks if the key at a path is kept, & if it is kept whole rather than for some of its nested keys
+func (c *csv) keepKey(path string, included bool) (bool, bool) {
+	for _, excluded := range c.options.ExcludeKeys {
+		if hasPathPrefix(path, excluded, c.options.ArrayDelimiter) {
+			return false, false
+		}
+	}
+	if included {
+		return true, true
+	}
+
+	partial := false
+	for _, key := range c.options.IncludeKeys {
+		if hasPathPrefix(path, key, c.options.ArrayDelimiter) {
+			return true, true
+		}
+		if hasPathPrefix(key, path, c.options.ArrayDelimiter) {
+			partial = true
+		}
+	}
+	return partial, false
+}
+
+// hasPathPrefix checks if a path starts with the whole parts of a prefix
+func hasPathPrefix(path string, prefix string, delimiter string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+delimiter)
+}
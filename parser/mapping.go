@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// errConflictingPath is returned by setPath when a value is already set on the path
+var errConflictingPath = errors.New("Conflicting mapping path")
+
+// pathStep is a step of a mapping path: the key of an object, or the index of an array when key is empty
+type pathStep struct {
+	key   string
+	index int
+}
+
+// parsePath parses a mapping path into its steps (ex: items[0].sku gives items, 0 & sku). A leading "$." is ignored
+func parsePath(path string) ([]pathStep, error) {
+	var steps []pathStep
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		name, indexes, _ := strings.Cut(segment, "[")
+		if name == "" {
+			return nil, errors.New("Invalid mapping path: " + path)
+		}
+		steps = append(steps, pathStep{key: name})
+		if indexes == "" && !strings.Contains(segment, "[") {
+			continue
+		}
+
+		// The indexes of the segment (ex: 0][1] for matrix[0][1])
+		for _, index := range strings.Split(indexes, "[") {
+			i, err := strconv.Atoi(strings.TrimSuffix(index, "]"))
+			if err != nil || i < 0 || !strings.HasSuffix(index, "]") {
+				return nil, errors.New("Invalid mapping path: " + path)
+			}
+			steps = append(steps, pathStep{index: i})
+		}
+	}
+	return steps, nil
+}
+
+// setMappedValues sets the values of the mapped columns of a record at their paths in the record map
+func (c *csv) setMappedValues(recordMap map[string]interface{}, record map[string]string) error {
+	for column, path := range c.options.Mapping {
+		value, ok := record[column]
+		if !ok {
+			continue
+		}
+
+		steps, err := parsePath(path)
+		if err != nil {
+			return err
+		}
+		if _, err = setPath(recordMap, steps, value); err != nil {
+			return errors.New("Conflicting mapping path: " + path)
+		}
+	}
+	return nil
+}
+
+// setPath sets a value at the path of the steps from a node, creating the missing objects & arrays, and returns the node
+// The array elements missing before an index are nil
+func setPath(node interface{}, steps []pathStep, value string) (interface{}, error) {
+	if len(steps) == 0 {
+		if node != nil {
+			return nil, errConflictingPath
+		}
+		return value, nil
+	}
+
+	step := steps[0]
+	if step.key != "" {
+		object, ok := node.(map[string]interface{})
+		if node == nil {
+			object, ok = make(map[string]interface{}), true
+		}
+		if !ok {
+			return nil, errConflictingPath
+		}
+		child, err := setPath(object[step.key], steps[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		object[step.key] = child
+		return object, nil
+	}
+
+	array, ok := node.([]interface{})
+	if node == nil {
+		ok = true
+	}
+	if !ok {
+		return nil, errConflictingPath
+	}
+	for len(array) <= step.index {
+		array = append(array, nil)
+	}
+	child, err := setPath(array[step.index], steps[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	array[step.index] = child
+	return array, nil
+}
+
+// aliasColumns renames the columns of a record with the ColumnAliases
+func (c *csv) aliasColumns(record map[string]string) (map[string]string, error) {
+	if len(c.options.ColumnAliases) == 0 {
+		return record, nil
+	}
+
+	aliased := make(map[string]string, len(record))
+	for k, v := range record {
+		if alias, ok := c.options.ColumnAliases[k]; ok {
+			k = alias
+		}
+		if _, ok := aliased[k]; ok {
+			return nil, errors.New("Conflicting column names: " + k)
+		}
+		aliased[k] = v
+	}
+	return aliased, nil
+}
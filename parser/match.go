@@ -0,0 +1,162 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// FieldMatchMode is the matching of the keys of the records with the fields of the structs of ToStruct
+type FieldMatchMode int
+
+// Field match modes
+// FieldMatchExact matches the keys with the names of the fields, ignoring their case. This is the default mode
+// FieldMatchNormalized also ignores the characters other than letters & digits (ex: "First Name", "first_name" & "FIRSTNAME" all match first_name)
+// FieldMatchFuzzy also matches the keys with the closest field name, when they differ by at most a quarter of their letters & digits (ex: "frist_name" matches first_name)
+const (
+	FieldMatchExact FieldMatchMode = iota
+	FieldMatchNormalized
+	FieldMatchFuzzy
+)
+
+// FieldMatches collects the keys matched with fields which don't have their name, by FieldMatchNormalized & FieldMatchFuzzy
+// It is safe for concurrent use, so that it can be shared by several parsings
+type FieldMatches struct {
+	mu      sync.Mutex
+	matched map[string]string
+}
+
+// Matched returns the field names matched so far, by key
+func (m *FieldMatches) Matched() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	matched := make(map[string]string, len(m.matched))
+	for key, field := range m.matched {
+		matched[key] = field
+	}
+	return matched
+}
+
+func (m *FieldMatches) add(key string, field string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.matched == nil {
+		m.matched = make(map[string]string)
+	}
+	m.matched[key] = field
+}
+
+// matchFields renames the keys of a map decoded into a struct to the names of the fields they match, as selected by the FieldMatching mode
+func (c *csv) matchFields(t reflect.Type, data interface{}) interface{} {
+	object := toObject(data)
+	if c.options.FieldMatching == FieldMatchExact || object == nil {
+		return data
+	}
+
+	fields := c.fieldNames(t)
+	// The fields matched by name are left to the struct mapping
+	unmatched := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		unmatched[field] = true
+	}
+	var keys []string
+	for key := range object {
+		found := false
+		for _, field := range fields {
+			if strings.EqualFold(key, field) {
+				delete(unmatched, field)
+				found = true
+			}
+		}
+		if !found {
+			keys = append(keys, key)
+		}
+	}
+
+	matched := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		matched[key] = value
+	}
+	for _, key := range keys {
+		field, ok := c.matchField(key, unmatched)
+		if !ok {
+			continue
+		}
+		delete(unmatched, field)
+		delete(matched, key)
+		matched[field] = object[key]
+		c.options.Logger.Debug("Field matched", "key", key, "field", field)
+		if c.options.FieldMatches != nil {
+			c.options.FieldMatches.add(key, field)
+		}
+	}
+	return matched
+}
+
+// matchField returns the field a key matches, among the unmatched fields
+func (c *csv) matchField(key string, unmatched map[string]bool) (string, bool) {
+	normalizedKey := normalizeName(key)
+	for field := range unmatched {
+		if normalizedKey == normalizeName(field) {
+			return field, true
+		}
+	}
+	if c.options.FieldMatching != FieldMatchFuzzy {
+		return "", false
+	}
+
+	// The closest field, unless several are as close
+	best, bestDistance, ties := "", len(normalizedKey)/4+1, 0
+	for field := range unmatched {
+		distance := editDistance(normalizedKey, normalizeName(field))
+		switch {
+		case distance < bestDistance:
+			best, bestDistance, ties = field, distance, 0
+		case distance == bestDistance && best != "":
+			ties++
+		}
+	}
+	return best, best != "" && ties == 0
+}
+
+// normalizeName lowers the case of a name & removes the characters other than letters & digits
+func normalizeName(name string) string {
+	var normalized strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			normalized.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return normalized.String()
+}
+
+// editDistance is the Levenshtein distance between two strings, counting the transpositions of adjacent characters as one edit
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// The distances of the last three rows
+	prevPrev := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(prev[j]+1, current[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				current[j] = min(current[j], prevPrev[j-2]+1)
+			}
+		}
+		prevPrev, prev, current = prev, current, prevPrev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,128 @@
+package parser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// setValue sets the value of a key in the record map, or at the path of the key in nested objects when NestObjects is set
+// The conflicting keys are reported with the ArrayDelimiter between their parts
+func (c *csv) setValue(recordMap map[string]interface{}, key string, value interface{}) error {
+	if !c.options.NestObjects {
+		recordMap[key] = value
+		return nil
+	}
+
+	parts := c.splitKey(key)
+	current := recordMap
+	for i, part := range parts[:len(parts)-1] {
+		child, ok := current[part]
+		if !ok {
+			nested := make(map[string]interface{})
+			current[part] = nested
+			current = nested
+			continue
+		}
+
+		nested, ok := child.(map[string]interface{})
+		if !ok {
+			// A column holds the value of an object
+			return errors.New("Conflicting column names: " + strings.Join(parts[:i+1], c.options.ArrayDelimiter))
+		}
+		current = nested
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := current[last]; ok {
+		return errors.New("Conflicting column names: " + key)
+	}
+	current[last] = value
+	return nil
+}
+
+// setValues sets the values of the keys in the record map, building the nested arrays of their indexes when NestArrays is set
+func (c *csv) setValues(recordMap map[string]interface{}, values map[string]string) error {
+	if !c.options.NestArrays {
+		for key, val := range values {
+			if err := c.setValue(recordMap, key, val); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The values of the arrays by array key, index & sub key
+	arrays := make(map[string]map[int]map[string]string)
+	for key, val := range values {
+		arrayKey, index, subKey, ok := c.splitIndex(key)
+		if !ok {
+			if err := c.setValue(recordMap, key, val); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if arrays[arrayKey] == nil {
+			arrays[arrayKey] = make(map[int]map[string]string)
+		}
+		if arrays[arrayKey][index] == nil {
+			arrays[arrayKey][index] = make(map[string]string)
+		}
+		arrays[arrayKey][index][subKey] = val
+	}
+
+	for arrayKey, elements := range arrays {
+		array, err := c.buildArray(arrayKey, elements)
+		if err != nil {
+			return err
+		}
+		if err = c.setValue(recordMap, arrayKey, array); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitIndex splits a key at its first index (ex: items.2.sku gives items, 2 & sku)
+// The index must follow a key, so keys starting with a number aren't arrays
+func (c *csv) splitIndex(key string) (string, int, string, bool) {
+	parts := c.splitKey(key)
+	for i := 1; i < len(parts); i++ {
+		index, err := strconv.Atoi(parts[i])
+		if err != nil || index < 0 {
+			continue
+		}
+		return strings.Join(parts[:i], c.options.ArrayDelimiter), index, strings.Join(parts[i+1:], c.options.ArrayDelimiter), true
+	}
+	return "", 0, "", false
+}
+
+// buildArray builds a nested array from the values of its elements by index & sub key
+// Like the arrays of the top level keys, empty values are left out of the arrays of values & empty objects out of the arrays of objects
+func (c *csv) buildArray(arrayKey string, elements map[int]map[string]string) (interface{}, error) {
+	// The elements are values when their only sub key is empty
+	values := true
+	objects := true
+	for _, element := range elements {
+		for subKey := range element {
+			if subKey == "" {
+				objects = false
+			} else {
+				values = false
+			}
+		}
+	}
+	if !values && !objects {
+		return nil, errors.New("Conflicting column names: " + arrayKey)
+	}
+
+	if values {
+		return c.buildValues(arrayKey, elements)
+	}
+	indexes, err := c.arrayIndexes(arrayKey, elements)
+	if err != nil {
+		return nil, err
+	}
+	return c.buildObjects(indexes, elements)
+}
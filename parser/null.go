@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+)
+
+// EmptyMode is the representation of the empty values in the parsed records
+type EmptyMode int
+
+// Empty modes
+// EmptyKeep keeps the empty values as empty strings, and as nil for the columns of ColumnKinds. This is the default mode
+// EmptyNil represents the empty values as nil. The pointer fields of ToStruct are left nil by the empty values in any mode
+// EmptyOmit leaves the keys of the empty values out of the records
+// EmptyZero represents the empty values as the zero value of the kind of their column, or as empty strings
+const (
+	EmptyKeep EmptyMode = iota
+	EmptyNil
+	EmptyOmit
+	EmptyZero
+)
+
+// omitted is the value of the keys left out of the records
+type omitted struct{}
+
+// cleanValues applies the transformers of the columns of a record, then replaces the null values with empty values & the empty values with the defaults of their columns
+func (c *csv) cleanValues(record map[string]string) map[string]string {
+	if len(c.options.Transformers) == 0 && len(c.options.NullValues) == 0 && len(c.options.Defaults) == 0 {
+		return record
+	}
+
+	cleaned := make(map[string]string, len(record))
+	for k, v := range record {
+		v = c.transform(k, v)
+		if c.isNull(v) {
+			v = ""
+		}
+		if v == "" {
+			v = c.options.Defaults[k]
+		}
+		cleaned[k] = v
+	}
+	return cleaned
+}
+
+// isNull checks if a value is one of the NullValues
+func (c *csv) isNull(value string) bool {
+	for _, null := range c.options.NullValues {
+		if value == null {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyValue returns the representation of an empty value of a kind, as selected by the EmptyValues mode
+func (c *csv) emptyValue(kind Kind) interface{} {
+	switch c.options.EmptyValues {
+	case EmptyNil:
+		return nil
+	case EmptyOmit:
+		return omitted{}
+	case EmptyZero:
+		switch kind {
+		case KindInt:
+			return int64(0)
+		case KindFloat:
+			return float64(0)
+		case KindBool:
+			return false
+		case KindDate:
+			return time.Time{}
+		}
+		return ""
+	}
+
+	if kind == "" || kind == KindString {
+		return ""
+	}
+	return nil
+}
+
+// scanValue decodes a value into a field implementing sql.Scanner (ex: sql.NullString, sql.NullInt64, sql.Null[T]). Empty values are invalid
+func (c *csv) scanValue(data interface{}, t reflect.Type) (interface{}, error) {
+	scanned := reflect.New(t)
+	if data == "" {
+		return scanned.Elem().Interface(), nil
+	}
+
+	// The times are scanned from time.Time
+	if value, ok := data.(string); ok && t == reflect.TypeOf(sql.NullTime{}) {
+		parsed, err := c.parseTime(value, []string{time.DateOnly, time.RFC3339})
+		if err != nil {
+			return nil, err
+		}
+		data = parsed
+	}
+
+	if err := scanned.Interface().(sql.Scanner).Scan(data); err != nil {
+		return nil, err
+	}
+	return scanned.Elem().Interface(), nil
+}
+
+// isScanner checks if the pointers to a type implement sql.Scanner
+func isScanner(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem())
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// localizedNumbers checks if the numbers are written with other separators than the Go ones
+func (c *csv) localizedNumbers() bool {
+	return c.options.DecimalSeparator != '.' || c.options.ThousandsSeparator != 0
+}
+
+// normalizeNumber rewrites a number with the Go separators (ex: 1.234,56 gives 1234.56 with the decimal separator ',' & the thousands separator '.')
+func (c *csv) normalizeNumber(value string) string {
+	if c.options.ThousandsSeparator != 0 {
+		value = strings.ReplaceAll(value, string(c.options.ThousandsSeparator), "")
+	}
+	if c.options.DecimalSeparator != '.' {
+		value = strings.ReplaceAll(value, string(c.options.DecimalSeparator), ".")
+	}
+	return strings.TrimSpace(value)
+}
+
+// parseNumber parses a localized number into the numeric kind of a struct field
+func (c *csv) parseNumber(value string, t reflect.Type) (interface{}, error) {
+	value = c.normalizeNumber(value)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, t.Bits())
+	default:
+		return strconv.ParseFloat(value, t.Bits())
+	}
+}
+
+// isNumber checks if a type is numeric
+func isNumber(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestToStructLocalizedNumbers(t *testing.T) {
+	type record struct {
+		Count  int
+		Small  uint8
+		Amount float64
+		Ratio  float32
+	}
+
+	tests := []struct {
+		name     string
+		options  CSVOptions
+		values   map[string]string
+		expected record
+		err      bool
+	}{
+		{
+			// Without separators, the numeric fields are only decoded from numbers
+			name:   "default separators",
+			values: map[string]string{"Count": "1234", "Small": "12", "Amount": "1234.56", "Ratio": "0.5"},
+			err:    true,
+		},
+		{
+			name:     "dot decimals",
+			options:  CSVOptions{DecimalSeparator: '.', ThousandsSeparator: ','},
+			values:   map[string]string{"Count": "1,234", "Small": "12", "Amount": "1,234.56", "Ratio": "0.5"},
+			expected: record{Count: 1234, Small: 12, Amount: 1234.56, Ratio: 0.5},
+		},
+		{
+			name:     "comma decimals & dot thousands",
+			options:  CSVOptions{DecimalSeparator: ',', ThousandsSeparator: '.'},
+			values:   map[string]string{"Count": "1.234", "Small": "12", "Amount": "1.234.567,89", "Ratio": "0,5"},
+			expected: record{Count: 1234, Small: 12, Amount: 1234567.89, Ratio: 0.5},
+		},
+		{
+			name:     "space thousands",
+			options:  CSVOptions{DecimalSeparator: ',', ThousandsSeparator: ' '},
+			values:   map[string]string{"Count": " 1 234 ", "Small": "12", "Amount": "1 234,5", "Ratio": "0,25"},
+			expected: record{Count: 1234, Small: 12, Amount: 1234.5, Ratio: 0.25},
+		},
+		{
+			name:     "apostrophe thousands",
+			options:  CSVOptions{ThousandsSeparator: '\''},
+			values:   map[string]string{"Count": "1'234'567", "Small": "1", "Amount": "1'234.56", "Ratio": "1"},
+			expected: record{Count: 1234567, Small: 1, Amount: 1234.56, Ratio: 1},
+		},
+		{
+			name:     "negative number",
+			options:  CSVOptions{DecimalSeparator: ',', ThousandsSeparator: '.'},
+			values:   map[string]string{"Count": "-1.234", "Small": "0", "Amount": "-0,5", "Ratio": "0"},
+			expected: record{Count: -1234, Amount: -0.5},
+		},
+		{
+			name:    "overflow",
+			options: CSVOptions{DecimalSeparator: ','},
+			values:  map[string]string{"Count": "1", "Small": "300", "Amount": "1", "Ratio": "1"},
+			err:     true,
+		},
+		{
+			name:    "decimals in an integer",
+			options: CSVOptions{DecimalSeparator: ','},
+			values:  map[string]string{"Count": "1,5", "Small": "1", "Amount": "1", "Ratio": "1"},
+			err:     true,
+		},
+		{
+			name:    "invalid number",
+			options: CSVOptions{DecimalSeparator: ',', ThousandsSeparator: '.'},
+			values:  map[string]string{"Count": "1", "Small": "1", "Amount": "1.2a", "Ratio": "1"},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var records []record
+			err := NewCSV(tt.options).ToStruct(context.Background(), []map[string]string{tt.values}, &records)
+			if tt.err {
+				if err == nil {
+					t.Errorf("ToStruct() = %v, want an error", records)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1 || records[0] != tt.expected {
+				t.Errorf("ToStruct() = %v, want %v", records, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColumnKindsLocalizedNumbers(t *testing.T) {
+	csvData := []map[string]string{{"count": "1.234", "amount": "1.234,5"}}
+	c := NewCSV(CSVOptions{
+		DecimalSeparator:   ',',
+		ThousandsSeparator: '.',
+		ColumnKinds:        map[string]Kind{"count": KindInt, "amount": KindFloat},
+	})
+
+	res, err := c.ToMap(context.Background(), csvData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []map[string]interface{}{{"count": int64(1234), "amount": 1234.5}}; !reflect.DeepEqual(res, expected) {
+		t.Errorf("ToMap() = %v, want %v", res, expected)
+	}
+}
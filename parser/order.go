@@ -0,0 +1,156 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// orderedObject is an object whose keys are marshalled to JSON in order
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// MarshalJSON marshals the object with its keys in order
+// The values aren't HTML escaped, since the encoder marshalling the object escapes them when it is set to
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(key); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1)
+		buf.WriteByte(':')
+		if err := encoder.Encode(o.values[key]); err != nil {
+			return nil, err
+		}
+		// Encode ends the values with a line break
+		buf.Truncate(buf.Len() - 1)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// columnOrder returns the positions of the keys in the Headers, by path without indexes (ex: company & company.name for company.0.name)
+// The position of a key is the one of its first column
+func (c *csv) columnOrder() map[string]int {
+	order := make(map[string]int)
+	// The paths of the parts, with the numeric parts skipped like the indexes of columnPath
+	add := func(i int, parts []string) {
+		var path []string
+		for _, part := range parts {
+			if _, err := strconv.Atoi(part); err == nil && len(path) > 0 {
+				continue
+			}
+			path = append(path, part)
+			key := strings.Join(path, c.options.ArrayDelimiter)
+			if _, ok := order[key]; !ok {
+				order[key] = i
+			}
+		}
+	}
+
+	for i, header := range c.options.Headers {
+		if alias, ok := c.options.ColumnAliases[header]; ok {
+			header = alias
+		}
+		add(i, c.splitKey(header))
+		// The single valued keys are kept as they are when they aren't nested
+		add(i, []string{header})
+
+		// The paths of the mapped columns
+		if path, ok := c.options.Mapping[header]; ok {
+			steps, err := parsePath(path)
+			if err != nil {
+				continue
+			}
+			var parts []string
+			for _, step := range steps {
+				if step.key != "" {
+					parts = append(parts, step.key)
+				}
+			}
+			add(i, parts)
+		}
+	}
+	return order
+}
+
+// ordered returns a record map whose objects marshal their keys in the order of the Headers, by path without indexes like the ColumnKinds
+// The keys without columns come last, sorted by name
+func (c *csv) ordered(val interface{}, path string, order map[string]int) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		object := orderedObject{values: make(map[string]interface{}, len(v))}
+		for key, value := range v {
+			object.keys = append(object.keys, key)
+			object.values[key] = c.ordered(value, c.childPath(path, key), order)
+		}
+		c.sortKeys(object.keys, path, order)
+		return object
+	case map[string]string:
+		object := orderedObject{values: make(map[string]interface{}, len(v))}
+		for key, value := range v {
+			object.keys = append(object.keys, key)
+			object.values[key] = value
+		}
+		c.sortKeys(object.keys, path, order)
+		return object
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			values[i] = c.ordered(value, path, order)
+		}
+		return values
+	case []map[string]interface{}:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			if value != nil {
+				values[i] = c.ordered(value, path, order)
+			}
+		}
+		return values
+	case []map[string]string:
+		values := make([]interface{}, len(v))
+		for i, value := range v {
+			if value != nil {
+				values[i] = c.ordered(value, path, order)
+			}
+		}
+		return values
+	}
+	return val
+}
+
+// childPath returns the path of a key of the object at a path
+func (c *csv) childPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + c.options.ArrayDelimiter + key
+}
+
+// sortKeys sorts the keys of the object at a path in the order of their columns, then by name
+func (c *csv) sortKeys(keys []string, path string, order map[string]int) {
+	sort.Slice(keys, func(i, j int) bool {
+		pi, oki := order[c.childPath(path, keys[i])]
+		pj, okj := order[c.childPath(path, keys[j])]
+		switch {
+		case oki && okj && pi != pj:
+			return pi < pj
+		case oki != okj:
+			return oki
+		}
+		return keys[i] < keys[j]
+	})
+}
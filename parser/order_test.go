@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"context"
+	"testing"
+)
+
+func TestToJSONHeadersOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  CSVOptions
+		expected string
+	}{
+		{
+			name:     "headers",
+			options:  CSVOptions{Headers: []string{"b", "a"}},
+			expected: `[{"b":"1","a":"2"}]`,
+		},
+		{
+			name:     "headers with root",
+			options:  CSVOptions{Headers: []string{"b", "a"}, JSONRoot: "records"},
+			expected: `{"records":[{"b":"1","a":"2"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			csvData := []map[string]string{{"a": "2", "b": "1"}}
+			res, err := NewCSV(tt.options).ToJSON(context.Background(), csvData)
+			if err != nil {
+				t.Fatalf("ToJSON() error = %v", err)
+			}
+			if res != tt.expected {
+				t.Errorf("ToJSON() = %s, want %s", res, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelBatch is the number of consecutive records converted at a time by each worker
+const parallelBatch = 256
+
+// convertRecords converts the records into maps, on up to Workers goroutines. The maps & errors are returned in the order of the records
+// The conversion stops at the first error which isn't a ParseErrors or once cancelled, so that the records after it may be left unconverted
+func (c *csv) convertRecords(ctx context.Context, recordStructure RecordStructure, csvData []map[string]string) ([]map[string]interface{}, []error) {
+	recordMaps := make([]map[string]interface{}, len(csvData))
+	errs := make([]error, len(csvData))
+
+	workers := c.options.Workers
+	if batches := (len(csvData) + parallelBatch - 1) / parallelBatch; workers > batches {
+		workers = batches
+	}
+	if workers <= 1 {
+		for i, record := range csvData {
+			if errs[i] = ctx.Err(); errs[i] != nil {
+				break
+			}
+			recordMaps[i], errs[i] = c.convertRecord(ctx, recordStructure, record, i)
+			if isFatal(errs[i]) {
+				break
+			}
+		}
+		return recordMaps, errs
+	}
+
+	// The batches are taken in order, so that all the records before a fatal error are converted
+	var next atomic.Int64
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !failed.Load() {
+				start := int(next.Add(parallelBatch)) - parallelBatch
+				if start >= len(csvData) {
+					return
+				}
+				end := min(start+parallelBatch, len(csvData))
+				for i := start; i < end; i++ {
+					if errs[i] = ctx.Err(); errs[i] != nil {
+						failed.Store(true)
+						break
+					}
+					recordMaps[i], errs[i] = c.convertRecord(ctx, recordStructure, csvData[i], i)
+					if isFatal(errs[i]) {
+						failed.Store(true)
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return recordMaps, errs
+}
+
+// convertRecord converts the record of a row into a map
+func (c *csv) convertRecord(ctx context.Context, recordStructure RecordStructure, record map[string]string, row int) (map[string]interface{}, error) {
+	// The quotes of the kept records are cleaned up in copies
+	if c.options.KeepInput {
+		record = c.cleanRecord(record)
+	}
+
+	recordMap, err := c.recordToMap(ctx, recordStructure, record)
+	return recordMap, withRow(err, row)
+}
+
+// isFatal checks if a conversion error stops the conversion, which is the case of the errors other than the invalid cells
+func isFatal(err error) bool {
+	var parseErrs ParseErrors
+	return err != nil && !errors.As(err, &parseErrs)
+}
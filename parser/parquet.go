@@ -0,0 +1,606 @@
+package parser
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+)
+
+// Parquet encodings, codec & page type of the written files
+const (
+	parquetPlain    = 0
+	parquetRLE      = 3
+	parquetSnappy   = 1
+	parquetDataPage = 0
+
+	// parquetPageSize is the size of the encoded values above which a column starts a new page
+	parquetPageSize = 1 << 20
+)
+
+var parquetMagic = []byte("PAR1")
+
+// parquetConvertedTypes are the converted types of the logical types, for the readers which predate the logical types
+var parquetConvertedTypes = map[string]int32{"STRING": 0, "ENUM": 4, "DECIMAL": 5, "DATE": 6, "JSON": 19}
+
+// ToParquet parses CSV into a Parquet file written to w, with a row group of all the records
+// schema is a Parquet schema in the message format (ex: message records { required int64 id; optional binary name (STRING); }). It is derived from the parsed records when empty: every field is optional, the objects are groups of their keys & the arrays groups of their indexes, & the types are inferred from the kinds of the values (ex: int64 for KindInt, binary strings by default)
+// The values are plain encoded in Snappy compressed pages. Repeated fields aren't supported, so the arrays are written as groups (ex: the tags.0 column of optional group tags { optional binary 0 (STRING); })
+func (c *csv) ToParquet(ctx context.Context, csvData []map[string]string, w io.Writer, schema string) error {
+	convertedToMap, err := c.ToMap(ctx, csvData)
+	if err != nil {
+		return err
+	}
+
+	var root *parquetNode
+	if schema == "" {
+		root, err = c.parquetSchema(convertedToMap)
+	} else {
+		root, err = parseParquetSchema(schema)
+	}
+	if err != nil {
+		return err
+	}
+
+	pw := &parquetWriter{w: w}
+	if err = pw.write(parquetMagic); err != nil {
+		return err
+	}
+
+	// A file of no records has no row groups
+	var rowGroups []interface{}
+	if len(convertedToMap) > 0 {
+		var chunks []interface{}
+		var totalSize int64
+		for _, column := range parquetColumns(root, nil, nil, 0) {
+			if err = ctx.Err(); err != nil {
+				return err
+			}
+			chunk, size, err := pw.writeColumn(column, convertedToMap)
+			if err != nil {
+				return err
+			}
+			chunks = append(chunks, chunk)
+			totalSize += size
+		}
+		rowGroups = append(rowGroups, thriftStruct{
+			{1, chunks},
+			{2, totalSize},
+			{3, int64(len(convertedToMap))},
+		})
+	}
+
+	metadata := thriftStruct{
+		{1, int32(1)},
+		{2, parquetSchemaElements(root, true)},
+		{3, int64(len(convertedToMap))},
+		{4, rowGroups},
+		{6, "uniparse"},
+	}
+	footer := metadata.encode(nil)
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	return pw.write(append(footer, parquetMagic...))
+}
+
+// parquetColumn is a column of the written file, with the fields of its path
+type parquetColumn struct {
+	*parquetNode
+	path          []string
+	nodes         []*parquetNode
+	maxDefinition int
+}
+
+// parquetColumns returns the columns of a group, in the order of the schema
+func parquetColumns(group *parquetNode, path []string, nodes []*parquetNode, maxDefinition int) []*parquetColumn {
+	var columns []*parquetColumn
+	for _, child := range group.children {
+		childPath := append(path[:len(path):len(path)], child.name)
+		childNodes := append(nodes[:len(nodes):len(nodes)], child)
+		childDefinition := maxDefinition
+		if child.repetition == parquetOptional {
+			childDefinition++
+		}
+
+		if child.group {
+			columns = append(columns, parquetColumns(child, childPath, childNodes, childDefinition)...)
+			continue
+		}
+		columns = append(columns, &parquetColumn{parquetNode: child, path: childPath, nodes: childNodes, maxDefinition: childDefinition})
+	}
+	return columns
+}
+
+// value returns the value of the column in a record & its definition level, which is the number of optional fields of its path which are defined
+// The empty strings are nulls, except for the binary columns
+func (col *parquetColumn) value(record map[string]interface{}) (interface{}, int, error) {
+	var value interface{} = record
+	level := 0
+	for i, node := range col.nodes {
+		if object := toObject(value); object != nil {
+			value = object[node.name]
+		} else if index, err := strconv.Atoi(node.name); err == nil && index >= 0 && index < len(toArray(value)) {
+			value = toArray(value)[index]
+		} else {
+			value = nil
+		}
+
+		if value == nil || (value == "" && (node.group || node.physicalType != parquetByteArray)) {
+			if node.repetition == parquetRequired {
+				return nil, 0, errors.New("Missing value of the required Parquet field " + strings.Join(col.path[:i+1], "."))
+			}
+			return nil, level, nil
+		}
+		if node.repetition == parquetOptional {
+			level++
+		}
+	}
+	return value, level, nil
+}
+
+// physicalValue converts a value of the parsed records to the physical type of the column, for its logical type. The strings are parsed for the other types
+func (col *parquetColumn) physicalValue(value interface{}) (interface{}, error) {
+	text, isString := value.(string)
+	switch col.physicalType {
+	case parquetBoolean:
+		if isString {
+			return strconv.ParseBool(text)
+		}
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+
+	case parquetInt32, parquetInt64:
+		var number int64
+		var err error
+		switch col.logicalType {
+		case "DATE":
+			number, err = parquetDate(value)
+		case "TIMESTAMP":
+			number, err = col.timestamp(value)
+		case "DECIMAL":
+			number, err = col.decimal(value)
+		default:
+			number, err = parquetInteger(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if col.physicalType == parquetInt64 {
+			return number, nil
+		}
+		if number < math.MinInt32 || number > math.MaxInt32 {
+			return nil, errors.New("Parquet int32 value out of range: " + strconv.FormatInt(number, 10))
+		}
+		return int32(number), nil
+
+	case parquetFloat, parquetDouble:
+		var number float64
+		switch v := value.(type) {
+		case string:
+			parsed, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, err
+			}
+			number = parsed
+		case float64:
+			number = v
+		case int64:
+			number = float64(v)
+		default:
+			return nil, errors.New("Invalid Parquet " + col.typeName() + " value: " + string(mustJSON(value)))
+		}
+		if col.physicalType == parquetFloat {
+			return float32(number), nil
+		}
+		return number, nil
+
+	case parquetByteArray:
+		switch v := value.(type) {
+		case string:
+			return []byte(v), nil
+		case int64:
+			return []byte(strconv.FormatInt(v, 10)), nil
+		case float64:
+			return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+		case bool:
+			return []byte(strconv.FormatBool(v)), nil
+		case time.Time:
+			return []byte(v.Format(time.RFC3339Nano)), nil
+		}
+		// The objects & arrays are stored as JSON
+		return mustJSON(value), nil
+	}
+	return nil, errors.New("Invalid Parquet " + col.typeName() + " value: " + string(mustJSON(value)))
+}
+
+// typeName returns the name of the physical type of the column in the message format
+func (col *parquetColumn) typeName() string {
+	for name, physicalType := range parquetPhysicalTypes {
+		if physicalType == col.physicalType {
+			return name
+		}
+	}
+	return ""
+}
+
+// parquetInteger converts a value to an integer
+func parquetInteger(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	case int64:
+		return v, nil
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), nil
+		}
+	}
+	return 0, errors.New("Invalid Parquet integer value: " + string(mustJSON(value)))
+}
+
+// parquetDate converts a value to the number of days since the Unix epoch
+func parquetDate(value interface{}) (int64, error) {
+	date, ok := value.(time.Time)
+	if text, isString := value.(string); isString {
+		var err error
+		if date, err = time.Parse(time.DateOnly, text); err != nil {
+			if date, err = time.Parse(time.RFC3339, text); err != nil {
+				return 0, errors.New("Invalid Parquet date value: " + text)
+			}
+		}
+		ok = true
+	}
+	if !ok {
+		return 0, errors.New("Invalid Parquet date value: " + string(mustJSON(value)))
+	}
+
+	// The days of the dates before the epoch are rounded down
+	seconds := date.Unix()
+	days := seconds / 86400
+	if seconds%86400 < 0 {
+		days--
+	}
+	return days, nil
+}
+
+// timestamp converts a value to a timestamp in the unit of the column. The timestamps which aren't adjusted to UTC store the wall clock time
+func (col *parquetColumn) timestamp(value interface{}) (int64, error) {
+	t, ok := value.(time.Time)
+	if text, isString := value.(string); isString {
+		var err error
+		if t, err = time.Parse(time.RFC3339Nano, text); err != nil {
+			return 0, errors.New("Invalid Parquet timestamp value: " + text)
+		}
+		ok = true
+	}
+	if !ok {
+		return 0, errors.New("Invalid Parquet timestamp value: " + string(mustJSON(value)))
+	}
+
+	if !col.utc {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+	switch col.unit {
+	case time.Millisecond:
+		return t.UnixMilli(), nil
+	case time.Microsecond:
+		return t.UnixMicro(), nil
+	}
+	return t.UnixNano(), nil
+}
+
+// decimal converts a value to the unscaled integer of a decimal of the scale & precision of the column
+func (col *parquetColumn) decimal(value interface{}) (int64, error) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return 0, errors.New("Invalid Parquet decimal value: " + string(mustJSON(value)))
+	}
+	number, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return 0, errors.New("Invalid Parquet decimal value: " + text)
+	}
+
+	scaled := number.Mul(number, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.scale)), nil)))
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(col.precision)), nil)
+	if !scaled.IsInt() || scaled.Num().CmpAbs(limit) >= 0 || !scaled.Num().IsInt64() {
+		return 0, errors.New("Parquet decimal value out of the scale or precision: " + text)
+	}
+	return scaled.Num().Int64(), nil
+}
+
+// parquetSchemaElements returns the schema elements of a node & its fields, depth first
+func parquetSchemaElements(node *parquetNode, root bool) []interface{} {
+	element := thriftStruct{}
+	if !node.group {
+		element = append(element, thriftField{1, node.physicalType})
+	}
+	if !root {
+		element = append(element, thriftField{3, node.repetition})
+	}
+	element = append(element, thriftField{4, node.name})
+	if node.group {
+		element = append(element, thriftField{5, int32(len(node.children))})
+	}
+
+	switch node.logicalType {
+	case "":
+	case "TIMESTAMP":
+		// Only the timestamps adjusted to UTC in millis & micros have a converted type
+		if node.utc && node.unit == time.Millisecond {
+			element = append(element, thriftField{6, int32(9)})
+		} else if node.utc && node.unit == time.Microsecond {
+			element = append(element, thriftField{6, int32(10)})
+		}
+		units := map[time.Duration]int16{time.Millisecond: 1, time.Microsecond: 2, time.Nanosecond: 3}
+		timestamp := thriftStruct{{1, node.utc}, {2, thriftStruct{{units[node.unit], thriftStruct{}}}}}
+		element = append(element, thriftField{10, thriftStruct{{8, timestamp}}})
+	case "DECIMAL":
+		element = append(element,
+			thriftField{6, parquetConvertedTypes[node.logicalType]},
+			thriftField{7, int32(node.scale)},
+			thriftField{8, int32(node.precision)},
+			thriftField{10, thriftStruct{{5, thriftStruct{{1, int32(node.scale)}, {2, int32(node.precision)}}}}},
+		)
+	default:
+		logicalTypes := map[string]int16{"STRING": 1, "ENUM": 4, "DATE": 6, "JSON": 12}
+		element = append(element,
+			thriftField{6, parquetConvertedTypes[node.logicalType]},
+			thriftField{10, thriftStruct{{logicalTypes[node.logicalType], thriftStruct{}}}},
+		)
+	}
+
+	elements := []interface{}{element}
+	for _, child := range node.children {
+		elements = append(elements, parquetSchemaElements(child, false)...)
+	}
+	return elements
+}
+
+// parquetWriter writes the pages of the columns, keeping track of their offsets in the file
+type parquetWriter struct {
+	w      io.Writer
+	offset int64
+}
+
+func (pw *parquetWriter) write(data []byte) error {
+	n, err := pw.w.Write(data)
+	pw.offset += int64(n)
+	return err
+}
+
+// writeColumn writes the pages of a column of the records & returns the column chunk of its metadata, along with its uncompressed size
+func (pw *parquetWriter) writeColumn(column *parquetColumn, records []map[string]interface{}) (thriftStruct, int64, error) {
+	start := pw.offset
+	var uncompressedSize int64
+	var levels []int
+	var values []byte
+	count := 0
+
+	flush := func() error {
+		var page []byte
+		if column.maxDefinition > 0 {
+			encoded := parquetLevels(levels, bits.Len(uint(column.maxDefinition)))
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(encoded)))
+			page = append(page, encoded...)
+		}
+		page = append(page, values...)
+		compressed := s2.EncodeSnappy(nil, page)
+
+		header := thriftStruct{
+			{1, int32(parquetDataPage)},
+			{2, int32(len(page))},
+			{3, int32(len(compressed))},
+			{5, thriftStruct{
+				{1, int32(len(levels))},
+				{2, int32(parquetPlain)},
+				{3, int32(parquetRLE)},
+				{4, int32(parquetRLE)},
+			}},
+		}.encode(nil)
+		uncompressedSize += int64(len(header) + len(page))
+		levels, values, count = levels[:0], values[:0], 0
+
+		if err := pw.write(header); err != nil {
+			return err
+		}
+		return pw.write(compressed)
+	}
+
+	for _, record := range records {
+		value, level, err := column.value(record)
+		if err != nil {
+			return nil, 0, err
+		}
+		levels = append(levels, level)
+		if level == column.maxDefinition {
+			physical, err := column.physicalValue(value)
+			if err != nil {
+				return nil, 0, errors.New("Invalid value of the Parquet column " + strings.Join(column.path, ".") + ": " + err.Error())
+			}
+			values = parquetPlainValue(values, physical, count)
+			count++
+		}
+		if len(values) >= parquetPageSize {
+			if err = flush(); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	if len(levels) > 0 {
+		if err := flush(); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	paths := make([]interface{}, len(column.path))
+	for i, name := range column.path {
+		paths[i] = name
+	}
+	metadata := thriftStruct{
+		{1, column.physicalType},
+		{2, []interface{}{int32(parquetPlain), int32(parquetRLE)}},
+		{3, paths},
+		{4, int32(parquetSnappy)},
+		{5, int64(len(records))},
+		{6, uncompressedSize},
+		{7, pw.offset - start},
+		{9, start},
+	}
+	return thriftStruct{{2, start}, {3, metadata}}, uncompressedSize, nil
+}
+
+// parquetPlainValue appends the plain encoding of a value, with the booleans bit packed given the number of values before it in the page
+func parquetPlainValue(data []byte, value interface{}, count int) []byte {
+	switch v := value.(type) {
+	case bool:
+		if count%8 == 0 {
+			data = append(data, 0)
+		}
+		if v {
+			data[len(data)-1] |= 1 << (count % 8)
+		}
+	case int32:
+		data = binary.LittleEndian.AppendUint32(data, uint32(v))
+	case int64:
+		data = binary.LittleEndian.AppendUint64(data, uint64(v))
+	case float32:
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(v))
+	case float64:
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	case []byte:
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(v)))
+		data = append(data, v...)
+	}
+	return data
+}
+
+// parquetLevels encodes levels as the RLE runs of the hybrid encoding
+func parquetLevels(levels []int, bitWidth int) []byte {
+	var data []byte
+	byteWidth := (bitWidth + 7) / 8
+	for i := 0; i < len(levels); {
+		run := 1
+		for i+run < len(levels) && levels[i+run] == levels[i] {
+			run++
+		}
+		data = binary.AppendUvarint(data, uint64(run)<<1)
+		for b := 0; b < byteWidth; b++ {
+			data = append(data, byte(levels[i]>>(8*b)))
+		}
+		i += run
+	}
+	return data
+}
+
+// thriftField is a field of a struct of the Thrift compact protocol, of id & value: bool, int32, int64, string, thriftStruct or a list of them
+type thriftField struct {
+	id    int16
+	value interface{}
+}
+
+// thriftStruct is a struct of the Thrift compact protocol, with its fields by increasing ids
+type thriftStruct []thriftField
+
+// Types of the Thrift compact protocol
+const (
+	thriftTrue       = 1
+	thriftFalse      = 2
+	thriftI32        = 5
+	thriftI64        = 6
+	thriftBinary     = 8
+	thriftList       = 9
+	thriftStructType = 12
+)
+
+// encode appends the encoding of the struct in the Thrift compact protocol
+func (s thriftStruct) encode(data []byte) []byte {
+	var lastID int16
+	for _, field := range s {
+		fieldType := thriftType(field.value)
+		if b, ok := field.value.(bool); ok && !b {
+			fieldType = thriftFalse
+		}
+		if delta := field.id - lastID; delta > 0 && delta <= 15 {
+			data = append(data, byte(delta)<<4|fieldType)
+		} else {
+			data = append(data, fieldType)
+			data = binary.AppendVarint(data, int64(field.id))
+		}
+		lastID = field.id
+
+		// The booleans of the fields are in their types
+		if _, ok := field.value.(bool); !ok {
+			data = thriftValue(data, field.value)
+		}
+	}
+	return append(data, 0)
+}
+
+// thriftType returns the type of a value in the Thrift compact protocol
+func thriftType(value interface{}) byte {
+	switch value.(type) {
+	case bool:
+		return thriftTrue
+	case int32:
+		return thriftI32
+	case int64:
+		return thriftI64
+	case string:
+		return thriftBinary
+	case []interface{}:
+		return thriftList
+	}
+	return thriftStructType
+}
+
+// thriftValue appends the encoding of a value in the Thrift compact protocol
+func thriftValue(data []byte, value interface{}) []byte {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return append(data, thriftTrue)
+		}
+		return append(data, thriftFalse)
+	case int32:
+		return binary.AppendVarint(data, int64(v))
+	case int64:
+		return binary.AppendVarint(data, v)
+	case string:
+		data = binary.AppendUvarint(data, uint64(len(v)))
+		return append(data, v...)
+	case []interface{}:
+		// The lists of no elements are lists of structs
+		elementType := byte(thriftStructType)
+		if len(v) > 0 {
+			elementType = thriftType(v[0])
+		}
+		if len(v) < 15 {
+			data = append(data, byte(len(v))<<4|elementType)
+		} else {
+			data = append(data, 0xf0|elementType)
+			data = binary.AppendUvarint(data, uint64(len(v)))
+		}
+		for _, element := range v {
+			data = thriftValue(data, element)
+		}
+		return data
+	case thriftStruct:
+		return v.encode(data)
+	}
+	return data
+}
@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/mindship/uniparse/reader"
+)
+
+func TestToParquet(t *testing.T) {
+	csvData := []map[string]string{
+		{"id": "1", "name": "Alice", "score": "1.5", "active": "true", "joined": "2024-02-01T10:00:00Z", "company.name": "Acme", "tags.0": "a", "tags.1": "b"},
+		{"id": "2", "name": "", "score": "", "active": "false", "joined": "", "company.name": "", "tags.0": "c", "tags.1": ""},
+	}
+	options := CSVOptions{NestObjects: true, ColumnKinds: map[string]Kind{"id": KindInt, "score": KindFloat, "active": KindBool, "joined": KindDate}}
+
+	tests := []struct {
+		name     string
+		schema   string
+		expected []map[string]string
+	}{
+		{
+			name: "derived schema",
+			expected: []map[string]string{
+				{"active": "true", "company.name": "Acme", "id": "1", "joined": "2024-02-01T10:00:00Z", "name": "Alice", "score": "1.5", "tags.0": "a", "tags.1": "b"},
+				{"active": "false", "company.name": "", "id": "2", "joined": "", "name": "", "score": "", "tags.0": "c", "tags.1": ""},
+			},
+		},
+		{
+			name: "schema",
+			schema: `message records {
+				required int32 id;
+				optional binary name (STRING);
+				optional int64 score (DECIMAL(10,2));
+				required boolean active;
+				optional int32 joined (DATE);
+				optional group company {
+					required binary name (UTF8);
+				}
+			}`,
+			expected: []map[string]string{
+				{"id": "1", "name": "Alice", "score": "1.50", "active": "true", "joined": "2024-02-01T00:00:00Z", "company.name": "Acme"},
+				{"id": "2", "name": "", "score": "", "active": "false", "joined": "", "company.name": ""},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewCSV(options).ToParquet(context.Background(), csvData, &buf, tt.schema); err != nil {
+				t.Fatalf("ToParquet() error = %v", err)
+			}
+
+			records, err := reader.NewParquet(reader.ParquetOptions{}).FromReader(context.Background(), &buf)
+			if err != nil {
+				t.Fatalf("FromReader() error = %v", err)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("ToParquet() = %v, want %v", records, tt.expected)
+			}
+		})
+	}
+}
+
+func TestToParquetEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewCSV(CSVOptions{}).ToParquet(context.Background(), []map[string]string{}, &buf, ""); err != nil {
+		t.Fatalf("ToParquet() error = %v", err)
+	}
+
+	records, err := reader.NewParquet(reader.ParquetOptions{}).FromReader(context.Background(), &buf)
+	if err != nil || len(records) != 0 {
+		t.Errorf("FromReader() = %v, %v, want no records", records, err)
+	}
+}
+
+func TestToParquetInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		csvData  []map[string]string
+		schema   string
+		expected string
+	}{
+		{
+			name:     "repeated field",
+			csvData:  []map[string]string{{"id": "1"}},
+			schema:   "message records { repeated int64 id; }",
+			expected: "Unsupported repeated field in the Parquet schema",
+		},
+		{
+			name:     "logical type",
+			csvData:  []map[string]string{{"id": "1"}},
+			schema:   "message records { required binary id (DATE); }",
+			expected: "Invalid Parquet schema: DATE doesn't annotate the type of id",
+		},
+		{
+			name:     "missing brace",
+			csvData:  []map[string]string{{"id": "1"}},
+			schema:   "message records { required int64 id;",
+			expected: "Invalid Parquet schema: missing }",
+		},
+		{
+			name:     "required value",
+			csvData:  []map[string]string{{"id": ""}},
+			schema:   "message records { required int64 id; }",
+			expected: "Missing value of the required Parquet field id",
+		},
+		{
+			name:     "value",
+			csvData:  []map[string]string{{"id": "one"}},
+			schema:   "message records { required int64 id; }",
+			expected: `Invalid value of the Parquet column id: strconv.ParseInt: parsing "one": invalid syntax`,
+		},
+		{
+			name:     "decimal scale",
+			csvData:  []map[string]string{{"price": "1.234"}},
+			schema:   "message records { required int64 price (DECIMAL(10,2)); }",
+			expected: "Invalid value of the Parquet column price: Parquet decimal value out of the scale or precision: 1.234",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewCSV(CSVOptions{}).ToParquet(context.Background(), tt.csvData, &buf, tt.schema)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("ToParquet() error = %v, want %s", err, tt.expected)
+			}
+		})
+	}
+}
+
+// TestToParquetBytes checks the bytes of a file against the Parquet format specification, independently of the reader
+// The Thrift structs are in the compact protocol: a field header byte holds the delta of the field id & the type (5 i32, 6 i64, 8 binary, 9 list, 12 struct), & the integers are zigzag varints
+func TestToParquetBytes(t *testing.T) {
+	csvData := []map[string]string{{"a": "1", "b": "x"}, {"a": "2", "b": ""}}
+	// The empty value of b is a null
+	options := CSVOptions{EmptyValues: EmptyNil}
+	schema := "message m { required int32 a; optional binary b (STRING); }"
+
+	var expected []byte
+	add := func(data ...interface{}) {
+		for _, d := range data {
+			switch v := d.(type) {
+			case int:
+				expected = append(expected, byte(v))
+			case string:
+				expected = append(expected, v...)
+			}
+		}
+	}
+
+	add("PAR1")
+	// Column a at offset 4: the PageHeader of a DATA_PAGE of 8 bytes, 10 once compressed, & its DataPageHeader of 2 PLAIN values with RLE levels
+	add(0x15, 0x00, 0x15, 0x10, 0x15, 0x14, 0x2c, 0x15, 0x04, 0x15, 0x00, 0x15, 0x06, 0x15, 0x06, 0x00, 0x00)
+	// The Snappy block: the uncompressed length & a literal of the 2 int32 values, without levels since a is required
+	add(0x08, 0x1c, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00)
+	// Column b at offset 31: a page of 13 bytes, 15 once compressed
+	add(0x15, 0x00, 0x15, 0x1a, 0x15, 0x1e, 0x2c, 0x15, 0x04, 0x15, 0x00, 0x15, 0x06, 0x15, 0x06, 0x00, 0x00)
+	// The length of the definition levels, their RLE runs of bit width 1 (a run of one 1 & a run of one 0) & the length prefixed "x"
+	add(0x0d, 0x30, 0x04, 0x00, 0x00, 0x00, 0x02, 0x01, 0x02, 0x00, 0x01, 0x00, 0x00, 0x00, "x")
+
+	footer := len(expected)
+	// FileMetaData: version 1 & the list of 3 SchemaElements
+	add(0x15, 0x02, 0x19, 0x3c)
+	// The root m of 2 children, a required INT32, & b an optional BYTE_ARRAY of converted type UTF8 & logical type STRING
+	add(0x48, 0x01, "m", 0x15, 0x04, 0x00)
+	add(0x15, 0x02, 0x25, 0x00, 0x18, 0x01, "a", 0x00)
+	add(0x15, 0x0c, 0x25, 0x02, 0x18, 0x01, "b", 0x25, 0x00, 0x4c, 0x1c, 0x00, 0x00, 0x00)
+	// num_rows 2 & the list of 1 RowGroup, with its list of 2 ColumnChunks
+	add(0x16, 0x04, 0x19, 0x1c, 0x19, 0x2c)
+	// The ColumnChunk of a at offset 4 & its ColumnMetaData: INT32, encodings PLAIN & RLE, path a, SNAPPY, 2 values, sizes 25 & 27 & data page offset 4
+	add(0x26, 0x08, 0x1c)
+	add(0x15, 0x02, 0x19, 0x25, 0x00, 0x06, 0x19, 0x18, 0x01, "a", 0x15, 0x02, 0x16, 0x04, 0x16, 0x32, 0x16, 0x36, 0x26, 0x08, 0x00, 0x00)
+	// The ColumnChunk of b at offset 31: BYTE_ARRAY, sizes 30 & 32
+	add(0x26, 0x3e, 0x1c)
+	add(0x15, 0x0c, 0x19, 0x25, 0x00, 0x06, 0x19, 0x18, 0x01, "b", 0x15, 0x02, 0x16, 0x04, 0x16, 0x3c, 0x16, 0x40, 0x26, 0x3e, 0x00, 0x00)
+	// The total_byte_size 55 & num_rows 2 of the RowGroup, then created_by
+	add(0x16, 0x6e, 0x16, 0x04, 0x00, 0x28, 0x08, "uniparse", 0x00)
+	// The little endian length of the FileMetaData & the magic
+	add(len(expected)-footer, 0x00, 0x00, 0x00, "PAR1")
+
+	var buf bytes.Buffer
+	if err := NewCSV(options).ToParquet(context.Background(), csvData, &buf, schema); err != nil {
+		t.Fatalf("ToParquet() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("ToParquet() = % x, want % x", buf.Bytes(), expected)
+	}
+}
@@ -0,0 +1,355 @@
+package parser
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parquet physical & repetition types
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetFloat     = 4
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+)
+
+// parquetPhysicalTypes are the physical types of the schemas, by name in the message format
+var parquetPhysicalTypes = map[string]int32{
+	"boolean": parquetBoolean,
+	"int32":   parquetInt32,
+	"int64":   parquetInt64,
+	"float":   parquetFloat,
+	"double":  parquetDouble,
+	"binary":  parquetByteArray,
+}
+
+// parquetNode is a field of a Parquet schema: a group of fields, or a column of a physical type annotated with its logical type (ex: STRING, DATE, TIMESTAMP)
+type parquetNode struct {
+	name         string
+	repetition   int32
+	group        bool
+	children     []*parquetNode
+	physicalType int32
+	logicalType  string
+	// unit & utc are the unit of the timestamps & whether they are adjusted to UTC, scale & precision the ones of the decimals
+	unit      time.Duration
+	utc       bool
+	scale     int
+	precision int
+}
+
+// parquetSchema derives the Parquet schema of the parsed records
+func (c *csv) parquetSchema(records []map[string]interface{}) (*parquetNode, error) {
+	values := make([]interface{}, len(records))
+	for i, record := range records {
+		values[i] = record
+	}
+
+	root, err := parquetType("schema", values)
+	if err != nil {
+		return nil, err
+	}
+	// A CSV without records has no fields
+	if !root.group {
+		root = &parquetNode{name: "schema", group: true}
+	}
+	return root, nil
+}
+
+// parquetType returns the optional field of the values of a key, from its first non nil value
+// The objects are groups of their keys & the arrays groups of their indexes
+func parquetType(name string, values []interface{}) (*parquetNode, error) {
+	node := &parquetNode{name: name, repetition: parquetOptional, physicalType: parquetByteArray, logicalType: "STRING"}
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+		case int64:
+			node.physicalType, node.logicalType = parquetInt64, ""
+		case float64:
+			node.physicalType, node.logicalType = parquetDouble, ""
+		case bool:
+			node.physicalType, node.logicalType = parquetBoolean, ""
+		case time.Time:
+			node.physicalType, node.logicalType, node.unit, node.utc = parquetInt64, "TIMESTAMP", time.Millisecond, true
+		case map[string]interface{}, map[string]string:
+			// The values of all the objects by key
+			fieldValues := make(map[string][]interface{})
+			for _, object := range values {
+				for key, v := range toObject(object) {
+					fieldValues[key] = append(fieldValues[key], v)
+				}
+			}
+			keys := make([]string, 0, len(fieldValues))
+			for key := range fieldValues {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			return parquetGroup(node, keys, fieldValues)
+		case []string, []interface{}, []map[string]string, []map[string]interface{}:
+			// The items of all the arrays by index
+			var itemValues [][]interface{}
+			for _, array := range values {
+				for i, item := range toArray(array) {
+					if i == len(itemValues) {
+						itemValues = append(itemValues, nil)
+					}
+					itemValues[i] = append(itemValues[i], item)
+				}
+			}
+			indexes := make([]string, len(itemValues))
+			fieldValues := make(map[string][]interface{}, len(itemValues))
+			for i, items := range itemValues {
+				indexes[i] = strconv.Itoa(i)
+				fieldValues[indexes[i]] = items
+			}
+			return parquetGroup(node, indexes, fieldValues)
+		default:
+			return nil, errors.New("Unsupported Parquet value: " + string(mustJSON(v)))
+		}
+		return node, nil
+	}
+	// The fields without values are strings
+	return node, nil
+}
+
+// parquetGroup turns a node into the group of the fields of keys
+func parquetGroup(node *parquetNode, keys []string, fieldValues map[string][]interface{}) (*parquetNode, error) {
+	node.group, node.physicalType, node.logicalType = true, 0, ""
+	for _, key := range keys {
+		child, err := parquetType(key, fieldValues[key])
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+	}
+	return node, nil
+}
+
+// parquetSchemaParser parses the Parquet schemas in the message format (ex: message records { required int64 id; optional binary name (STRING); })
+type parquetSchemaParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseParquetSchema parses a Parquet schema in the message format
+func parseParquetSchema(schema string) (*parquetNode, error) {
+	p := &parquetSchemaParser{tokens: parquetTokens(schema)}
+	if err := p.expect("message"); err != nil {
+		return nil, err
+	}
+	root := &parquetNode{name: p.next(), group: true}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if root.children, err = p.parseFields(); err != nil {
+		return nil, err
+	}
+	if p.peek() == ";" {
+		p.next()
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New("Invalid Parquet schema: unexpected " + p.peek())
+	}
+	return root, nil
+}
+
+// parquetTokens splits a schema into names, keywords & punctuation
+func parquetTokens(schema string) []string {
+	var tokens []string
+	var token strings.Builder
+	flush := func() {
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+			token.Reset()
+		}
+	}
+
+	for _, r := range schema {
+		switch {
+		case strings.ContainsRune("{}();,=", r):
+			flush()
+			tokens = append(tokens, string(r))
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			token.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+func (p *parquetSchemaParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parquetSchemaParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *parquetSchemaParser) expect(token string) error {
+	if next := p.next(); !strings.EqualFold(next, token) {
+		if next == "" {
+			return errors.New("Invalid Parquet schema: missing " + token)
+		}
+		return errors.New("Invalid Parquet schema: expected " + token + " instead of " + next)
+	}
+	return nil
+}
+
+// parseFields parses the fields of a group, up to its closing brace
+func (p *parquetSchemaParser) parseFields() ([]*parquetNode, error) {
+	var fields []*parquetNode
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, errors.New("Invalid Parquet schema: missing }")
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	p.next()
+	return fields, nil
+}
+
+// parseField parses a field: its repetition, its group of fields or physical type, its name & its logical type
+func (p *parquetSchemaParser) parseField() (*parquetNode, error) {
+	node := &parquetNode{}
+	switch repetition := strings.ToLower(p.next()); repetition {
+	case "required":
+		node.repetition = parquetRequired
+	case "optional":
+		node.repetition = parquetOptional
+	case "repeated":
+		return nil, errors.New("Unsupported repeated field in the Parquet schema")
+	default:
+		return nil, errors.New("Invalid Parquet schema: invalid repetition " + repetition)
+	}
+
+	typeName := strings.ToLower(p.next())
+	node.name = p.next()
+	if node.name == "" || strings.ContainsAny(node.name, "{}();,=") {
+		return nil, errors.New("Invalid Parquet schema: missing field name")
+	}
+
+	if typeName == "group" {
+		node.group = true
+		var err error
+		if err = p.expect("{"); err != nil {
+			return nil, err
+		}
+		if node.children, err = p.parseFields(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+
+	physicalType, ok := parquetPhysicalTypes[typeName]
+	if !ok {
+		return nil, errors.New("Unsupported Parquet type: " + typeName)
+	}
+	node.physicalType = physicalType
+
+	if p.peek() == "(" {
+		p.next()
+		if err := p.parseLogicalType(node); err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+	// The field ids are ignored
+	if p.peek() == "=" {
+		p.next()
+		p.next()
+	}
+	return node, p.expect(";")
+}
+
+// parseLogicalType parses the logical type of a column, which has to match its physical type
+func (p *parquetSchemaParser) parseLogicalType(node *parquetNode) error {
+	name := strings.ToUpper(p.next())
+	var args []string
+	if p.peek() == "(" {
+		p.next()
+		for p.peek() != ")" {
+			if p.peek() == "" {
+				return errors.New("Invalid Parquet schema: missing )")
+			}
+			if arg := p.next(); arg != "," {
+				args = append(args, arg)
+			}
+		}
+		p.next()
+	}
+
+	node.logicalType = name
+	physicalType := int32(parquetByteArray)
+	switch name {
+	case "STRING", "UTF8", "JSON", "ENUM":
+		if name == "UTF8" {
+			node.logicalType = "STRING"
+		}
+	case "DATE":
+		physicalType = parquetInt32
+	case "TIMESTAMP_MILLIS", "TIMESTAMP_MICROS":
+		node.logicalType, node.utc, node.unit = "TIMESTAMP", true, time.Millisecond
+		if name == "TIMESTAMP_MICROS" {
+			node.unit = time.Microsecond
+		}
+		physicalType = parquetInt64
+	case "TIMESTAMP":
+		if len(args) != 2 {
+			return errors.New("Invalid Parquet schema: TIMESTAMP takes a unit & whether it is adjusted to UTC")
+		}
+		units := map[string]time.Duration{"MILLIS": time.Millisecond, "MICROS": time.Microsecond, "NANOS": time.Nanosecond}
+		unit, ok := units[strings.ToUpper(args[0])]
+		utc, err := strconv.ParseBool(args[1])
+		if !ok || err != nil {
+			return errors.New("Invalid Parquet schema: invalid TIMESTAMP(" + strings.Join(args, ",") + ")")
+		}
+		node.unit, node.utc = unit, utc
+		physicalType = parquetInt64
+	case "DECIMAL":
+		if len(args) != 2 {
+			return errors.New("Invalid Parquet schema: DECIMAL takes a precision & a scale")
+		}
+		precision, err := strconv.Atoi(args[0])
+		scale, scaleErr := strconv.Atoi(args[1])
+		if err != nil || scaleErr != nil || precision < 1 || scale < 0 || scale > precision {
+			return errors.New("Invalid Parquet schema: invalid DECIMAL(" + strings.Join(args, ",") + ")")
+		}
+		node.precision, node.scale = precision, scale
+		// The decimals are stored as integers of their precision
+		if node.physicalType == parquetInt32 || node.physicalType == parquetInt64 {
+			physicalType = node.physicalType
+		}
+	default:
+		return errors.New("Unsupported Parquet logical type: " + name)
+	}
+
+	if node.physicalType != physicalType && !(node.logicalType == "TIMESTAMP" && node.physicalType == parquetInt64) {
+		return errors.New("Invalid Parquet schema: " + name + " doesn't annotate the type of " + node.name)
+	}
+	return nil
+}
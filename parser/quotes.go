@@ -0,0 +1,43 @@
+package parser
+
+import "strings"
+
+// QuoteMode is the cleanup of the double quotes of the values
+type QuoteMode int
+
+// Quote modes
+// QuotesRemove removes all the double quotes of the values. This is the default mode
+// QuotesTrim removes the double quotes enclosing the values & unescapes their doubled quotes (ex: "5'10"" tall" gives 5'10" tall), keeping their other quotes
+// QuotesKeep keeps the values as they are
+const (
+	QuotesRemove QuoteMode = iota
+	QuotesTrim
+	QuotesKeep
+)
+
+// cleanRecord returns a copy of a record whose quotes are cleaned up, or the record itself when they are kept
+func (c *csv) cleanRecord(record map[string]string) map[string]string {
+	if c.options.Quotes == QuotesKeep {
+		return record
+	}
+
+	cleaned := make(map[string]string, len(record))
+	for k, v := range record {
+		cleaned[k] = c.cleanQuotes(v)
+	}
+	return cleaned
+}
+
+// cleanQuotes cleans up the double quotes of a value, as selected by the Quotes mode
+func (c *csv) cleanQuotes(value string) string {
+	switch c.options.Quotes {
+	case QuotesKeep:
+		return value
+	case QuotesTrim:
+		if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+			return strings.ReplaceAll(value[1:len(value)-1], "\"\"", "\"")
+		}
+		return value
+	}
+	return strings.Replace(value, "\"", "", -1)
+}
@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ParseMode is the handling of the records with invalid cells
+type ParseMode int
+
+// Parse modes
+// ParseStrict fails the parsing when a record has invalid cells. This is the default mode
+// ParseLenient skips the records with invalid cells & adds their errors to the Report, so that the valid records are still returned
+const (
+	ParseStrict ParseMode = iota
+	ParseLenient
+)
+
+// ParseReport collects the invalid cells of the records skipped by the lenient parsings
+// It is safe for concurrent use, so that it can be shared by several parsings
+type ParseReport struct {
+	mu      sync.Mutex
+	skipped []ParseError
+}
+
+// Skipped returns the invalid cells of the records skipped so far
+func (r *ParseReport) Skipped() []ParseError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]ParseError(nil), r.skipped...)
+}
+
+func (r *ParseReport) add(parseErrs ParseErrors) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, parseErr := range parseErrs {
+		r.skipped = append(r.skipped, *parseErr)
+	}
+}
+
+// skipRecord adds the invalid cells of a record to the report of a lenient parsing
+// It returns false when the error isn't about the cells of the record, which fails the parsing
+func (c *csv) skipRecord(err error) bool {
+	var parseErrs ParseErrors
+	if c.options.Mode != ParseLenient || !errors.As(err, &parseErrs) {
+		return false
+	}
+
+	for _, parseErr := range parseErrs {
+		c.options.Logger.Warn("Invalid record skipped", "row", parseErr.Row, "column", parseErr.Column, "reason", parseErr.Reason)
+	}
+	if c.options.Report != nil {
+		c.options.Report.add(parseErrs)
+	}
+	return true
+}
+
+// decodeLenient maps the parsed records into the slice res points to one at a time, skipping the records which can't be mapped
+// rows are the rows of the records in the CSV data
+func (c *csv) decodeLenient(ctx context.Context, input []map[string]interface{}, rows []int, res interface{}) error {
+	slice := reflect.ValueOf(res)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return c.decode(ctx, input, res)
+	}
+	slice = slice.Elem()
+	decoded := reflect.MakeSlice(slice.Type(), 0, len(input))
+
+	for i, recordMap := range input {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		element := reflect.New(slice.Type().Elem())
+		err := withRow(c.decode(ctx, recordMap, element.Interface()), rows[i])
+		if c.skipRecord(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		decoded = reflect.Append(decoded, element.Elem())
+	}
+
+	slice.Set(decoded)
+	return nil
+}
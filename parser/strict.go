@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// checkColumns checks the columns of the record structure against the ColumnKinds, as selected by ErrorUnused & ErrorUnset
+func (c *csv) checkColumns(recordStructure RecordStructure) error {
+	if len(c.options.ColumnKinds) == 0 || (!c.options.ErrorUnused && !c.options.ErrorUnset) {
+		return nil
+	}
+
+	// The columns by name without their indexes, like the ColumnKinds
+	columns := make(map[string]bool)
+	for key, subKeys := range recordStructure {
+		if len(subKeys) == 0 || (len(subKeys) == 1 && subKeys[0] == "") {
+			columns[key] = true
+			continue
+		}
+		for _, subKey := range subKeys {
+			columns[key+c.options.ArrayDelimiter+subKey] = true
+		}
+	}
+
+	if c.options.ErrorUnused {
+		var unknown []string
+		for column := range columns {
+			if _, ok := c.options.ColumnKinds[column]; !ok {
+				unknown = append(unknown, column)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return errors.New("Unknown columns: " + strings.Join(unknown, ", "))
+		}
+	}
+
+	if c.options.ErrorUnset {
+		var missing []string
+		for column := range c.options.ColumnKinds {
+			if !columns[column] {
+				missing = append(missing, column)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return errors.New("Missing columns: " + strings.Join(missing, ", "))
+		}
+	}
+	return nil
+}
+
+// checkFields checks that the keys of a map decoded into a struct are set for all the fields of the struct
+func (c *csv) checkFields(t reflect.Type, data interface{}) error {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range c.fieldNames(t) {
+		found := false
+		for key := range object {
+			// Like the struct mapping, the names of the fields are case insensitive
+			if strings.EqualFold(key, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("Missing columns: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// fieldNames returns the names of the exported fields of a struct, from their StructTag, including the fields of the squashed & embedded structs
+func (c *csv) fieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, c.structFieldNames(t.Field(i))...)
+	}
+	return names
+}
+
+// structFieldNames returns the name of a field, or the names of the fields of a squashed or embedded struct
+func (c *csv) structFieldNames(field reflect.StructField) []string {
+	if field.PkgPath != "" {
+		return nil
+	}
+
+	tag := field.Tag.Get(c.options.StructTag)
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return nil
+	}
+	if field.Type.Kind() == reflect.Struct && strings.Contains(options, "squash") {
+		return c.fieldNames(field.Type)
+	}
+	if t, ok := c.embeddedStruct(field); ok {
+		return c.fieldNames(t)
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return []string{name}
+}
@@ -0,0 +1,80 @@
+package parser
+
+import "context"
+
+// ToStructs parses CSV into a slice of structs of type T, with the parser options
+func ToStructs[T any](ctx context.Context, csvData []map[string]string, options CSVOptions) ([]T, error) {
+	res := make([]T, 0, len(csvData))
+	if len(csvData) == 0 {
+		return res, nil
+	}
+
+	err := NewCSV(options).ToStruct(ctx, csvData, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ToStructStream parses the records received on records into structs of type T one at a time, so that huge CSVs are never held in memory (ex: with the records of the streaming reader)
+// The structure of the records is taken from the first one. Both channels are closed once records is closed or the parsing fails. At most one error is sent on the error channel
+func ToStructStream[T any](ctx context.Context, records <-chan map[string]string, options CSVOptions) (<-chan T, <-chan error) {
+	res := make(chan T)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(res)
+		defer close(errs)
+
+		c := NewCSV(options).(*csv)
+		var structure RecordStructure
+		for row := 0; ; row++ {
+			var record map[string]string
+			var ok bool
+			select {
+			case record, ok = <-records:
+				if !ok {
+					return
+				}
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+
+			var err error
+			if structure == nil {
+				structure, err = c.Structure(ctx, record)
+				if err != nil {
+					errs <- err
+					return
+				}
+				c = c.withKeys(record, structure)
+			}
+
+			recordMap, err := c.ParseRecord(ctx, structure, record)
+			var value T
+			if err == nil {
+				err = c.decode(ctx, recordMap, &value)
+			}
+			if err != nil {
+				// The invalid records are skipped in the ParseLenient mode
+				err = withRow(err, row)
+				if c.skipRecord(err) {
+					continue
+				}
+				errs <- err
+				return
+			}
+
+			select {
+			case res <- value:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return res, errs
+}
@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"strconv"
+	"time"
+)
+
+// LayoutUnix is the time layout of the Unix timestamps in seconds
+const LayoutUnix = "unix"
+
+// parseTime parses a time value with the first of the layouts which accepts it, in the Location
+// The layouts default to the TimeLayouts option
+func (c *csv) parseTime(value string, layouts []string) (time.Time, error) {
+	if len(c.options.TimeLayouts) > 0 {
+		layouts = c.options.TimeLayouts
+	}
+
+	var err error
+	for _, layout := range layouts {
+		if layout == LayoutUnix {
+			var seconds int64
+			if seconds, err = strconv.ParseInt(value, 10, 64); err == nil {
+				return time.Unix(seconds, 0).In(c.options.Location), nil
+			}
+			continue
+		}
+
+		var parsed time.Time
+		if parsed, err = time.ParseInLocation(layout, value, c.options.Location); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseDuration parses a Go duration (ex: 1h30m) or a number of seconds (ex: 90)
+func parseDuration(value string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+	return time.ParseDuration(value)
+}
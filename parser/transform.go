@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Transformer normalizes the values of a column before they are parsed
+type Transformer func(value string) string
+
+// Built-in transformers
+// Trim removes the leading & trailing white space. Upper & Lower change the case of the letters
+// StripCurrency removes the currency symbol or code of the money values (ex: "$1,299.00" gives "1,299.00")
+var (
+	Trim          Transformer = strings.TrimSpace
+	Upper         Transformer = strings.ToUpper
+	Lower         Transformer = strings.ToLower
+	StripCurrency Transformer = func(value string) string {
+		amount, _ := splitCurrency(value)
+		return amount
+	}
+)
+
+// Replace returns a transformer replacing the matches of a pattern with a replacement, which may refer to the submatches (ex: $1)
+func Replace(pattern *regexp.Regexp, replacement string) Transformer {
+	return func(value string) string {
+		return pattern.ReplaceAllString(value, replacement)
+	}
+}
+
+// transform applies the transformers of a column to a value, in order
+// The transformers are those of the column name, or else of the column name without its indexes like the ColumnKinds
+func (c *csv) transform(k string, value string) string {
+	transformers, ok := c.options.Transformers[k]
+	if !ok {
+		transformers = c.options.Transformers[c.columnPath(k)]
+	}
+	for _, transformer := range transformers {
+		value = transformer(value)
+	}
+	return value
+}
+
+// columnPath returns the name of a column without its indexes (ex: company.name for company.0.name)
+func (c *csv) columnPath(k string) string {
+	if c.keys != nil {
+		if path, ok := c.keys.paths[k]; ok {
+			return path
+		}
+	}
+	return c.parseColumnPath(k)
+}
+
+func (c *csv) parseColumnPath(k string) string {
+	var path []string
+	for _, part := range c.splitKey(k) {
+		if _, err := strconv.Atoi(part); err == nil && len(path) > 0 {
+			continue
+		}
+		path = append(path, part)
+	}
+	return strings.Join(path, c.options.ArrayDelimiter)
+}
@@ -0,0 +1,39 @@
+package parser
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// isUnmarshaler checks if the pointers to a type implement encoding.TextUnmarshaler or json.Unmarshaler
+func isUnmarshaler(t reflect.Type) bool {
+	ptr := reflect.PointerTo(t)
+	return ptr.Implements(textUnmarshalerType) || ptr.Implements(jsonUnmarshalerType)
+}
+
+// unmarshalValue decodes a value into a field implementing encoding.TextUnmarshaler, or else json.Unmarshaler
+// The values which aren't valid JSON are unmarshaled as JSON strings
+func unmarshalValue(value string, t reflect.Type) (interface{}, error) {
+	unmarshaled := reflect.New(t)
+	if unmarshaler, ok := unmarshaled.Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return nil, err
+		}
+		return unmarshaled.Elem().Interface(), nil
+	}
+
+	data := []byte(value)
+	if !json.Valid(data) {
+		data = mustJSON(value)
+	}
+	if err := unmarshaled.Interface().(json.Unmarshaler).UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return unmarshaled.Elem().Interface(), nil
+}
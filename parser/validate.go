@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Rule is the validation rule of the values of a column. The empty values are only checked by Required
+// Required fails the empty values. Pattern fails the values it doesn't match
+// Min & Max are the bounds of the numeric values, read with the DecimalSeparator & ThousandsSeparator. MinLength & MaxLength are the bounds of the lengths of the values in characters, when not 0
+// Enum are the only values allowed, if any. Func is a custom check of the values, which fails them with its error
+type Rule struct {
+	Required  bool
+	Pattern   *regexp.Regexp
+	Min       *float64
+	Max       *float64
+	MinLength int
+	MaxLength int
+	Enum      []string
+	Func      func(value string) error
+}
+
+// validate checks a value against the rule of its column
+func (c *csv) validate(value string, column string) *ParseError {
+	rule, ok := c.options.Rules[column]
+	if !ok {
+		return nil
+	}
+
+	invalid := func(reason string, err error) *ParseError {
+		c.options.Logger.Warn("Validation failed", "column", column, "value", value, "reason", reason)
+		return &ParseError{Column: column, Value: value, Reason: reason, Err: err}
+	}
+
+	if value == "" {
+		if rule.Required {
+			return invalid("Required value", nil)
+		}
+		return nil
+	}
+
+	if rule.Pattern != nil && !rule.Pattern.MatchString(value) {
+		return invalid("Value not matching "+rule.Pattern.String(), nil)
+	}
+
+	if rule.Min != nil || rule.Max != nil {
+		number, err := strconv.ParseFloat(c.normalizeNumber(value), 64)
+		if err != nil {
+			return invalid("Invalid number", err)
+		}
+		if rule.Min != nil && number < *rule.Min {
+			return invalid("Value below "+strconv.FormatFloat(*rule.Min, 'f', -1, 64), nil)
+		}
+		if rule.Max != nil && number > *rule.Max {
+			return invalid("Value above "+strconv.FormatFloat(*rule.Max, 'f', -1, 64), nil)
+		}
+	}
+
+	length := utf8.RuneCountInString(value)
+	if rule.MinLength > 0 && length < rule.MinLength {
+		return invalid("Value shorter than "+strconv.Itoa(rule.MinLength)+" characters", nil)
+	}
+	if rule.MaxLength > 0 && length > rule.MaxLength {
+		return invalid("Value longer than "+strconv.Itoa(rule.MaxLength)+" characters", nil)
+	}
+
+	if len(rule.Enum) > 0 && !contains(rule.Enum, value) {
+		return invalid("Value not allowed", nil)
+	}
+
+	if rule.Func != nil {
+		if err := rule.Func(value); err != nil {
+			return invalid(err.Error(), err)
+		}
+	}
+	return nil
+}
+
+// contains checks if a value is one of the values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ToXML parses CSV into XML, with a rootElement holding a recordElement per record. Their names are made valid like the names of the keys
+// The keys of the records are elements, in the order of the Headers when set or sorted by name, & the elements of the arrays are repeated elements named after their key
+func (c *csv) ToXML(ctx context.Context, csvData []map[string]string, rootElement string, recordElement string) (string, error) {
+	convertedToMap, err := c.ToMap(ctx, csvData)
+	if err != nil {
+		return "", err
+	}
+
+	var order map[string]int
+	if len(c.options.Headers) > 0 {
+		order = c.columnOrder()
+	}
+
+	var convertedToXML strings.Builder
+	convertedToXML.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&convertedToXML)
+
+	root := xml.StartElement{Name: xml.Name{Local: xmlName(rootElement)}}
+	if err = encoder.EncodeToken(root); err != nil {
+		return "", err
+	}
+	for _, record := range convertedToMap {
+		if err = ctx.Err(); err != nil {
+			return "", err
+		}
+		if err = c.writeXML(encoder, recordElement, record, "", order); err != nil {
+			return "", err
+		}
+	}
+	if err = encoder.EncodeToken(root.End()); err != nil {
+		return "", err
+	}
+	if err = encoder.Flush(); err != nil {
+		return "", err
+	}
+
+	return convertedToXML.String(), nil
+}
+
+// writeXML writes a value of a record as an element, at the path of the value in the record
+func (c *csv) writeXML(encoder *xml.Encoder, name string, value interface{}, path string, order map[string]int) error {
+	// The arrays are repeated elements
+	switch v := value.(type) {
+	case []string:
+		for _, element := range v {
+			if err := c.writeXML(encoder, name, element, path, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		for _, element := range v {
+			if err := c.writeXML(encoder, name, element, path, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []map[string]string:
+		for _, element := range v {
+			if err := c.writeXML(encoder, name, element, path, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []map[string]interface{}:
+		for _, element := range v {
+			if err := c.writeXML(encoder, name, element, path, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		c.sortKeys(keys, path, order)
+		for _, key := range keys {
+			if err := c.writeXML(encoder, key, v[key], c.childPath(path, key), order); err != nil {
+				return err
+			}
+		}
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		c.sortKeys(keys, path, order)
+		for _, key := range keys {
+			if err := c.writeXML(encoder, key, v[key], c.childPath(path, key), order); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		text, err := xmlText(v)
+		if err != nil {
+			return err
+		}
+		if err = encoder.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlText returns the text of a value
+func xmlText(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339), nil
+	}
+
+	// The other values are written like in the JSON
+	text, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(string(text), "\""), nil
+}
+
+// xmlName turns a key into a valid element name, replacing the invalid characters with underscores (ex: "unit price" gives unit_price)
+func xmlName(key string) string {
+	var name strings.Builder
+	for i, r := range key {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		case i == 0 && unicode.IsDigit(r):
+			name.WriteRune('_')
+		default:
+			r = '_'
+		}
+		name.WriteRune(r)
+	}
+	if name.Len() == 0 {
+		return "_"
+	}
+	return name.String()
+}
@@ -0,0 +1,29 @@
+package parser
+
+import (
+	"context"
+	"testing"
+)
+
+func TestToXMLEmpty(t *testing.T) {
+	res, err := NewCSV(CSVOptions{}).ToXML(context.Background(), []map[string]string{}, "records", "record")
+	if err != nil {
+		t.Fatalf("ToXML() error = %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<records></records>`
+	if res != expected {
+		t.Errorf("ToXML() = %s, want %s", res, expected)
+	}
+}
+
+func TestToXMLElementNames(t *testing.T) {
+	csvData := []map[string]string{{"unit price": "2"}}
+	res, err := NewCSV(CSVOptions{}).ToXML(context.Background(), csvData, "2024 records", "a record")
+	if err != nil {
+		t.Fatalf("ToXML() error = %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<_2024_records><a_record><unit_price>2</unit_price></a_record></_2024_records>`
+	if res != expected {
+		t.Errorf("ToXML() = %s, want %s", res, expected)
+	}
+}
@@ -0,0 +1,29 @@
+package reader
+
+import "net/http"
+
+// BasicAuth consists of the credentials for HTTP basic authentication
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// prepareRequest adds the configured headers & authentication to a URL request
+func (c *csv) prepareRequest(req *http.Request) error {
+	for name, value := range c.options.HTTPHeaders {
+		req.Header.Set(name, value)
+	}
+
+	if c.options.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.options.BearerToken)
+	}
+	if c.options.BasicAuth != nil {
+		req.SetBasicAuth(c.options.BasicAuth.Username, c.options.BasicAuth.Password)
+	}
+
+	if c.options.RequestHook != nil {
+		return c.options.RequestHook(req)
+	}
+
+	return nil
+}
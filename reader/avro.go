@@ -0,0 +1,426 @@
+package reader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+var errInvalidAvroData = errors.New("Invalid Avro file: invalid data")
+
+var avroPrimitives = map[string]bool{
+	"null": true, "boolean": true, "int": true, "long": true, "float": true, "double": true, "bytes": true, "string": true,
+}
+
+// AvroOptions consists of the Avro reader options available
+// CSVOptions apply to the records of the file, except for the ones specific to text (ex: Delimiter, Encoding)
+// KeyDelimiter joins the names of nested records, the keys of maps & the indexes of arrays into column names (ex: company.0.name), which matches the array delimiter of the parser. Default value is "."
+type AvroOptions struct {
+	CSVOptions
+	KeyDelimiter string
+}
+
+// Avro is a lightweight interface for reading Avro container files
+// The records are decoded with the schema embedded in the file & flattened into records, with the fields in the order of the schema. Nulls are empty strings
+// The blocks may be uncompressed or compressed with the deflate or snappy codecs
+type Avro interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, src io.Reader) (RecordIterator, error)
+}
+
+type avro struct {
+	csv     *csv
+	options AvroOptions
+}
+
+// FromPath reads an Avro file from a file path
+func (a *avro) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	it, err := a.open(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads an Avro file from an io.Reader
+func (a *avro) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	it, err := a.open(ctx, "", r)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the Avro file read from src
+// The blocks of the file are read one at a time
+func (a *avro) Open(ctx context.Context, src io.Reader) (RecordIterator, error) {
+	return a.open(ctx, "", src)
+}
+
+func (a *avro) open(ctx context.Context, source string, src io.Reader) (*recordIterator, error) {
+	input, err := a.csv.openText(src)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := newAvroRowReader(input.text, a.options.KeyDelimiter)
+	if err != nil {
+		input.data.Close()
+		return nil, err
+	}
+
+	return a.csv.newTextIterator(ctx, source, input, rows)
+}
+
+// avroRowReader flattens the records decoded by goavro into keyed rows
+// The schema of the file orders the fields & formats the values of the logical types
+type avroRowReader struct {
+	ocf          *goavro.OCFReader
+	schema       interface{}
+	named        map[string]interface{}
+	keyDelimiter string
+	line         int
+
+	keys   []string
+	fields map[string]string
+}
+
+func newAvroRowReader(r io.Reader, keyDelimiter string) (*avroRowReader, error) {
+	ocf, err := goavro.NewOCFReader(r)
+	if err != nil {
+		return nil, errors.New("Invalid Avro file: " + err.Error())
+	}
+
+	var schema interface{}
+	if err = json.Unmarshal([]byte(ocf.Codec().Schema()), &schema); err != nil {
+		return nil, errors.New("Invalid Avro schema: " + err.Error())
+	}
+	rows := &avroRowReader{
+		ocf:          ocf,
+		schema:       schema,
+		named:        make(map[string]interface{}),
+		keyDelimiter: keyDelimiter,
+	}
+	rows.register(schema, "")
+
+	if definition, _ := rows.resolve(schema, ""); avroType(definition) != "record" {
+		return nil, errors.New("Unsupported Avro schema: the top-level type should be a record")
+	}
+	return rows, nil
+}
+
+func (r *avroRowReader) Read() ([]string, error) {
+	if !r.ocf.Scan() {
+		if err := r.ocf.Err(); err != nil {
+			return nil, errors.New("Invalid Avro file: " + err.Error())
+		}
+		return nil, io.EOF
+	}
+	record, err := r.ocf.Read()
+	if err != nil {
+		return nil, errors.New("Invalid Avro file: " + err.Error())
+	}
+
+	r.keys = nil
+	r.fields = make(map[string]string)
+	if err = r.flatten(r.schema, record, "", ""); err != nil {
+		return nil, err
+	}
+	r.line++
+
+	fields := make([]string, len(r.keys))
+	for i, key := range r.keys {
+		fields[i] = r.fields[key]
+	}
+	return fields, nil
+}
+
+func (r *avroRowReader) Line() int {
+	return r.line
+}
+
+func (r *avroRowReader) Keys() []string {
+	return r.keys
+}
+
+// register records the named types of a schema by full name, so that the references to them can be resolved
+func (r *avroRowReader) register(schema interface{}, namespace string) {
+	switch s := schema.(type) {
+	case []interface{}:
+		for _, branch := range s {
+			r.register(branch, namespace)
+		}
+	case map[string]interface{}:
+		switch avroType(s) {
+		case "record", "error", "enum", "fixed":
+			fullName := avroFullName(s, namespace)
+			r.named[fullName] = s
+			fields, _ := s["fields"].([]interface{})
+			for _, field := range fields {
+				if fieldDefinition, ok := field.(map[string]interface{}); ok {
+					r.register(fieldDefinition["type"], avroNamespace(fullName))
+				}
+			}
+		case "array":
+			r.register(s["items"], namespace)
+		case "map":
+			r.register(s["values"], namespace)
+		default:
+			r.register(s["type"], namespace)
+		}
+	}
+}
+
+// resolve returns the definition of a schema which references a named type, along with its namespace
+func (r *avroRowReader) resolve(schema interface{}, namespace string) (interface{}, string) {
+	name, ok := schema.(string)
+	if !ok {
+		return schema, namespace
+	}
+	if fullName, ok := r.lookup(name, namespace); ok {
+		return r.named[fullName], avroNamespace(fullName)
+	}
+	return schema, namespace
+}
+
+// lookup returns the full name of a named type referenced in a namespace
+func (r *avroRowReader) lookup(name string, namespace string) (string, bool) {
+	for _, fullName := range []string{namespace + "." + name, name} {
+		if _, ok := r.named[fullName]; ok {
+			return fullName, true
+		}
+	}
+	return "", false
+}
+
+// flatten adds a decoded value to the fields of the record, with the keys joined by the key delimiter
+func (r *avroRowReader) flatten(schema interface{}, value interface{}, prefix string, namespace string) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + r.keyDelimiter + key
+	}
+
+	schema, namespace = r.resolve(schema, namespace)
+	switch s := schema.(type) {
+	case []interface{}:
+		// goavro decodes the non null values of the unions as a map of the name of their branch to their value
+		if value == nil {
+			r.add(prefix, "")
+			return nil
+		}
+		branchValue, ok := value.(map[string]interface{})
+		if !ok || len(branchValue) != 1 {
+			return errInvalidAvroData
+		}
+		for name, v := range branchValue {
+			for _, branch := range s {
+				if r.isBranch(branch, name, namespace) {
+					return r.flatten(branch, v, prefix, namespace)
+				}
+			}
+		}
+		return errInvalidAvroData
+
+	case map[string]interface{}:
+		switch avroType(s) {
+		case "record", "error":
+			record, ok := value.(map[string]interface{})
+			if !ok {
+				return errInvalidAvroData
+			}
+			// The types of the fields are in the namespace of the record
+			namespace = avroNamespace(avroFullName(s, namespace))
+			fields, _ := s["fields"].([]interface{})
+			for _, field := range fields {
+				fieldDefinition, _ := field.(map[string]interface{})
+				name, _ := fieldDefinition["name"].(string)
+				if err := r.flatten(fieldDefinition["type"], record[name], join(name), namespace); err != nil {
+					return err
+				}
+			}
+			return nil
+
+		case "array":
+			items, ok := value.([]interface{})
+			if !ok {
+				return errInvalidAvroData
+			}
+			for i, item := range items {
+				if err := r.flatten(s["items"], item, join(strconv.Itoa(i)), namespace); err != nil {
+					return err
+				}
+			}
+			return nil
+
+		case "map":
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return errInvalidAvroData
+			}
+			// goavro doesn't keep the order of the keys of the maps
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if err := r.flatten(s["values"], values[key], join(key), namespace); err != nil {
+					return err
+				}
+			}
+			return nil
+
+		case "enum", "fixed":
+			r.add(prefix, formatAvroValue(value, s))
+			return nil
+		}
+
+		// The other definitions annotate a primitive type (ex: a logical type), or wrap another type
+		if kind := avroType(s); avroPrimitives[kind] {
+			r.add(prefix, formatAvroValue(value, s))
+			return nil
+		}
+		return r.flatten(s["type"], value, prefix, namespace)
+	}
+
+	r.add(prefix, formatAvroValue(value, nil))
+	return nil
+}
+
+// isBranch checks if a branch of a union is the one goavro names name: a primitive, logical (ex: long.timestamp-millis), complex or named type
+func (r *avroRowReader) isBranch(branch interface{}, name string, namespace string) bool {
+	if s, ok := branch.(string); ok {
+		if fullName, ok := r.lookup(s, namespace); ok {
+			return fullName == name
+		}
+		return s == name
+	}
+
+	s, ok := branch.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	switch kind := avroType(s); kind {
+	case "record", "error", "enum", "fixed":
+		return avroFullName(s, namespace) == name
+	case "":
+		return false
+	default:
+		logical, _ := s["logicalType"].(string)
+		return kind == name || kind+"."+logical == name
+	}
+}
+
+func (r *avroRowReader) add(key string, value string) {
+	if _, ok := r.fields[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.fields[key] = value
+}
+
+// formatAvroValue formats a value decoded by goavro, with the logical type of its definition
+// The logical types which goavro doesn't decode are formatted from their underlying values (ex: local-timestamp-millis, uuid)
+func formatAvroValue(value interface{}, definition map[string]interface{}) string {
+	logical, _ := definition["logicalType"].(string)
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		switch logical {
+		case "timestamp-nanos", "local-timestamp-nanos":
+			return formatTimestamp(v, time.Nanosecond, logical == "timestamp-nanos")
+		case "local-timestamp-millis":
+			return formatTimestamp(v, time.Millisecond, false)
+		case "local-timestamp-micros":
+			return formatTimestamp(v, time.Microsecond, false)
+		}
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	case string:
+		return v
+	case []byte:
+		if logical == "uuid" && len(v) == 16 {
+			return formatUUID(v)
+		}
+		return string(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return formatTime(int64(v), time.Nanosecond)
+	case *big.Rat:
+		scale, _ := definition["scale"].(float64)
+		return v.FloatString(int(scale))
+	}
+	return ""
+}
+
+// avroType returns the type of a definition (ex: record, array), or "" if it isn't one
+func avroType(definition interface{}) string {
+	s, ok := definition.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	kind, _ := s["type"].(string)
+	return kind
+}
+
+// avroFullName returns the full name of a named type, in its own namespace or else the enclosing one
+func avroFullName(definition map[string]interface{}, namespace string) string {
+	name, _ := definition["name"].(string)
+	if ns, ok := definition["namespace"].(string); ok {
+		namespace = ns
+	}
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+	return namespace + "." + name
+}
+
+// avroNamespace returns the namespace of a full name
+func avroNamespace(fullName string) string {
+	if i := strings.LastIndex(fullName, "."); i >= 0 {
+		return fullName[:i]
+	}
+	return ""
+}
+
+// NewAvro is the initialization method for the Avro reader
+func NewAvro(options AvroOptions) Avro {
+	if options.KeyDelimiter == "" {
+		options.KeyDelimiter = "."
+	}
+	// Avro files are binary
+	options.Encoding = ""
+
+	return &avro{
+		csv:     NewCSV(options.CSVOptions).(*csv),
+		options: options,
+	}
+}
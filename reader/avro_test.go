@@ -0,0 +1,175 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+const avroTestSchema = `{
+	"type": "record",
+	"name": "Order",
+	"namespace": "shop",
+	"fields": [
+		{"name": "id", "type": "long"},
+		{"name": "note", "type": ["null", "string"]},
+		{"name": "customer", "type": {"type": "record", "name": "Customer", "fields": [
+			{"name": "name", "type": "string"},
+			{"name": "status", "type": {"type": "enum", "name": "Status", "symbols": ["ACTIVE", "CLOSED"]}}
+		]}},
+		{"name": "previous", "type": ["null", "Customer"]},
+		{"name": "tags", "type": {"type": "array", "items": "string"}},
+		{"name": "prices", "type": {"type": "map", "values": "double"}},
+		{"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "due", "type": ["null", {"type": "int", "logicalType": "date"}]},
+		{"name": "total", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}}
+	]
+}`
+
+func writeAvro(t *testing.T, codec string, records ...map[string]interface{}) []byte {
+	t.Helper()
+
+	var file bytes.Buffer
+	writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &file, Schema: avroTestSchema, CompressionName: codec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	natives := make([]interface{}, len(records))
+	for i, record := range records {
+		natives[i] = record
+	}
+	if err = writer.Append(natives); err != nil {
+		t.Fatal(err)
+	}
+	return file.Bytes()
+}
+
+func avroTestRecords() []map[string]interface{} {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []map[string]interface{}{
+		{
+			"id":       int64(1),
+			"note":     goavro.Union("string", "gift"),
+			"customer": map[string]interface{}{"name": "Ada", "status": "ACTIVE"},
+			"previous": goavro.Union("shop.Customer", map[string]interface{}{"name": "Bob", "status": "CLOSED"}),
+			"tags":     []interface{}{"a", "b"},
+			"prices":   map[string]interface{}{"eur": 2.5, "chf": 3.0},
+			"created":  created,
+			"due":      goavro.Union("int.date", created.AddDate(0, 0, 30)),
+			"total":    big.NewRat(1234, 100),
+		},
+		{
+			"id":       int64(2),
+			"note":     nil,
+			"customer": map[string]interface{}{"name": "Cy", "status": "CLOSED"},
+			"previous": nil,
+			"tags":     []interface{}{},
+			"prices":   map[string]interface{}{},
+			"created":  created,
+			"due":      nil,
+			"total":    big.NewRat(-5, 1),
+		},
+	}
+}
+
+func TestAvroFromReader(t *testing.T) {
+	expected := []map[string]string{
+		{
+			"id":              "1",
+			"note":            "gift",
+			"customer.name":   "Ada",
+			"customer.status": "ACTIVE",
+			"previous.name":   "Bob",
+			"previous.status": "CLOSED",
+			"tags.0":          "a",
+			"tags.1":          "b",
+			"prices.chf":      "3",
+			"prices.eur":      "2.5",
+			"created":         "2024-01-02T03:04:05Z",
+			"due":             "2024-02-01T00:00:00Z",
+			"total":           "12.34",
+		},
+		{
+			"id":              "2",
+			"note":            "",
+			"customer.name":   "Cy",
+			"customer.status": "CLOSED",
+			"previous":        "",
+			"created":         "2024-01-02T03:04:05Z",
+			"due":             "",
+			"total":           "-5.00",
+		},
+	}
+
+	for _, codec := range []string{goavro.CompressionNullLabel, goavro.CompressionDeflateLabel, goavro.CompressionSnappyLabel} {
+		t.Run(codec, func(t *testing.T) {
+			file := writeAvro(t, codec, avroTestRecords()...)
+
+			records, err := NewAvro(AvroOptions{}).FromReader(context.Background(), bytes.NewReader(file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, expected) {
+				t.Errorf("FromReader() = %v, want %v", records, expected)
+			}
+		})
+	}
+}
+
+func TestAvroRowReaderKeys(t *testing.T) {
+	file := writeAvro(t, goavro.CompressionNullLabel, avroTestRecords()[0])
+
+	rows, err := newAvroRowReader(bytes.NewReader(file), "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rows.Read(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The keys are in the order of the schema, & the keys of the maps are sorted
+	expected := []string{
+		"id", "note", "customer_name", "customer_status", "previous_name", "previous_status",
+		"tags_0", "tags_1", "prices_chf", "prices_eur", "created", "due", "total",
+	}
+	if keys := rows.Keys(); !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Keys() = %v, want %v", keys, expected)
+	}
+	if _, err = rows.Read(); err != io.EOF {
+		t.Errorf("Read() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestAvroInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"not avro", []byte("id,name\n1,a\n")},
+		{"not a record", func() []byte {
+			var file bytes.Buffer
+			writer, err := goavro.NewOCFWriter(goavro.OCFConfig{W: &file, Schema: `"long"`})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = writer.Append([]interface{}{int64(1)}); err != nil {
+				t.Fatal(err)
+			}
+			return file.Bytes()
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewAvro(AvroOptions{}).FromReader(context.Background(), bytes.NewReader(tt.file)); err == nil {
+				t.Error("FromReader() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// BatchIterator iterates over the records of a CSV in fixed size batches
+// Next returns io.EOF once all the records have been read. The last batch may be smaller than the batch size
+// Close releases the resources held by the iterator
+type BatchIterator interface {
+	Next() ([]map[string]string, error)
+	Close() error
+}
+
+type batchIterator struct {
+	records   *recordIterator
+	batchSize int
+	done      bool
+}
+
+// ReadBatches returns an iterator over the records of the CSV read from src, in batches of batchSize records
+func (c *csv) ReadBatches(ctx context.Context, src io.Reader, batchSize int) (BatchIterator, error) {
+	if batchSize <= 0 {
+		return nil, errors.New("Invalid batch size: batch size should be positive")
+	}
+
+	records, err := c.newRecordIterator(ctx, "", src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batchIterator{
+		records:   records,
+		batchSize: batchSize,
+	}, nil
+}
+
+// Next returns the next batch of records
+func (b *batchIterator) Next() ([]map[string]string, error) {
+	if b.done {
+		return nil, io.EOF
+	}
+
+	batch := make([]map[string]string, 0, b.batchSize)
+	for len(batch) < b.batchSize {
+		record, err := b.records.Next()
+		if err == io.EOF {
+			b.done = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, record)
+	}
+
+	if len(batch) == 0 {
+		return nil, io.EOF
+	}
+	return batch, nil
+}
+
+// Close releases the underlying sources owned by the iterator
+func (b *batchIterator) Close() error {
+	return b.records.Close()
+}
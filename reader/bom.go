@@ -0,0 +1,136 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// BOM is the byte order mark found at the start of a CSV
+type BOM string
+
+// Byte order marks detected by the reader
+const (
+	BOMNone    BOM = ""
+	BOMUTF8    BOM = "UTF-8"
+	BOMUTF16LE BOM = "UTF-16LE"
+	BOMUTF16BE BOM = "UTF-16BE"
+)
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// stripBOM detects & removes the byte order mark at the start of the input
+// UTF-16 input is converted to UTF-8 so that it can be read by the csv reader
+func stripBOM(r *bufio.Reader) (io.Reader, BOM, error) {
+	prefix, err := r.Peek(len(bomUTF8))
+	if err != nil && err != io.EOF {
+		return nil, BOMNone, err
+	}
+
+	switch {
+	case bytes.HasPrefix(prefix, bomUTF8):
+		_, err = r.Discard(len(bomUTF8))
+		return r, BOMUTF8, err
+	case bytes.HasPrefix(prefix, bomUTF16LE):
+		_, err = r.Discard(len(bomUTF16LE))
+		return &utf16Reader{reader: r, bigEndian: false}, BOMUTF16LE, err
+	case bytes.HasPrefix(prefix, bomUTF16BE):
+		_, err = r.Discard(len(bomUTF16BE))
+		return &utf16Reader{reader: r, bigEndian: true}, BOMUTF16BE, err
+	}
+
+	return r, BOMNone, nil
+}
+
+// utf16Reader converts UTF-16 input into UTF-8
+// The code units buffered by reader are decoded in bulk into the buffer of each read
+type utf16Reader struct {
+	reader    *bufio.Reader
+	bigEndian bool
+	// pending is the rest of the UTF-8 of a rune which didn't fit in the buffer of the previous read
+	pending    []byte
+	pendingBuf [utf8.UTFMax]byte
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	n := copy(p, u.pending)
+	u.pending = u.pending[n:]
+
+	// need is the number of bytes of the next rune: 2, or 4 for a surrogate pair
+	need := 2
+	for n < len(p) {
+		atEOF := false
+		if u.reader.Buffered() < need {
+			// The runes decoded so far are returned rather than waiting for more input
+			if n > 0 {
+				return n, nil
+			}
+			_, err := u.reader.Peek(need)
+			if err != nil && (err != io.EOF || u.reader.Buffered() < 2) {
+				// A trailing odd byte is dropped
+				return 0, err
+			}
+			atEOF = err != nil
+		}
+
+		units, _ := u.reader.Peek(u.reader.Buffered() &^ 1)
+		written, consumed, next := u.decode(p[n:], units, atEOF)
+		n += written
+		need = next
+		u.reader.Discard(consumed)
+	}
+
+	return n, nil
+}
+
+// decode decodes the code units of src into dst, & returns the number of bytes written & consumed, along with the number of bytes needed to decode the next rune
+// The high surrogates at the end of src are decoded with the next units, unless the input is over
+func (u *utf16Reader) decode(dst []byte, src []byte, atEOF bool) (int, int, int) {
+	written := 0
+	for i := 0; i+1 < len(src); {
+		r, size := rune(u.unit(src[i:])), 2
+		if utf16.IsSurrogate(r) {
+			switch {
+			case r >= 0xDC00:
+				// A low surrogate without its high surrogate
+				r = utf8.RuneError
+			case i+3 < len(src):
+				if next := rune(u.unit(src[i+2:])); next >= 0xDC00 && next < 0xE000 {
+					r, size = utf16.DecodeRune(r, next), 4
+				} else {
+					r = utf8.RuneError
+				}
+			case !atEOF:
+				return written, i, 4
+			default:
+				r = utf8.RuneError
+			}
+		}
+
+		// The rest of the rune is returned by the next read
+		if utf8.RuneLen(r) > len(dst)-written {
+			encoded := u.pendingBuf[:utf8.EncodeRune(u.pendingBuf[:], r)]
+			copied := copy(dst[written:], encoded)
+			u.pending = encoded[copied:]
+			return written + copied, i + size, 2
+		}
+
+		written += utf8.EncodeRune(dst[written:], r)
+		i += size
+	}
+	return written, len(src) &^ 1, 2
+}
+
+// unit returns the code unit at the start of data
+func (u *utf16Reader) unit(data []byte) uint16 {
+	if u.bigEndian {
+		return uint16(data[0])<<8 | uint16(data[1])
+	}
+	return uint16(data[1])<<8 | uint16(data[0])
+}
@@ -0,0 +1,90 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+)
+
+// utf16Data returns the bytes of UTF-16 code units, in little or big endian
+func utf16Data(units []uint16, bigEndian bool) []byte {
+	data := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		if bigEndian {
+			data = append(data, byte(unit>>8), byte(unit))
+		} else {
+			data = append(data, byte(unit), byte(unit>>8))
+		}
+	}
+	return data
+}
+
+func TestUTF16Reader(t *testing.T) {
+	tests := []struct {
+		name     string
+		units    []uint16
+		trailing []byte
+		expected string
+	}{
+		{name: "ascii", units: utf16.Encode([]rune("id,name\n1,a\n")), expected: "id,name\n1,a\n"},
+		{name: "multi-byte", units: utf16.Encode([]rune("prix,€\n1,é\n")), expected: "prix,€\n1,é\n"},
+		{name: "surrogate pairs", units: utf16.Encode([]rune("a😀b🎉\n")), expected: "a😀b🎉\n"},
+		{name: "high surrogate at the end", units: []uint16{'a', 0xD83D}, expected: "a�"},
+		{name: "high surrogate without its pair", units: []uint16{0xD83D, 'a'}, expected: "�a"},
+		{name: "low surrogate without its pair", units: []uint16{'a', 0xDE00, 'b'}, expected: "a�b"},
+		{name: "odd trailing byte", units: []uint16{'a'}, trailing: []byte{'b'}, expected: "a"},
+	}
+
+	for _, tt := range tests {
+		for _, bigEndian := range []bool{false, true} {
+			data := append(utf16Data(tt.units, bigEndian), tt.trailing...)
+
+			// The runes split between the reads & the units split between the reads of the input are kept for the next reads
+			for _, size := range []int{1, 2, 3, 4096} {
+				for _, oneByte := range []bool{false, true} {
+					var input io.Reader = bytes.NewReader(data)
+					if oneByte {
+						input = iotest.OneByteReader(input)
+					}
+					reader := &utf16Reader{reader: bufio.NewReader(input), bigEndian: bigEndian}
+
+					var decoded []byte
+					buf := make([]byte, size)
+					for {
+						n, err := reader.Read(buf)
+						decoded = append(decoded, buf[:n]...)
+						if err == io.EOF {
+							break
+						}
+						if err != nil {
+							t.Fatal(err)
+						}
+					}
+					if string(decoded) != tt.expected {
+						t.Errorf("%s: Read() with big endian %v, %d bytes reads & one byte input %v = %q, want %q", tt.name, bigEndian, size, oneByte, decoded, tt.expected)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestFromReaderUTF16(t *testing.T) {
+	content := "id,name\n1,é😀\n"
+	for _, bom := range [][]byte{bomUTF16LE, bomUTF16BE} {
+		bigEndian := bytes.Equal(bom, bomUTF16BE)
+		data := append(append([]byte{}, bom...), utf16Data(utf16.Encode([]rune(content)), bigEndian)...)
+
+		records, err := NewCSV(CSVOptions{}).FromBytes(context.Background(), data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0]["name"] != "é😀" {
+			t.Errorf("FromBytes() with big endian %v = %v, want name é😀", bigEndian, records)
+		}
+	}
+}
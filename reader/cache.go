@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// URLCache stores the records read by FromURL along with their HTTP validators
+// When a cache is configured, FromURL sends conditional requests & returns the cached records if the remote CSV hasn't changed
+type URLCache interface {
+	Get(url string) (CachedURL, bool)
+	Set(url string, entry CachedURL)
+}
+
+// CachedURL is a URLCache entry
+// Rows is the number of rows read to get the Records & Skipped the malformed rows skipped by the lenient read. They are reported to the Metrics & the Report again whenever the Records are returned from the cache
+type CachedURL struct {
+	ETag         string
+	LastModified string
+	Records      []map[string]string
+	Rows         int
+	Skipped      []RowError
+}
+
+type memoryURLCache struct {
+	mu      sync.RWMutex
+	entries map[string]CachedURL
+}
+
+// NewMemoryURLCache is the initialization method for an in-memory URLCache
+func NewMemoryURLCache() URLCache {
+	return &memoryURLCache{
+		entries: make(map[string]CachedURL),
+	}
+}
+
+// Get returns the cache entry of a url
+func (m *memoryURLCache) Get(url string) (CachedURL, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.entries[url]
+	return entry, ok
+}
+
+// Set stores the cache entry of a url
+func (m *memoryURLCache) Set(url string, entry CachedURL) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[url] = entry
+}
+
+// getCachedRecords sends a conditional request for the url & keeps the cache up to date
+func (c *csv) getCachedRecords(ctx context.Context, url string, req *http.Request) ([]map[string]string, error) {
+	started := time.Now()
+	entry, cached := c.options.Cache.Get(url)
+	if cached {
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	// Without a cached entry, a 304 response has no records to return
+	resp, err := c.do(ctx, c.options.HTTPClient, req, cached)
+	if err != nil {
+		return nil, err
+	}
+	body := c.resumable(ctx, c.options.HTTPClient, req, resp)
+	defer body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return c.readCachedRecords(url, entry, started), nil
+	}
+
+	// The rows skipped by the read are kept along with the records
+	options := c.options
+	options.Report = &ReadReport{}
+	records, rows, err := NewCSV(options).(*csv).readCacheableRecords(ctx, url, body)
+	if c.options.Report != nil {
+		for _, rowErr := range options.Report.Skipped() {
+			c.options.Report.add(rowErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if etag != "" || lastModified != "" {
+		c.options.Cache.Set(url, CachedURL{
+			ETag:         etag,
+			LastModified: lastModified,
+			Records:      copyRecords(records),
+			Rows:         rows,
+			Skipped:      options.Report.Skipped(),
+		})
+	}
+
+	return records, nil
+}
+
+// readCacheableRecords reads the records of the CSV, along with the number of rows read
+func (c *csv) readCacheableRecords(ctx context.Context, url string, body io.Reader) ([]map[string]string, int, error) {
+	it, err := c.newRecordIterator(ctx, url, body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	records, err := readAll(it)
+	return records, it.rows, err
+}
+
+// readCachedRecords returns copies of the cached records, whose read is reported to the Metrics & the Report like the read of the CSV
+func (c *csv) readCachedRecords(url string, entry CachedURL, started time.Time) []map[string]string {
+	if c.options.Report != nil {
+		for _, rowErr := range entry.Skipped {
+			c.options.Report.add(rowErr)
+		}
+	}
+	c.reportReadMetrics(url, started, entry.Rows, 0, nil)
+
+	return copyRecords(entry.Records)
+}
+
+// copyRecords copies the records so that the cached ones can't be modified by the callers
+func copyRecords(records []map[string]string) []map[string]string {
+	copied := make([]map[string]string, len(records))
+	for i, record := range records {
+		copied[i] = make(map[string]string, len(record))
+		for k, v := range record {
+			copied[i][k] = v
+		}
+	}
+	return copied
+}
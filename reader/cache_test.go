@@ -0,0 +1,87 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFromURLNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The stale path answers 304 even to unconditional requests
+		if r.URL.Path == "/stale.csv" || r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("id\n1\n"))
+	}))
+	defer server.Close()
+
+	expected := []map[string]string{{"id": "1"}}
+	c := NewCSV(CSVOptions{Cache: NewMemoryURLCache()})
+	for _, attempt := range []string{"first", "cached"} {
+		records, err := c.FromURL(context.Background(), server.URL+"/data.csv")
+		if err != nil {
+			t.Fatalf("FromURL() %s error = %v", attempt, err)
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("FromURL() %s = %v, want %v", attempt, records, expected)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		options CSVOptions
+	}{
+		{"without cache", CSVOptions{}},
+		{"without cached entry", CSVOptions{Cache: NewMemoryURLCache()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCSV(tt.options).FromURL(context.Background(), server.URL+"/stale.csv")
+			if err == nil || err.Error() != "Unexpected HTTP status code: 304" {
+				t.Errorf("FromURL() error = %v, want Unexpected HTTP status code: 304", err)
+			}
+		})
+	}
+}
+
+func TestFromURLNotModifiedReported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("id,name\n1,a\n2\n3,c\n"))
+	}))
+	defer server.Close()
+
+	metrics := &metricsRecorder{}
+	report := &ReadReport{}
+	c := NewCSV(CSVOptions{Cache: NewMemoryURLCache(), Metrics: metrics, Mode: ReadLenient, Report: report})
+
+	// The cached read is reported like the read of the CSV
+	for i, attempt := range []string{"first", "cached"} {
+		records, err := c.FromURL(context.Background(), server.URL+"/data.csv")
+		if err != nil {
+			t.Fatalf("FromURL() %s error = %v", attempt, err)
+		}
+		if len(records) != 2 {
+			t.Errorf("FromURL() %s returned %d records, want 2", attempt, len(records))
+		}
+
+		reads := i + 1
+		if metrics.rows != 2*reads || metrics.durations != reads {
+			t.Errorf("RowsRead() total after the %s read = %d in %d reads, want %d in %d", attempt, metrics.rows, metrics.durations, 2*reads, reads)
+		}
+		skipped := report.Skipped()
+		if len(skipped) != reads || skipped[i].Line != 3 {
+			t.Errorf("Skipped() after the %s read = %v, want %d rows at line 3", attempt, skipped, reads)
+		}
+	}
+}
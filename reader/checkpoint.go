@@ -0,0 +1,435 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Checkpoint is the position of a read, from which it can be resumed
+// Offset is the byte offset of the file right after the last row read, or 0 when the rows have to be read again to be skipped (ex: compressed files)
+// Rows is the number of rows read, Line the line the last row ends on & Headers the column names of the file
+// Seen are the hashes of the dedupe keys of the records read with DedupeColumns, so that the duplicates of the records read before the checkpoint are still dropped once resumed. The values of the records aren't kept
+// A saved checkpoint only holds the hashes added since the previous checkpoint of the read: the stores add them to the previous ones, & Load returns all of them
+type Checkpoint struct {
+	Offset  int64    `json:"offset"`
+	Rows    int      `json:"rows"`
+	Line    int      `json:"line"`
+	Headers []string `json:"headers"`
+	Seen    []string `json:"seen,omitempty"`
+}
+
+// CheckpointStore stores the checkpoints of the reads by key
+// Load returns false when there is no checkpoint for the key
+// Save adds the Seen hashes of the checkpoint to the ones of the previous checkpoint of the key
+type CheckpointStore interface {
+	Load(key string) (Checkpoint, bool, error)
+	Save(key string, checkpoint Checkpoint) error
+	Delete(key string) error
+}
+
+// CheckpointOptions consists of the checkpoint options available
+// Store is the store the checkpoints are saved to. It is required
+// Key identifies the read in the store. Default value is the file path
+// Interval is the number of records returned between two checkpoints. Default value is 1000
+type CheckpointOptions struct {
+	Store    CheckpointStore
+	Key      string
+	Interval int
+}
+
+type memoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]Checkpoint
+}
+
+// NewMemoryCheckpointStore is the initialization method for an in-memory CheckpointStore
+func NewMemoryCheckpointStore() CheckpointStore {
+	return &memoryCheckpointStore{
+		checkpoints: make(map[string]Checkpoint),
+	}
+}
+
+// Load returns the checkpoint of a key
+func (m *memoryCheckpointStore) Load(key string) (Checkpoint, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	checkpoint, ok := m.checkpoints[key]
+	return checkpoint, ok, nil
+}
+
+// Save stores the checkpoint of a key
+func (m *memoryCheckpointStore) Save(key string, checkpoint Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checkpoint.Seen = append(m.checkpoints[key].Seen, checkpoint.Seen...)
+	m.checkpoints[key] = checkpoint
+	return nil
+}
+
+// Delete removes the checkpoint of a key
+func (m *memoryCheckpointStore) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.checkpoints, key)
+	return nil
+}
+
+// seenHashSize is the number of bytes of the hashes of the dedupe keys, & seenLineLength the length of their lines in the .seen files of the checkpoints
+const (
+	seenHashSize   = 16
+	seenLineLength = 2*seenHashSize + 1
+)
+
+type fileCheckpointStore struct {
+	dir string
+}
+
+// fileCheckpoint is the JSON of a checkpoint file. The Seen hashes are in a file of their own, which is appended to, & SeenCount is their number
+type fileCheckpoint struct {
+	Checkpoint
+	SeenCount int `json:"seen_count,omitempty"`
+}
+
+// NewFileCheckpointStore is the initialization method for a CheckpointStore keeping the checkpoints as JSON files of a directory, so that they survive crashes
+// The Seen hashes of the checkpoints are appended to a .seen file next to the JSON file of their key, one per line
+func NewFileCheckpointStore(dir string) CheckpointStore {
+	return &fileCheckpointStore{dir: dir}
+}
+
+// path returns the file of the checkpoint of a key, named after its hash since keys are usually file paths
+func (f *fileCheckpointStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// seenPath returns the file of the Seen hashes of the checkpoints of a key
+func (f *fileCheckpointStore) seenPath(key string) string {
+	return strings.TrimSuffix(f.path(key), ".json") + ".seen"
+}
+
+// load reads the JSON file of the checkpoint of a key
+func (f *fileCheckpointStore) load(key string) (fileCheckpoint, bool, error) {
+	data, err := os.ReadFile(f.path(key))
+	if os.IsNotExist(err) {
+		return fileCheckpoint{}, false, nil
+	}
+	if err != nil {
+		return fileCheckpoint{}, false, err
+	}
+
+	var checkpoint fileCheckpoint
+	if err = json.Unmarshal(data, &checkpoint); err != nil {
+		return fileCheckpoint{}, false, err
+	}
+	return checkpoint, true, nil
+}
+
+// Load reads the checkpoint of a key, with the Seen hashes of all the checkpoints of the key
+func (f *fileCheckpointStore) Load(key string) (Checkpoint, bool, error) {
+	checkpoint, ok, err := f.load(key)
+	if !ok || err != nil || checkpoint.SeenCount == 0 {
+		return checkpoint.Checkpoint, ok, err
+	}
+
+	// The hashes past the count were appended by a save which didn't complete
+	seen := make([]byte, checkpoint.SeenCount*seenLineLength)
+	file, err := os.Open(f.seenPath(key))
+	if err != nil {
+		return Checkpoint{}, false, err
+	}
+	defer file.Close()
+	if _, err = io.ReadFull(file, seen); err != nil {
+		return Checkpoint{}, false, errors.New("Invalid checkpoint: missing dedupe keys")
+	}
+
+	checkpoint.Seen = make([]string, checkpoint.SeenCount)
+	for i := range checkpoint.Seen {
+		checkpoint.Seen[i] = string(seen[i*seenLineLength : (i+1)*seenLineLength-1])
+	}
+	return checkpoint.Checkpoint, true, nil
+}
+
+// Save writes the checkpoint of a key
+// The Seen hashes are written after the ones of the previous checkpoint, then the checkpoint is written to a temporary file first, so that a crash can't leave a truncated one behind
+func (f *fileCheckpointStore) Save(key string, checkpoint Checkpoint) error {
+	if err := os.MkdirAll(f.dir, 0755); err != nil {
+		return err
+	}
+
+	previous, _, err := f.load(key)
+	if err != nil {
+		return err
+	}
+	saved := fileCheckpoint{Checkpoint: checkpoint, SeenCount: previous.SeenCount + len(checkpoint.Seen)}
+	saved.Seen = nil
+	if len(checkpoint.Seen) > 0 {
+		if err = f.appendSeen(key, previous.SeenCount, checkpoint.Seen); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.dir, "checkpoint-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+// appendSeen writes Seen hashes after the count hashes of the previous checkpoint, overwriting the ones of a save which didn't complete
+func (f *fileCheckpointStore) appendSeen(key string, count int, seen []string) error {
+	file, err := os.OpenFile(f.seenPath(key), os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	data := make([]byte, 0, len(seen)*seenLineLength)
+	for _, hash := range seen {
+		if len(hash) != seenLineLength-1 {
+			file.Close()
+			return errors.New("Invalid dedupe key hash: " + hash)
+		}
+		data = append(append(data, hash...), '\n')
+	}
+	offset := int64(count * seenLineLength)
+	if _, err = file.WriteAt(data, offset); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Truncate(offset + int64(len(data))); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Delete removes the checkpoint of a key
+func (f *fileCheckpointStore) Delete(key string) error {
+	for _, path := range []string{f.path(key), f.seenPath(key)} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkpointer saves the checkpoints of a record iterator
+type checkpointer struct {
+	options CheckpointOptions
+	// seekable is set when the offsets of the rows are offsets of the file
+	seekable bool
+	// pending is the number of records returned since the last checkpoint
+	pending int
+}
+
+// OpenWithCheckpoint returns an iterator over the records of a local file, which saves its position to the checkpoint store as the records are read
+// When the store has a checkpoint for the file, the read resumes after the last row of the checkpoint. The checkpoint is deleted once the file is read entirely
+// A checkpoint is saved when the records read before it are handled, i.e. when the next record is requested, so records are processed at least once
+// The checkpoints hold the hashes of the dedupe keys of the records read with DedupeColumns since the previous checkpoint. The DedupeKeepLast policy isn't supported, since all the records are read before the first one is returned
+func (c *csv) OpenWithCheckpoint(ctx context.Context, filePath string, options CheckpointOptions) (RecordIterator, error) {
+	if options.Store == nil {
+		return nil, errors.New("A checkpoint store is required")
+	}
+	if len(c.options.DedupeColumns) > 0 && c.options.DedupePolicy == DedupeKeepLast {
+		return nil, errors.New("Checkpoints aren't supported with the DedupeKeepLast policy")
+	}
+	if options.Key == "" {
+		options.Key = filePath
+	}
+	if options.Interval <= 0 {
+		options.Interval = 1000
+	}
+
+	checkpoint, resume, err := options.Store.Load(options.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Offsets of the text only match the offsets of the file when it is read as is
+	seekable, err := c.isSeekable(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	var it *recordIterator
+	if resume && seekable && checkpoint.Offset > 0 {
+		it, err = c.resumeAt(ctx, filePath, file, checkpoint)
+	} else {
+		it, err = c.resumeBySkipping(ctx, filePath, file, checkpoint, resume)
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	it.closers = append([]io.Closer{file}, it.closers...)
+	it.checkpoint = &checkpointer{options: options, seekable: seekable}
+
+	// The records read before the checkpoint are duplicates of the later ones
+	if len(c.options.DedupeColumns) > 0 {
+		it.dedupe = &deduplicator{seen: make(map[string]bool, len(checkpoint.Seen))}
+		for _, key := range checkpoint.Seen {
+			it.dedupe.seen[key] = true
+		}
+	}
+
+	return it, nil
+}
+
+// isSeekable checks if a file is read without being decompressed nor decoded
+func (c *csv) isSeekable(file *os.File) (bool, error) {
+	magic := make([]byte, len(zstdMagic))
+	n, err := file.ReadAt(magic, 0)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	magic = magic[:n]
+
+	if c.options.Encoding != "" || isCompressed(magic) {
+		return false, nil
+	}
+	// UTF-16 files are converted to UTF-8
+	if bytes.HasPrefix(magic, []byte{0xFF, 0xFE}) || bytes.HasPrefix(magic, []byte{0xFE, 0xFF}) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// resumeAt reads a file from the offset of a checkpoint, with the column names of the checkpoint
+func (c *csv) resumeAt(ctx context.Context, filePath string, file *os.File, checkpoint Checkpoint) (*recordIterator, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.Offset > info.Size() {
+		return nil, errors.New("Checkpoint beyond the end of the file: " + filePath)
+	}
+	if _, err = file.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	options := c.options
+	options.Headers = checkpoint.Headers
+	options.SkipRows = 0
+	it, err := NewCSV(options).(*csv).newRecordIterator(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+	// The rows before the checkpoint were reported to the metrics by the previous read
+	it.rows = checkpoint.Rows
+	it.metrics.rows = checkpoint.Rows
+	it.line = checkpoint.Line
+	it.lineOffset = checkpoint.Line
+	it.offsetBase = checkpoint.Offset
+
+	return it, nil
+}
+
+// resumeBySkipping reads a file from its start & skips the rows read before the checkpoint, if any
+func (c *csv) resumeBySkipping(ctx context.Context, filePath string, file *os.File, checkpoint Checkpoint, resume bool) (*recordIterator, error) {
+	it, err := c.newRecordIterator(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+	// The BOM is part of the file but not of the text
+	if it.bom == BOMUTF8 {
+		it.offsetBase = 3
+	}
+
+	if resume {
+		// The rows skipped were reported to the metrics by the previous read
+		it.metrics.rows = checkpoint.Rows
+		for it.rows < checkpoint.Rows {
+			if _, err = it.readRecord(); err != nil {
+				it.Close()
+				if err == io.EOF {
+					return nil, errors.New("Checkpoint beyond the end of the file: " + filePath)
+				}
+				return nil, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// saveCheckpoint saves the position of the iterator once enough records were returned since the last checkpoint
+func (it *recordIterator) saveCheckpoint() error {
+	if it.checkpoint == nil || it.checkpoint.pending < it.checkpoint.options.Interval {
+		return nil
+	}
+
+	checkpoint := Checkpoint{
+		Rows:    it.rows,
+		Line:    it.endLine,
+		Headers: it.headers,
+	}
+	if it.checkpoint.seekable {
+		checkpoint.Offset = it.offset
+	}
+	if it.dedupe != nil {
+		checkpoint.Seen = it.dedupe.added
+	}
+	if err := it.checkpoint.options.Store.Save(it.checkpoint.options.Key, checkpoint); err != nil {
+		return err
+	}
+
+	it.checkpoint.pending = 0
+	if it.dedupe != nil {
+		it.dedupe.added = nil
+	}
+	return nil
+}
+
+// hashDedupeKey returns the hash of a dedupe key kept in the checkpoints, so that they don't hold the values of the records
+func hashDedupeKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:seenHashSize])
+}
+
+// clearCheckpoint deletes the checkpoint of a file read entirely
+func (it *recordIterator) clearCheckpoint() error {
+	if it.checkpoint == nil {
+		return nil
+	}
+
+	return it.checkpoint.options.Store.Delete(it.checkpoint.options.Key)
+}
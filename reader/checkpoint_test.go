@@ -0,0 +1,299 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestOpenWithCheckpointDedupe(t *testing.T) {
+	content := "email,name\na@example.com,a\nb@example.com,b\na@example.com,c\nc@example.com,d\nb@example.com,e\nd@example.com,f\n"
+
+	tests := []struct {
+		name  string
+		data  []byte
+		store func(dir string) CheckpointStore
+	}{
+		{name: "offset", data: []byte(content), store: func(string) CheckpointStore { return NewMemoryCheckpointStore() }},
+		// The compressed files are resumed by skipping the rows read
+		{name: "skipped rows", data: gzipData(t, content), store: func(string) CheckpointStore { return NewMemoryCheckpointStore() }},
+		{name: "file store", data: []byte(content), store: NewFileCheckpointStore},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := filepath.Join(dir, "file.csv")
+			if err := os.WriteFile(filePath, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			storeDir := filepath.Join(dir, "checkpoints")
+			c := NewCSV(CSVOptions{DedupeColumns: []string{"email"}})
+			options := CheckpointOptions{Store: tt.store(storeDir), Interval: 2}
+
+			// The read is abandoned after the checkpoint of the first 2 records
+			it, err := c.OpenWithCheckpoint(context.Background(), filePath, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				if _, err = it.Next(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			it.Close()
+
+			checkpoint, _, err := options.Store.Load(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := []string{hashDedupeKey("13:a@example.com"), hashDedupeKey("13:b@example.com")}; !reflect.DeepEqual(checkpoint.Seen, expected) {
+				t.Errorf("Load() Seen = %v, want %v", checkpoint.Seen, expected)
+			}
+			// The stored checkpoints don't hold the values of the records
+			files, _ := os.ReadDir(storeDir)
+			for _, file := range files {
+				data, _ := os.ReadFile(filepath.Join(storeDir, file.Name()))
+				if strings.Contains(string(data), "example.com") {
+					t.Errorf("checkpoint file %s = %s, want no dedupe values", file.Name(), data)
+				}
+			}
+
+			it, err = c.OpenWithCheckpoint(context.Background(), filePath, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer it.Close()
+
+			var names []string
+			for {
+				record, err := it.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, record["name"])
+			}
+			if expected := []string{"d", "f"}; !reflect.DeepEqual(names, expected) {
+				t.Errorf("Next() after the checkpoint = %v, want %v", names, expected)
+			}
+		})
+	}
+}
+
+func TestOpenWithCheckpointDedupeKeepLast(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "file.csv")
+	if err := os.WriteFile(filePath, []byte("id\n1\n1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCSV(CSVOptions{DedupeColumns: []string{"id"}, DedupePolicy: DedupeKeepLast})
+	_, err := c.OpenWithCheckpoint(context.Background(), filePath, CheckpointOptions{Store: NewMemoryCheckpointStore()})
+	if expected := "Checkpoints aren't supported with the DedupeKeepLast policy"; err == nil || err.Error() != expected {
+		t.Errorf("OpenWithCheckpoint() error = %v, want %s", err, expected)
+	}
+}
+
+func TestFileCheckpointStoreSeen(t *testing.T) {
+	store := NewFileCheckpointStore(t.TempDir())
+	a, b, c := hashDedupeKey("a"), hashDedupeKey("b"), hashDedupeKey("c")
+
+	if err := store.Save("key", Checkpoint{Rows: 1, Seen: []string{a}}); err != nil {
+		t.Fatal(err)
+	}
+	// A save which didn't complete leaves hashes past the ones of the checkpoint
+	seenPath := store.(*fileCheckpointStore).seenPath("key")
+	file, err := os.OpenFile(seenPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(hashDedupeKey("leftover") + "\n")
+	file.Close()
+
+	checkpoint, _, err := store.Load("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{a}; !reflect.DeepEqual(checkpoint.Seen, expected) {
+		t.Errorf("Load() Seen = %v, want %v", checkpoint.Seen, expected)
+	}
+
+	// The hashes of the next checkpoints are added to the previous ones
+	if err = store.Save("key", Checkpoint{Rows: 3, Seen: []string{b, c}}); err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, _, err = store.Load("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (Checkpoint{Rows: 3, Seen: []string{a, b, c}}); !reflect.DeepEqual(checkpoint, expected) {
+		t.Errorf("Load() = %v, want %v", checkpoint, expected)
+	}
+
+	if err = store.Delete("key"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(seenPath); !os.IsNotExist(err) {
+		t.Errorf("Delete() left %s behind", seenPath)
+	}
+}
+
+func TestOpenWithCheckpointMetrics(t *testing.T) {
+	content := "id\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "offset", data: []byte(content)},
+		{name: "skipped rows", data: gzipData(t, content)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "file.csv")
+			if err := os.WriteFile(filePath, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			options := CheckpointOptions{Store: NewMemoryCheckpointStore(), Interval: 4}
+
+			// The read is abandoned after the checkpoint of the first 4 records
+			it, err := NewCSV(CSVOptions{}).OpenWithCheckpoint(context.Background(), filePath, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 5; i++ {
+				if _, err = it.Next(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			it.Close()
+
+			metrics := &metricsRecorder{}
+			it, err = NewCSV(CSVOptions{Metrics: metrics, ProgressInterval: 2}).OpenWithCheckpoint(context.Background(), filePath, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer it.Close()
+			records := 0
+			for {
+				if _, err = it.Next(); err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				records++
+			}
+
+			if records != 6 || metrics.rows != 6 {
+				t.Errorf("RowsRead() total after the checkpoint = %d for %d records, want 6", metrics.rows, records)
+			}
+		})
+	}
+}
+
+func TestOpenWithCheckpointResume(t *testing.T) {
+	content := "id,note\n1,a\n2,b\n3,c\n4,d\n5,e\n"
+
+	tests := []struct {
+		name    string
+		data    []byte
+		options CSVOptions
+	}{
+		{name: "plain", data: []byte(content)},
+		{name: "quoted line breaks", data: []byte("id,note\r\n1,\"a\r\nb\"\r\n2,c\r\n3,\"d,\ne\"\r\n4,f\r\n5,g\r\n")},
+		{name: "UTF-8 BOM", data: []byte("\xEF\xBB\xBF" + content)},
+		{name: "skipped rows & comments", data: []byte("exported\nid,note\n1,a\n# comment\n2,b\n3,c\n# comment\n4,d\n5,e\n"), options: CSVOptions{SkipRows: 1, Comment: '#'}},
+		// The files which aren't read as is are resumed by skipping the rows read
+		{name: "windows-1252", data: []byte("id,note\n1,caf\xe9\n2,b\n3,c\n4,d\n5,e\n"), options: CSVOptions{Encoding: "windows-1252"}},
+		{name: "UTF-16", data: append([]byte{0xFF, 0xFE}, utf16Data(utf16.Encode([]rune(content)), false)...)},
+		{name: "gzip", data: gzipData(t, content)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "file.csv")
+			if err := os.WriteFile(filePath, tt.data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			c := NewCSV(tt.options)
+
+			it, err := c.Open(context.Background(), bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expected, expectedLines := readCheckpointTest(t, it)
+			if len(expected) != 5 {
+				t.Fatalf("Open() read %d records, want 5", len(expected))
+			}
+
+			// The read is abandoned after each record, then resumed from its last checkpoint
+			for stop := 1; stop <= len(expected); stop++ {
+				options := CheckpointOptions{Store: NewMemoryCheckpointStore(), Interval: 2}
+				it, err = c.OpenWithCheckpoint(context.Background(), filePath, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < stop; i++ {
+					if _, err = it.Next(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				it.Close()
+
+				// A checkpoint is saved when the record after the interval is requested
+				rows := (stop - 1) / 2 * 2
+				checkpoint, ok, err := options.Store.Load(filePath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok != (rows > 0) || checkpoint.Rows != rows {
+					t.Fatalf("Load() after %d records = %v, %v, want %d rows", stop, checkpoint, ok, rows)
+				}
+
+				it, err = c.OpenWithCheckpoint(context.Background(), filePath, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				records, lines := readCheckpointTest(t, it)
+				if !reflect.DeepEqual(records, expected[rows:]) || !reflect.DeepEqual(lines, expectedLines[rows:]) {
+					t.Errorf("Next() after %d records = %v at lines %v, want %v at lines %v", stop, records, lines, expected[rows:], expectedLines[rows:])
+				}
+
+				// The checkpoint is deleted once the file is read entirely
+				if _, ok, _ = options.Store.Load(filePath); ok {
+					t.Errorf("Load() after the end of the file = true, want false")
+				}
+			}
+		})
+	}
+}
+
+// readCheckpointTest reads the records of an iterator up to its end, with their line numbers
+func readCheckpointTest(t *testing.T, it RecordIterator) ([]map[string]string, []int) {
+	t.Helper()
+	defer it.Close()
+
+	var records []map[string]string
+	var lines []int
+	for {
+		record, err := it.Next()
+		if err == io.EOF {
+			return records, lines
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+		lines = append(lines, it.Line())
+	}
+}
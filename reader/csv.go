@@ -1,68 +1,157 @@
 package reader
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	gocsv "encoding/csv"
-	"errors"
 	"io"
+	"io/fs"
+	"log/slog"
 	"net"
 	"net/http"
-	"os"
-	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/crypto/ssh"
 )
 
 // CSVOptions consists of the reader options available
 // HTTPClient is required only if you want a custom client to handle the requests. By Default, the package keeps 10s of end-to-end request timeout with 5s TCP connect timeout & 5s of TLS handshake timeout
+// StreamBufferSize is the capacity of the record channel returned by Stream. Default value is 100
+// SourceColumn is the name of the column added to every record with the path or URL it was read from. Disabled by default
+// S3 is required only for reading from S3. See S3Options
+// GCS is required only for reading from Google Cloud Storage. See GCSOptions
+// FTP holds the credentials of the reads from FTP servers. See FTPOptions
+// Delimiter is the field delimiter. Default value is ','
+// Encoding is the character encoding of the input (ex: "latin-1", "windows-1252", "utf-16le"), which is converted to UTF-8 while reading. Default value is UTF-8
+// Headers are the column names of a CSV without a header row. When provided, the first row is read as a record
+// SkipRows is the number of leading rows (ex: titles) ignored before the header row
+// SkipFooterRows is the number of trailing rows (ex: totals) ignored at the end of the CSV
+// Comment is the character which starts a comment line (ex: '#'). Comment lines are ignored. Disabled by default
+// LazyQuotes allows quotes in unquoted fields & non-doubled quotes in quoted fields
+// PadShortRows fills the missing trailing fields of short rows with empty strings instead of failing
+// Retry configures the retries of the HTTP based reads. See RetryOptions
+// HTTPHeaders are the headers sent with every URL request (ex: API keys)
+// BearerToken & BasicAuth add the matching Authorization header to every URL request
+// RequestHook is called with every URL request before it is sent, so it can be modified freely
+// Cache enables conditional requests in FromURL using ETag & Last-Modified. See URLCache. Disabled by default
+// MaxResumes is the number of times an interrupted HTTP download is resumed with a range request. Disabled by default
+// MaxBytes & MaxRows limit the size of the (decompressed) CSV & its number of records. Exceeding them returns a *LimitError. Disabled by default. MaxBytes also limits the spreadsheets & Parquet files buffered in memory, & each decompressed XML file of the spreadsheets
+// OnProgress is called with the number of records & bytes read every ProgressInterval records, and once the read is complete
+// ProgressInterval is the number of records between two progress reports. Default value is 1000
+// LineColumn is the name of the column added to every record with its line number in the CSV. Disabled by default
+// DuplicateHeaders is the handling of columns sharing the same name. See DuplicateHeaderPolicy. Default value is DuplicateHeadersKeepLast
+// HeaderTransforms are applied in order to every column name before the duplicates are resolved (ex: []HeaderTransform{StripInvisibleHeader, SnakeCaseHeader})
+// HeaderAliases renames columns by name (ex: map[string]string{"E-Mail Address": "email"}). The aliases are looked up with the column names as read, then as transformed. Aliased names aren't transformed
+// RowFilter is called with every record as it is read. Records for which it returns false are dropped
+// Metrics receives the measures of every read, see Metrics. Disabled by default
+// Logger receives the debug & warning events of the reads (ex: skipped rows, stripped BOMs, retries). Disabled by default
+// Trim is the handling of the whitespace around the values. See TrimPolicy. Default value is TrimSpace
+// ColumnTrim overrides Trim for some columns, by column name (ex: map[string]TrimPolicy{"comment": TrimNone})
+// Profiler computes the statistics of the columns of the records returned by the reads. See Profiler. Disabled by default
+// DedupeColumns are the columns whose values identify a record. Records with the same values as another record are dropped, as selected by DedupePolicy. Disabled by default
+// DedupePolicy is the record kept among the duplicates. See DedupePolicy. Default value is DedupeKeepFirst
+// Mode is the handling of the malformed rows. See ReadMode. Default value is ReadStrict
+// Report collects the malformed rows skipped by the lenient reads. See ReadReport. Optional
+// MaxCellBytes limits the size of the fields. Exceeding it returns a *LimitError, unless TruncateCells is set. Disabled by default
+// TruncateCells truncates the fields exceeding MaxCellBytes instead of failing
+// TruncatedColumn is the name of the column added to the records with truncated fields, with the names of their truncated columns joined by commas. Disabled by default
+// MemoryMap makes FromPath read the file through a memory mapping instead of read system calls, so that the pages of very large files are loaded on demand. The bytes are still copied through the buffered reads of the parser. It is ignored on the platforms without memory mappings
 type CSVOptions struct {
-	HTTPClient *http.Client
+	HTTPClient       *http.Client
+	StreamBufferSize int
+	SourceColumn     string
+	S3               S3Options
+	GCS              GCSOptions
+	FTP              FTPOptions
+	Delimiter        rune
+	Encoding         string
+	Headers          []string
+	SkipRows         int
+	SkipFooterRows   int
+	Comment          rune
+	LazyQuotes       bool
+	PadShortRows     bool
+	Retry            RetryOptions
+	HTTPHeaders      map[string]string
+	BearerToken      string
+	BasicAuth        *BasicAuth
+	RequestHook      func(req *http.Request) error
+	Cache            URLCache
+	MaxResumes       int
+	MaxBytes         int64
+	MaxRows          int
+	OnProgress       func(rowsRead int, bytesRead int64)
+	ProgressInterval int
+	LineColumn       string
+	DuplicateHeaders DuplicateHeaderPolicy
+	HeaderTransforms []HeaderTransform
+	HeaderAliases    map[string]string
+	RowFilter        func(record map[string]string) bool
+	MemoryMap        bool
+	Metrics          Metrics
+	Logger           *slog.Logger
+	Trim             TrimPolicy
+	ColumnTrim       map[string]TrimPolicy
+	Profiler         *Profiler
+	DedupeColumns    []string
+	DedupePolicy     DedupePolicy
+	Mode             ReadMode
+	Report           *ReadReport
+	MaxCellBytes     int
+	TruncateCells    bool
+	TruncatedColumn  string
 }
 
 // CSV is a lightweight interface for reading csv files
 type CSV interface {
 	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
 	FromURL(ctx context.Context, url string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	FromString(ctx context.Context, data string) ([]map[string]string, error)
+	FromBytes(ctx context.Context, data []byte) ([]map[string]string, error)
+	Open(ctx context.Context, src io.Reader) (RecordIterator, error)
+	Stream(ctx context.Context, src io.Reader) (<-chan Record, <-chan error)
+	FromZip(ctx context.Context, archivePath string, memberPattern string) ([]map[string]string, error)
+	FromS3(ctx context.Context, bucket string, key string) ([]map[string]string, error)
+	FromGCS(ctx context.Context, uri string) ([]map[string]string, error)
+	FromSFTP(ctx context.Context, addr string, filePath string, config *ssh.ClientConfig) ([]map[string]string, error)
+	FromFTP(ctx context.Context, addr string, filePath string) ([]map[string]string, error)
+	FromFS(ctx context.Context, fsys fs.FS, filePath string) ([]map[string]string, error)
+	FromRequest(ctx context.Context, req *http.Request) ([]map[string]string, error)
+	Sample(ctx context.Context, src io.Reader, mode SampleMode, n int) ([]map[string]string, error)
+	ReadBatches(ctx context.Context, src io.Reader, batchSize int) (BatchIterator, error)
+	FromGlob(ctx context.Context, pattern string) ([]map[string]string, error)
+	FromURLs(ctx context.Context, urls []string, concurrency int) ([]map[string]string, map[string]error)
+	FromGoogleSheet(ctx context.Context, spreadsheetID string, sheet string, cellRange string) ([]map[string]string, error)
+	FromSource(ctx context.Context, src Source) ([]map[string]string, error)
+	OpenWithCheckpoint(ctx context.Context, filePath string, options CheckpointOptions) (RecordIterator, error)
+	FromPathParallel(ctx context.Context, filePath string, workers int) ([]map[string]string, error)
+	Sniff(ctx context.Context, src io.Reader) (Dialect, error)
 }
 
 type csv struct {
 	options CSVOptions
 }
 
-func (c *csv) getRecords(ctx context.Context, csvData io.Reader) ([]map[string]string, error) {
+// getRecords reads all the records of the CSV. source is the path or URL the CSV is read from, if any
+func (c *csv) getRecords(ctx context.Context, source string, csvData io.Reader) ([]map[string]string, error) {
 	var lines []map[string]string
 
-	reader := gocsv.NewReader(csvData)
-	lineCount := 0
-	var mapKeys []string
-	var err error
-	for {
-		if lineCount == 0 {
-			mapKeys, err = reader.Read()
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return nil, err
-			}
-			lineCount++
-			continue
-		}
+	it, err := c.newRecordIterator(ctx, source, csvData)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
 
-		line, err := reader.Read()
+	for {
+		record, err := it.Next()
 		if err == io.EOF {
 			break
 		}
 		if err != nil {
 			return nil, err
 		}
-		record := make(map[string]string)
-		for i, val := range line {
-			record[mapKeys[i]] = strings.TrimSpace(val)
-		}
 		lines = append(lines, record)
-		lineCount++
 	}
 
 	return lines, nil
@@ -70,27 +159,91 @@ func (c *csv) getRecords(ctx context.Context, csvData io.Reader) ([]map[string]s
 
 // FromPath reads CSV from a file path
 func (c *csv) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
-	file, err := os.Open(filePath)
+	return c.FromSource(ctx, &fileSource{path: filePath, mapped: c.options.MemoryMap})
+}
+
+// FromFS reads CSV from a file of a fs.FS (ex: embed.FS)
+func (c *csv) FromFS(ctx context.Context, fsys fs.FS, filePath string) ([]map[string]string, error) {
+	return c.FromSource(ctx, FSSource(fsys, filePath))
+}
+
+// FromURL reads the CSV from a url
+// The request is bound to ctx, so cancelling it aborts the download
+func (c *csv) FromURL(ctx context.Context, url string) ([]map[string]string, error) {
+	if c.options.Cache != nil {
+		req, err := c.newURLRequest(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		return c.getCachedRecords(ctx, url, req)
+	}
+
+	return c.FromSource(ctx, URLSource(url, c.options))
+}
+
+// newURLRequest builds the GET request of a url with the HTTP options
+func (c *csv) newURLRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
+	if err = c.prepareRequest(req); err != nil {
+		return nil, err
+	}
 
-	return c.getRecords(ctx, bufio.NewReader(file))
+	return req, nil
 }
 
-// FromURL reads the CSV from a url
-func (c *csv) FromURL(ctx context.Context, url string) ([]map[string]string, error) {
-	resp, err := c.options.HTTPClient.Get(url)
+// FromRequest reads the CSV from the response of a custom HTTP request (ex: a POST with a JSON body)
+// Requests with a body are retried only if the body can be recreated (see http.Request.GetBody)
+func (c *csv) FromRequest(ctx context.Context, req *http.Request) ([]map[string]string, error) {
+	req = req.WithContext(ctx)
+	if err := c.prepareRequest(req); err != nil {
+		return nil, err
+	}
+
+	return c.getRecordsFromRequest(ctx, req.URL.String(), c.options.HTTPClient, req)
+}
+
+// getRecordsFromRequest sends the request with the given HTTP client & reads the CSV from the response
+func (c *csv) getRecordsFromRequest(ctx context.Context, source string, client *http.Client, req *http.Request) ([]map[string]string, error) {
+	body, err := c.openRequest(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
-	if resp.StatusCode != 200 {
-		return nil, errors.New("Unexpected HTTP status code: " + strconv.Itoa(resp.StatusCode))
+	defer body.Close()
+
+	return c.getRecords(ctx, source, body)
+}
+
+// openRequest sends the request with the given HTTP client & returns the body of the response
+func (c *csv) openRequest(ctx context.Context, client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, client, req, false)
+	if err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	return c.getRecords(ctx, bufio.NewReader(resp.Body))
+	return c.resumable(ctx, client, req, resp), nil
+}
+
+// FromReader reads CSV from an io.Reader
+func (c *csv) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	return c.getRecords(ctx, "", r)
+}
+
+// FromString reads CSV from a string (ex: a CSV pasted into a request)
+func (c *csv) FromString(ctx context.Context, data string) ([]map[string]string, error) {
+	return c.getRecords(ctx, "", strings.NewReader(data))
+}
+
+// FromBytes reads CSV from a byte slice
+func (c *csv) FromBytes(ctx context.Context, data []byte) ([]map[string]string, error) {
+	return c.getRecords(ctx, "", bytes.NewReader(data))
+}
+
+// Open returns an iterator over the records of the CSV read from src
+func (c *csv) Open(ctx context.Context, src io.Reader) (RecordIterator, error) {
+	return c.newRecordIterator(ctx, "", src)
 }
 
 // NewCSV is the initialization method for csv reader
@@ -108,7 +261,44 @@ func NewCSV(options CSVOptions) CSV {
 		}
 	}
 
+	if options.Delimiter == 0 {
+		options.Delimiter = ','
+	}
+	if options.StreamBufferSize == 0 {
+		options.StreamBufferSize = 100
+	}
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
+	if options.ProgressInterval == 0 {
+		options.ProgressInterval = 1000
+	}
+	if options.Retry.MaxAttempts == 0 {
+		options.Retry.MaxAttempts = 1
+	}
+	if options.Retry.InitialBackoff == 0 {
+		options.Retry.InitialBackoff = 500 * time.Millisecond
+	}
+	if options.Retry.MaxBackoff == 0 {
+		options.Retry.MaxBackoff = 10 * time.Second
+	}
+	if options.Retry.RetryableStatusCodes == nil {
+		options.Retry.RetryableStatusCodes = []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		}
+	}
+
 	return &csv{
 		options: options,
 	}
 }
+
+// NewTSV is the initialization method for a tab separated values reader
+// Quoted fields may contain tabs
+func NewTSV(options CSVOptions) CSV {
+	options.Delimiter = '\t'
+	return NewCSV(options)
+}
@@ -0,0 +1,92 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func gzipData(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return compressed.Bytes()
+}
+
+func zstdData(t *testing.T, data string) []byte {
+	t.Helper()
+
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer encoder.Close()
+	return encoder.EncodeAll([]byte(data), nil)
+}
+
+func TestDecompress(t *testing.T) {
+	const csvData = "id,name\n1,a\n2,b\n"
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"plain", []byte(csvData), csvData},
+		{"gzip", gzipData(t, csvData), csvData},
+		{"zstd", zstdData(t, csvData), csvData},
+		{"shorter than the magic bytes", []byte("a"), "a"},
+		{"empty", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := decompress(bufio.NewReader(bytes.NewReader(tt.input)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer data.Close()
+
+			decompressed, err := io.ReadAll(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decompressed) != tt.expected {
+				t.Errorf("decompress() = %q, want %q", decompressed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromReaderZstd(t *testing.T) {
+	c := NewCSV(CSVOptions{})
+
+	records, err := c.FromReader(context.Background(), bytes.NewReader(zstdData(t, "id,name\n1,a\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []map[string]string{{"id": "1", "name": "a"}}; !reflect.DeepEqual(records, expected) {
+		t.Errorf("FromReader() = %v, want %v", records, expected)
+	}
+
+	dialect, err := c.Sniff(context.Background(), bytes.NewReader(zstdData(t, "id;name\n1;a\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dialect.Compressed || dialect.Delimiter != ';' {
+		t.Errorf("Sniff() = %+v, want a compressed CSV delimited by ;", dialect)
+	}
+}
@@ -0,0 +1,115 @@
+package reader
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DedupePolicy is the record kept among the records sharing the same values in the DedupeColumns
+type DedupePolicy int
+
+// Dedupe policies
+// DedupeKeepFirst keeps the first record of the duplicates. This is the default policy
+// DedupeKeepLast keeps the last record of the duplicates, at its position. Since any record can have a later duplicate, all the records are read before the first one is returned
+const (
+	DedupeKeepFirst DedupePolicy = iota
+	DedupeKeepLast
+)
+
+// deduplicator holds the keys of the records read, & the records of DedupeKeepLast
+// The keys of the checkpointed reads are hashed, & added holds the ones added since the last checkpoint
+type deduplicator struct {
+	seen    map[string]bool
+	added   []string
+	records []dedupedRecord
+	loaded  bool
+}
+
+type dedupedRecord struct {
+	record map[string]string
+	keys   []string
+	line   int
+}
+
+// dedupeKey returns the values of the dedupe columns of a record. The values are prefixed with their length, so that they can't be confused
+func (it *recordIterator) dedupeKey(record map[string]string) string {
+	var b strings.Builder
+	for _, column := range it.options.DedupeColumns {
+		value := record[column]
+		b.WriteString(strconv.Itoa(len(value)))
+		b.WriteByte(':')
+		b.WriteString(value)
+	}
+	return b.String()
+}
+
+// isDuplicate checks if a record with the same values in the dedupe columns was already read
+func (it *recordIterator) isDuplicate(record map[string]string) bool {
+	if it.dedupe == nil {
+		it.dedupe = &deduplicator{seen: make(map[string]bool)}
+	}
+
+	key := it.dedupeKey(record)
+	if it.checkpoint != nil {
+		key = hashDedupeKey(key)
+	}
+	if it.dedupe.seen[key] {
+		return true
+	}
+	it.dedupe.seen[key] = true
+	if it.checkpoint != nil {
+		it.dedupe.added = append(it.dedupe.added, key)
+	}
+	return false
+}
+
+// nextLastRecord returns the next record which has no later duplicate
+func (it *recordIterator) nextLastRecord() (map[string]string, error) {
+	if it.dedupe == nil {
+		it.dedupe = &deduplicator{}
+	}
+	if !it.dedupe.loaded {
+		if err := it.loadLastRecords(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(it.dedupe.records) == 0 {
+		return nil, io.EOF
+	}
+	next := it.dedupe.records[0]
+	it.dedupe.records = it.dedupe.records[1:]
+	it.line, it.keys = next.line, next.keys
+
+	return next.record, nil
+}
+
+// loadLastRecords reads all the records & keeps the last one of the duplicates
+func (it *recordIterator) loadLastRecords() error {
+	var records []dedupedRecord
+	last := make(map[string]int)
+	for {
+		record, err := it.nextFilteredRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		last[it.dedupeKey(record)] = len(records)
+		records = append(records, dedupedRecord{record: record, keys: it.keys, line: it.line})
+	}
+
+	for i, record := range records {
+		if last[it.dedupeKey(record.record)] != i {
+			it.options.Logger.Debug("Duplicate row dropped", "source", it.source, "line", record.line)
+			continue
+		}
+		it.dedupe.records = append(it.dedupe.records, record)
+	}
+	it.dedupe.loaded = true
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package reader
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupe(t *testing.T) {
+	content := "email,name,age\na@example.com,a,1\nb@example.com,b,2\na@example.com,c,3\nc@example.com,a,4\nb@example.com,d,2\n"
+
+	tests := []struct {
+		name     string
+		content  string
+		options  CSVOptions
+		expected []string
+	}{
+		{
+			name:     "no dedupe columns",
+			content:  content,
+			expected: []string{"1", "2", "3", "4", "2"},
+		},
+		{
+			name:     "keep first",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"email"}},
+			expected: []string{"1", "2", "4"},
+		},
+		{
+			name:     "keep last",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"email"}, DedupePolicy: DedupeKeepLast},
+			expected: []string{"3", "4", "2"},
+		},
+		{
+			name:     "several columns",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"email", "age"}},
+			expected: []string{"1", "2", "3", "4"},
+		},
+		{
+			name:     "several columns keep last",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"email", "age"}, DedupePolicy: DedupeKeepLast},
+			expected: []string{"1", "3", "4", "2"},
+		},
+		{
+			// The values aren't confused with the ones of the other columns once joined
+			name:     "joined values",
+			content:  "a,b,age\nx1,,1\nx,1,2\nx1,,3\n",
+			options:  CSVOptions{DedupeColumns: []string{"a", "b"}},
+			expected: []string{"1", "2"},
+		},
+		{
+			name:     "missing column",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"phone"}},
+			expected: []string{"1"},
+		},
+		{
+			// The records filtered out don't count as duplicates
+			name:     "filtered records",
+			content:  content,
+			options:  CSVOptions{DedupeColumns: []string{"email"}, RowFilter: func(record map[string]string) bool { return record["name"] != "a" }},
+			expected: []string{"2", "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := NewCSV(tt.options).FromReader(context.Background(), strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ages []string
+			for _, record := range records {
+				ages = append(ages, record["age"])
+			}
+			if !reflect.DeepEqual(ages, tt.expected) {
+				t.Errorf("FromReader() ages = %v, want %v", ages, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDedupeKeepLastLines(t *testing.T) {
+	content := "id\n1\n2\n1\n3\n2\n"
+	it, err := NewCSV(CSVOptions{DedupeColumns: []string{"id"}, DedupePolicy: DedupeKeepLast}).Open(context.Background(), strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+
+	// The records keep the line numbers of their rows
+	var lines []int
+	for {
+		if _, err = it.Next(); err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, it.Line())
+	}
+	if expected := []int{4, 5, 6}; !reflect.DeepEqual(lines, expected) {
+		t.Errorf("Line() = %v, want %v", lines, expected)
+	}
+}
@@ -0,0 +1,48 @@
+package reader
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/transform"
+)
+
+// encodingAliases consists of the commonly used encoding names which are not registered labels
+var encodingAliases = map[string]string{
+	"latin-1": "iso-8859-1",
+	"utf-16":  "utf-16le",
+}
+
+// getEncoding looks up an encoding by its IANA name or WHATWG label
+func getEncoding(name string) (encoding.Encoding, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := encodingAliases[name]; ok {
+		name = alias
+	}
+
+	enc, err := ianaindex.IANA.Encoding(name)
+	if err == nil && enc != nil {
+		return enc, nil
+	}
+
+	enc, err = htmlindex.Get(name)
+	if err == nil && enc != nil {
+		return enc, nil
+	}
+
+	return nil, errors.New("Unsupported encoding: " + name)
+}
+
+// decode converts the input from the named encoding into UTF-8
+func decode(r io.Reader, name string) (io.Reader, error) {
+	enc, err := getEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return transform.NewReader(r, enc.NewDecoder()), nil
+}
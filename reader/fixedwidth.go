@@ -0,0 +1,142 @@
+package reader
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+)
+
+// FixedWidthColumn is a column of a fixed-width file
+// Start is the position (0-indexed) of its first character & Length its number of characters
+type FixedWidthColumn struct {
+	Name   string
+	Start  int
+	Length int
+}
+
+// FixedWidthOptions consists of the fixed-width reader options available
+// CSVOptions apply to the lines of the file, except for the ones specific to delimited text (ex: Delimiter, LazyQuotes). Headers are always the column names
+// Columns is the specification of the columns of the file
+type FixedWidthOptions struct {
+	CSVOptions
+	Columns []FixedWidthColumn
+}
+
+// FixedWidth is a lightweight interface for reading fixed-width files
+type FixedWidth interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, src io.Reader) (RecordIterator, error)
+}
+
+type fixedWidth struct {
+	csv     *csv
+	options FixedWidthOptions
+}
+
+// FromPath reads a fixed-width file from a file path
+func (f *fixedWidth) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	it, err := f.open(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads a fixed-width file from an io.Reader
+func (f *fixedWidth) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	it, err := f.open(ctx, "", r)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the fixed-width file read from src
+func (f *fixedWidth) Open(ctx context.Context, src io.Reader) (RecordIterator, error) {
+	return f.open(ctx, "", src)
+}
+
+func (f *fixedWidth) open(ctx context.Context, source string, src io.Reader) (*recordIterator, error) {
+	input, err := f.csv.openText(src)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &fixedWidthRowReader{
+		reader:  bufio.NewReader(input.text),
+		columns: f.options.Columns,
+		comment: f.options.Comment,
+	}
+
+	return f.csv.newTextIterator(ctx, source, input, rows)
+}
+
+// fixedWidthRowReader splits the lines of a fixed-width file into fields
+type fixedWidthRowReader struct {
+	reader  *bufio.Reader
+	columns []FixedWidthColumn
+	comment rune
+	line    int
+}
+
+func (r *fixedWidthRowReader) Read() ([]string, error) {
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		r.line++
+
+		line = strings.TrimRight(line, "\r\n")
+		// Empty & comment lines are skipped like in a CSV
+		if strings.TrimSpace(line) == "" || (r.comment != 0 && strings.HasPrefix(line, string(r.comment))) {
+			continue
+		}
+
+		return r.fields([]rune(line)), nil
+	}
+}
+
+// fields cuts the columns out of a line. Positions are counted in characters
+func (r *fixedWidthRowReader) fields(line []rune) []string {
+	fields := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		start, end := column.Start, column.Start+column.Length
+		if start >= len(line) || start < 0 {
+			continue
+		}
+		if end > len(line) {
+			end = len(line)
+		}
+		fields[i] = string(line[start:end])
+	}
+	return fields
+}
+
+func (r *fixedWidthRowReader) Line() int {
+	return r.line
+}
+
+// NewFixedWidth is the initialization method for the fixed-width reader
+func NewFixedWidth(options FixedWidthOptions) FixedWidth {
+	options.Headers = make([]string, len(options.Columns))
+	for i, column := range options.Columns {
+		options.Headers[i] = column.Name
+	}
+
+	return &fixedWidth{
+		csv:     NewCSV(options.CSVOptions).(*csv),
+		options: options,
+	}
+}
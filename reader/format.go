@@ -0,0 +1,72 @@
+package reader
+
+import (
+	"encoding/hex"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// formatFloat formats floats like encoding/json, without exponent for the usual magnitudes
+func formatFloat(v float64, bits int) string {
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	return strconv.FormatFloat(v, format, -1, bits)
+}
+
+// formatDecimal formats the unscaled integer of a decimal with its scale
+func formatDecimal(unscaled *big.Int, scale int) string {
+	if scale <= 0 {
+		return unscaled.String()
+	}
+
+	digits := new(big.Int).Abs(unscaled).String()
+	for len(digits) <= scale {
+		digits = "0" + digits
+	}
+
+	s := digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	if unscaled.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// formatDecimalBytes formats a decimal stored as a big-endian two's complement integer
+func formatDecimalBytes(b []byte, scale int) string {
+	unscaled := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		unscaled.Sub(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return formatDecimal(unscaled, scale)
+}
+
+// formatDate formats a number of days since the Unix epoch as RFC3339
+func formatDate(days int64) string {
+	return time.Unix(days*24*60*60, 0).UTC().Format(time.RFC3339)
+}
+
+// formatTimestamp formats a number of units since the Unix epoch as RFC3339
+// Timestamps which aren't adjusted to UTC are local date times, without time zone
+func formatTimestamp(v int64, unit time.Duration, utc bool) string {
+	perSecond := int64(time.Second / unit)
+	timestamp := time.Unix(v/perSecond, v%perSecond*int64(unit)).UTC()
+	if !utc {
+		return timestamp.Format("2006-01-02T15:04:05.999999999")
+	}
+	return timestamp.Format(time.RFC3339Nano)
+}
+
+// formatTime formats a number of units since midnight
+func formatTime(v int64, unit time.Duration) string {
+	return time.Time{}.Add(time.Duration(v) * unit).Format("15:04:05.999999999")
+}
+
+// formatUUID formats the 16 bytes of a UUID
+func formatUUID(b []byte) string {
+	s := hex.EncodeToString(b)
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
@@ -0,0 +1,238 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FTPOptions consists of the options used to read files from FTP servers
+// User & Password are the credentials of the login. Default to an anonymous login
+// The credentials & the data are sent in clear text, so FTP only suits trusted networks. Prefer SFTP otherwise
+type FTPOptions struct {
+	User     string
+	Password string
+}
+
+// FromFTP reads the CSV from a file on an FTP server, with the FTP options
+func (c *csv) FromFTP(ctx context.Context, addr string, filePath string) ([]map[string]string, error) {
+	return c.FromSource(ctx, c.ftpSource(addr, filePath))
+}
+
+func (c *csv) ftpSource(addr string, filePath string) NamedSource {
+	options := c.options.FTP
+	if options.User == "" {
+		options.User = "anonymous"
+		options.Password = "anonymous"
+	}
+
+	return &ftpSource{addr: addr, filePath: filePath, options: options}
+}
+
+// ftpSource is a file on an FTP server, downloaded in passive mode through a new connection every time it is opened
+type ftpSource struct {
+	addr     string
+	filePath string
+	options  FTPOptions
+}
+
+func (s *ftpSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &ftpFile{conn: conn, control: textproto.NewConn(conn), done: make(chan struct{})}
+	// Abort the transfer if the context is cancelled mid transfer
+	go func() {
+		select {
+		case <-ctx.Done():
+			file.abort()
+		case <-file.done:
+		}
+	}()
+
+	if err = file.retrieve(ctx, dialer, s.filePath, s.options); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *ftpSource) Name() string {
+	return "ftp://" + s.addr + "/" + strings.TrimPrefix(s.filePath, "/")
+}
+
+// ftpFile is the data connection of the download of a file, along with its control connection
+type ftpFile struct {
+	conn    net.Conn
+	control *textproto.Conn
+	done    chan struct{}
+	// mu guards data, which is closed by the cancellation of the context
+	mu   sync.Mutex
+	data net.Conn
+	// complete is set once the server confirmed the end of the transfer
+	complete bool
+}
+
+// retrieve logs in & starts the binary download of a file
+func (f *ftpFile) retrieve(ctx context.Context, dialer *net.Dialer, filePath string, options FTPOptions) error {
+	if _, _, err := f.control.ReadResponse(220); err != nil {
+		return ftpError(err)
+	}
+
+	code, message, err := f.command(0, "USER "+options.User)
+	if err != nil {
+		return err
+	}
+	if code == 331 {
+		if code, message, err = f.command(0, "PASS "+options.Password); err != nil {
+			return err
+		}
+	}
+	if code != 230 && code != 202 {
+		return errors.New("FTP error: " + strconv.Itoa(code) + " " + message)
+	}
+
+	if _, _, err = f.command(200, "TYPE I"); err != nil {
+		return err
+	}
+
+	port, err := f.passivePort()
+	if err != nil {
+		return err
+	}
+	// The data connection goes to the host of the control connection, whatever the address announced by PASV
+	host, _, err := net.SplitHostPort(f.conn.RemoteAddr().String())
+	if err != nil {
+		return err
+	}
+	data, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	f.data = data
+	f.mu.Unlock()
+
+	_, _, err = f.command(1, "RETR "+filePath)
+	return err
+}
+
+// passivePort enters the passive mode with EPSV, or PASV for the servers without it, & returns the port of the data connection
+func (f *ftpFile) passivePort() (string, error) {
+	if _, message, err := f.command(229, "EPSV"); err == nil {
+		// Ex: Entering Extended Passive Mode (|||6446|)
+		start := strings.Index(message, "(")
+		end := strings.LastIndex(message, ")")
+		if start < 0 || end < start {
+			return "", errors.New("FTP error: invalid EPSV response: " + message)
+		}
+		fields := strings.Split(message[start+1:end], "|")
+		if len(fields) != 5 {
+			return "", errors.New("FTP error: invalid EPSV response: " + message)
+		}
+		if _, err = strconv.ParseUint(fields[3], 10, 16); err != nil {
+			return "", errors.New("FTP error: invalid EPSV response: " + message)
+		}
+		return fields[3], nil
+	}
+
+	_, message, err := f.command(227, "PASV")
+	if err != nil {
+		return "", err
+	}
+	// Ex: Entering Passive Mode (127,0,0,1,25,46)
+	start := strings.Index(message, "(")
+	end := strings.LastIndex(message, ")")
+	if start < 0 || end < start {
+		return "", errors.New("FTP error: invalid PASV response: " + message)
+	}
+	fields := strings.Split(message[start+1:end], ",")
+	if len(fields) != 6 {
+		return "", errors.New("FTP error: invalid PASV response: " + message)
+	}
+	high, err := strconv.ParseUint(fields[4], 10, 8)
+	if err != nil {
+		return "", errors.New("FTP error: invalid PASV response: " + message)
+	}
+	low, err := strconv.ParseUint(fields[5], 10, 8)
+	if err != nil {
+		return "", errors.New("FTP error: invalid PASV response: " + message)
+	}
+	return strconv.Itoa(int(high<<8 | low)), nil
+}
+
+// command sends a command & reads its response, which must have the expected code or code prefix, unless expected is 0
+func (f *ftpFile) command(expected int, command string) (int, string, error) {
+	if err := f.control.PrintfLine("%s", command); err != nil {
+		return 0, "", err
+	}
+
+	code, message, err := f.control.ReadResponse(expected)
+	if err != nil {
+		return 0, "", ftpError(err)
+	}
+	return code, message, nil
+}
+
+// Read reads the data connection, then checks that the server completed the transfer, so that a truncated file isn't read as complete
+func (f *ftpFile) Read(p []byte) (int, error) {
+	if f.complete {
+		return 0, io.EOF
+	}
+
+	n, err := f.data.Read(p)
+	if err != io.EOF {
+		return n, err
+	}
+
+	if _, _, err = f.control.ReadResponse(2); err != nil {
+		return n, ftpError(err)
+	}
+	f.complete = true
+	return n, io.EOF
+}
+
+// Close closes the data connection, then logs out
+func (f *ftpFile) Close() error {
+	defer func() {
+		close(f.done)
+		f.conn.Close()
+	}()
+
+	if !f.complete {
+		f.abort()
+		return nil
+	}
+
+	f.data.Close()
+	f.control.PrintfLine("QUIT")
+	return nil
+}
+
+// abort closes the connections, which interrupts the transfer
+func (f *ftpFile) abort() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.data != nil {
+		f.data.Close()
+	}
+	f.conn.Close()
+}
+
+// ftpError returns the error of an unexpected response with the FTP prefix of the errors of the servers
+func ftpError(err error) error {
+	var protocolErr *textproto.Error
+	if errors.As(err, &protocolErr) {
+		return errors.New("FTP error: " + strconv.Itoa(protocolErr.Code) + " " + protocolErr.Msg)
+	}
+	return err
+}
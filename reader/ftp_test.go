@@ -0,0 +1,205 @@
+package reader
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ftpTestServer serves files through a minimal FTP server in passive mode
+// noEPSV makes the server refuse EPSV like the servers only supporting PASV, & aborted fails the transfers once their data is sent
+type ftpTestServer struct {
+	files    map[string]string
+	password string
+	noEPSV   bool
+	aborted  bool
+}
+
+// start starts the server & returns its address
+func (s *ftpTestServer) start(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func (s *ftpTestServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	control := textproto.NewConn(conn)
+	control.PrintfLine("220 Ready")
+
+	var data net.Listener
+	defer func() {
+		if data != nil {
+			data.Close()
+		}
+	}()
+	for {
+		line, err := control.ReadLine()
+		if err != nil {
+			return
+		}
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch command {
+		case "USER":
+			control.PrintfLine("331 Password required")
+		case "PASS":
+			if argument != s.password {
+				control.PrintfLine("530 Login incorrect")
+				continue
+			}
+			control.PrintfLine("230 Logged in")
+		case "TYPE":
+			control.PrintfLine("200 Type set")
+		case "EPSV", "PASV":
+			if command == "EPSV" && s.noEPSV {
+				control.PrintfLine("502 Command not implemented")
+				continue
+			}
+			if data, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+				return
+			}
+			port := data.Addr().(*net.TCPAddr).Port
+			if command == "EPSV" {
+				control.PrintfLine("229 Entering Extended Passive Mode (|||%d|)", port)
+				continue
+			}
+			// The announced host is ignored by the client
+			control.PrintfLine("227 Entering Passive Mode (10,0,0,1,%d,%d)", port>>8, port&0xff)
+		case "RETR":
+			content, ok := s.files[argument]
+			if !ok {
+				control.PrintfLine("550 No such file")
+				continue
+			}
+			control.PrintfLine("150 Opening data connection")
+			dataConn, err := data.Accept()
+			if err != nil {
+				return
+			}
+			dataConn.Write([]byte(content))
+			dataConn.Close()
+			if s.aborted {
+				control.PrintfLine("451 Transfer aborted")
+				continue
+			}
+			control.PrintfLine("226 Transfer complete")
+		case "QUIT":
+			control.PrintfLine("221 Bye")
+			return
+		default:
+			control.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestFromFTP(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id,name\n")
+	for i := 0; i < 1000; i++ {
+		content.WriteString(strconv.Itoa(i) + ",name " + strconv.Itoa(i) + "\n")
+	}
+
+	tests := []struct {
+		name    string
+		server  *ftpTestServer
+		options FTPOptions
+	}{
+		{name: "anonymous", server: &ftpTestServer{password: "anonymous"}},
+		{name: "credentials", server: &ftpTestServer{password: "secret"}, options: FTPOptions{User: "user", Password: "secret"}},
+		{name: "PASV", server: &ftpTestServer{password: "anonymous", noEPSV: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.server.files = map[string]string{"/data/file.csv": content.String()}
+			addr := tt.server.start(t)
+
+			records, err := NewCSV(CSVOptions{FTP: tt.options}).FromFTP(context.Background(), addr, "/data/file.csv")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 1000 {
+				t.Fatalf("FromFTP() returned %d records, want %d", len(records), 1000)
+			}
+			if expected := map[string]string{"id": "999", "name": "name 999"}; !reflect.DeepEqual(records[999], expected) {
+				t.Errorf("FromFTP() last record = %v, want %v", records[999], expected)
+			}
+		})
+	}
+}
+
+func TestFTPSource(t *testing.T) {
+	server := &ftpTestServer{files: map[string]string{"/file.csv": "id\n1\n"}, password: "anonymous"}
+	addr := server.start(t)
+
+	records, err := NewCSV(CSVOptions{SourceColumn: "source"}).FromSource(context.Background(), FTPSource(addr, "/file.csv", CSVOptions{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []map[string]string{{"id": "1", "source": "ftp://" + addr + "/file.csv"}}; !reflect.DeepEqual(records, expected) {
+		t.Errorf("FromSource() = %v, want %v", records, expected)
+	}
+}
+
+func TestFromFTPErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   *ftpTestServer
+		filePath string
+		expected string
+	}{
+		{
+			name:     "login",
+			server:   &ftpTestServer{password: "secret"},
+			filePath: "/file.csv",
+			expected: "FTP error: 530 Login incorrect",
+		},
+		{
+			name:     "missing file",
+			server:   &ftpTestServer{password: "anonymous"},
+			filePath: "/missing.csv",
+			expected: "FTP error: 550 No such file",
+		},
+		{
+			// The data read before the failure of the transfer isn't returned as the complete file
+			name:     "aborted transfer",
+			server:   &ftpTestServer{password: "anonymous", aborted: true},
+			filePath: "/file.csv",
+			expected: "FTP error: 451 Transfer aborted",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.server.files = map[string]string{"/file.csv": "id\n1\n"}
+			addr := tt.server.start(t)
+
+			_, err := NewCSV(CSVOptions{}).FromFTP(context.Background(), addr, tt.filePath)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("FromFTP() error = %v, want %s", err, tt.expected)
+			}
+		})
+	}
+}
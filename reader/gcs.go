@@ -0,0 +1,66 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GCSOptions consists of the options used to read objects from Google Cloud Storage
+// HTTPClient is an authorized client (ex: from golang.org/x/oauth2/google) used for the requests. Defaults to the reader's HTTPClient
+// AccessToken is an OAuth2 bearer token sent with every request. It is not needed when HTTPClient already handles the authorization
+// Endpoint is required only for emulators or private endpoints. Default value is "https://storage.googleapis.com"
+type GCSOptions struct {
+	HTTPClient  *http.Client
+	AccessToken string
+	Endpoint    string
+}
+
+const gcsScheme = "gs://"
+
+// FromGCS reads the CSV from a Cloud Storage object addressed as gs://bucket/object
+func (c *csv) FromGCS(ctx context.Context, uri string) ([]map[string]string, error) {
+	return c.FromSource(ctx, c.gcsSource(uri))
+}
+
+func (c *csv) gcsSource(uri string) NamedSource {
+	options := c.options.GCS
+	if options.HTTPClient == nil {
+		options.HTTPClient = c.options.HTTPClient
+	}
+	if options.Endpoint == "" {
+		options.Endpoint = "https://storage.googleapis.com"
+	}
+
+	return &requestSource{
+		csv:    c,
+		name:   uri,
+		client: options.HTTPClient,
+		newRequest: func(ctx context.Context) (*http.Request, error) {
+			return newGCSRequest(ctx, uri, options)
+		},
+	}
+}
+
+func newGCSRequest(ctx context.Context, uri string, options GCSOptions) (*http.Request, error) {
+	if !strings.HasPrefix(uri, gcsScheme) {
+		return nil, errors.New("Invalid GCS URI: " + uri)
+	}
+	parts := strings.SplitN(strings.TrimPrefix(uri, gcsScheme), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.New("Invalid GCS URI: " + uri)
+	}
+
+	objectURL := strings.TrimSuffix(options.Endpoint, "/") + "/storage/v1/b/" + url.PathEscape(parts[0]) + "/o/" + url.PathEscape(parts[1]) + "?alt=media"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if options.AccessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+options.AccessToken)
+	}
+
+	return req, nil
+}
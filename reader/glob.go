@@ -0,0 +1,42 @@
+package reader
+
+import (
+	"context"
+	"path/filepath"
+)
+
+// FromGlob reads all the files matching the pattern (ex: "exports/*.csv") in lexical order & merges their records
+// Files may have different columns. The records are completed with empty values for the columns of the other files
+func (c *csv) FromGlob(ctx context.Context, pattern string) ([]map[string]string, error) {
+	filePaths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []map[string]string
+	columns := make(map[string]bool)
+	for _, filePath := range filePaths {
+		records, err := c.FromPath(ctx, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			for key := range record {
+				columns[key] = true
+			}
+		}
+		lines = append(lines, records...)
+	}
+
+	// Use the union of the columns for every record
+	for _, record := range lines {
+		for key := range columns {
+			if _, ok := record[key]; !ok {
+				record[key] = ""
+			}
+		}
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,79 @@
+package reader
+
+import (
+	"errors"
+	"strconv"
+)
+
+// DuplicateHeaderPolicy is the handling of columns sharing the same name
+type DuplicateHeaderPolicy int
+
+// Duplicate header policies
+// DuplicateHeadersKeepLast keeps the value of the last duplicate column. This is the default policy
+// DuplicateHeadersKeepFirst keeps the value of the first duplicate column
+// DuplicateHeadersError fails the read
+// DuplicateHeadersSuffix renames the duplicate columns with a numeric suffix (ex: email, email_2, email_3)
+const (
+	DuplicateHeadersKeepLast DuplicateHeaderPolicy = iota
+	DuplicateHeadersKeepFirst
+	DuplicateHeadersError
+	DuplicateHeadersSuffix
+)
+
+// resolveHeaders applies the duplicate header policy to the column names of the iterator
+func (it *recordIterator) resolveHeaders() error {
+	counts := make(map[string]int, len(it.mapKeys))
+	for _, key := range it.mapKeys {
+		counts[key]++
+		if counts[key] == 2 {
+			it.duplicates = append(it.duplicates, key)
+		}
+	}
+	if len(it.duplicates) == 0 {
+		return nil
+	}
+	it.options.Logger.Warn("Duplicate column names", "source", it.source, "columns", it.duplicates)
+
+	switch it.options.DuplicateHeaders {
+	case DuplicateHeadersError:
+		return errors.New("Duplicate CSV header: " + it.duplicates[0])
+	case DuplicateHeadersKeepFirst:
+		it.skipColumns = make([]bool, len(it.mapKeys))
+		seen := make(map[string]bool, len(it.mapKeys))
+		for i, key := range it.mapKeys {
+			it.skipColumns[i] = seen[key]
+			seen[key] = true
+		}
+	case DuplicateHeadersSuffix:
+		keys := make([]string, len(it.mapKeys))
+		used := make(map[string]bool, len(it.mapKeys))
+		for _, key := range it.mapKeys {
+			used[key] = true
+		}
+		seen := make(map[string]int, len(it.mapKeys))
+		for i, key := range it.mapKeys {
+			seen[key]++
+			keys[i] = key
+			if seen[key] == 1 {
+				continue
+			}
+
+			// Skip the suffixes which are already used by other columns
+			suffix := seen[key]
+			for used[key+"_"+strconv.Itoa(suffix)] {
+				suffix++
+			}
+			seen[key] = suffix
+			keys[i] = key + "_" + strconv.Itoa(suffix)
+			used[keys[i]] = true
+		}
+		it.mapKeys = keys
+	}
+
+	return nil
+}
+
+// DuplicateHeaders returns the column names which appear more than once in the CSV
+func (it *recordIterator) DuplicateHeaders() []string {
+	return it.duplicates
+}
@@ -0,0 +1,463 @@
+package reader
+
+import (
+	"bufio"
+	"context"
+	gocsv "encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordIterator iterates over the records of a CSV one at a time
+// Next returns io.EOF once all the records have been read
+// Close releases the resources held by the iterator
+// BOM returns the byte order mark which was stripped from the start of the CSV, if any
+// Line returns the line number of the last record returned by Next
+// DuplicateHeaders returns the column names which appear more than once in the CSV
+type RecordIterator interface {
+	Next() (map[string]string, error)
+	Close() error
+	BOM() BOM
+	Line() int
+	DuplicateHeaders() []string
+}
+
+// rowReader reads the rows of a tabular source one at a time
+// Line returns the line (or row) number of the last row read
+type rowReader interface {
+	Read() ([]string, error)
+	Line() int
+}
+
+// keyedRowReader is a rowReader whose rows carry their own column names (ex: JSON objects)
+// Keys returns the column names of the last row read
+type keyedRowReader interface {
+	rowReader
+	Keys() []string
+}
+
+// positionedRowReader is a rowReader which knows where its rows end in the text, so that a read can be resumed from there
+// Position returns the byte offset of the end of the last row read & the line it ends on
+type positionedRowReader interface {
+	rowReader
+	Position() (offset int64, line int)
+}
+
+// csvRowReader is the rowReader of the delimited files
+type csvRowReader struct {
+	reader *gocsv.Reader
+	fields []string
+}
+
+func (r *csvRowReader) Read() ([]string, error) {
+	fields, err := r.reader.Read()
+	r.fields = fields
+	return fields, err
+}
+
+func (r *csvRowReader) Line() int {
+	line, _ := r.reader.FieldPos(0)
+	return line
+}
+
+func (r *csvRowReader) Position() (int64, int) {
+	if len(r.fields) == 0 {
+		return r.reader.InputOffset(), 0
+	}
+	// Quoted fields can span several lines
+	last := len(r.fields) - 1
+	line, _ := r.reader.FieldPos(last)
+	return r.reader.InputOffset(), line + strings.Count(r.fields[last], "\n")
+}
+
+type recordIterator struct {
+	ctx     context.Context
+	source  string
+	options CSVOptions
+	reader  rowReader
+	closers []io.Closer
+	mapKeys []string
+	bom     BOM
+	rows    int
+	line    int
+	// keys are the column names of the last record read
+	keys []string
+
+	// duplicates are the duplicate column names & skipColumns the columns ignored because of them
+	duplicates  []string
+	skipColumns []bool
+
+	// normalizedKeys caches the transformed column names of the keyed rows
+	normalizedKeys map[string]string
+
+	// input counts the bytes read from the source for the progress reports
+	input        *countingReader
+	progressDone bool
+
+	// lookahead holds the rows read ahead of the current one to detect the footer rows
+	lookahead []bufferedLine
+
+	// headers are the column names as read from the header row or the options, before their normalization
+	headers []string
+	// offset & endLine are the position of the end of the last row read, see positionedRowReader
+	// Rows read after a resumed checkpoint are positioned relative to it, by lineOffset & offsetBase
+	offset     int64
+	endLine    int
+	offsetBase int64
+	lineOffset int
+	checkpoint *checkpointer
+	dedupe     *deduplicator
+
+	metrics metricsState
+}
+
+type bufferedLine struct {
+	fields []string
+	line   int
+	// keys are the column names of the rows of a keyedRowReader
+	keys []string
+	// offset & endLine are the position of the end of the row, if known
+	offset  int64
+	endLine int
+	// truncated are the indexes of the fields truncated to MaxCellBytes
+	truncated []int
+}
+
+func (c *csv) newRecordIterator(ctx context.Context, source string, csvData io.Reader) (*recordIterator, error) {
+	input, err := c.openText(csvData)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := gocsv.NewReader(input.text)
+	reader.Comma = c.options.Delimiter
+	reader.Comment = c.options.Comment
+	reader.LazyQuotes = c.options.LazyQuotes
+	// The field count of the records is checked by the iterator, since the leading & footer rows can have any number of fields
+	reader.FieldsPerRecord = -1
+
+	return c.newTextIterator(ctx, source, input, &csvRowReader{reader: reader})
+}
+
+// textInput is a text source prepared for reading
+type textInput struct {
+	text    io.Reader
+	data    io.ReadCloser
+	counter *countingReader
+	bom     BOM
+}
+
+// openText decompresses, limits & decodes a text source into UTF-8
+func (c *csv) openText(src io.Reader) (*textInput, error) {
+	counter := &countingReader{reader: src}
+
+	data, err := decompress(bufio.NewReader(counter))
+	if err != nil {
+		return nil, err
+	}
+
+	// The limit applies to the decompressed data, which protects against decompression bombs too
+	var input io.Reader = data
+	if c.options.MaxBytes > 0 {
+		input = &limitedReader{reader: data, max: c.options.MaxBytes}
+	}
+
+	text, bom, err := stripBOM(bufio.NewReader(input))
+	if err != nil {
+		data.Close()
+		return nil, err
+	}
+
+	// UTF-16 input is already converted to UTF-8 while stripping its BOM
+	if c.options.Encoding != "" && bom != BOMUTF16LE && bom != BOMUTF16BE {
+		text, err = decode(text, c.options.Encoding)
+		if err != nil {
+			data.Close()
+			return nil, err
+		}
+	}
+
+	return &textInput{text: text, data: data, counter: counter, bom: bom}, nil
+}
+
+// newTextIterator builds the record iterator of the rows read from a text source
+func (c *csv) newTextIterator(ctx context.Context, source string, input *textInput, rows rowReader) (*recordIterator, error) {
+	it, err := c.newRowIterator(ctx, source, rows, []io.Closer{input.data})
+	if err != nil {
+		return nil, err
+	}
+	it.input = input.counter
+	it.bom = input.bom
+	if it.bom != "" {
+		c.options.Logger.Debug("Byte order mark stripped", "source", source, "bom", string(it.bom))
+	}
+
+	return it, nil
+}
+
+// newRowIterator builds the record iterator of a tabular source from its rows
+// The closers are closed along with the iterator, or right away if it can't be built
+func (c *csv) newRowIterator(ctx context.Context, source string, rows rowReader, closers []io.Closer) (*recordIterator, error) {
+	var err error
+	it := &recordIterator{
+		ctx:     ctx,
+		source:  source,
+		options: c.options,
+		reader:  rows,
+		closers: closers,
+		metrics: metricsState{started: time.Now()},
+	}
+
+	if c.options.SkipRows > 0 {
+		for i := 0; i < c.options.SkipRows; i++ {
+			_, err = it.reader.Read()
+			if err == io.EOF {
+				return it, nil
+			}
+			if err != nil {
+				it.Close()
+				return nil, err
+			}
+		}
+		c.options.Logger.Debug("Leading rows skipped", "source", source, "rows", c.options.SkipRows)
+	}
+
+	// The rows of keyed sources have their own column names
+	if _, ok := rows.(keyedRowReader); ok {
+		it.mapKeys = []string{}
+		return it, nil
+	}
+
+	// Headerless CSVs use the column names provided in the options
+	if len(c.options.Headers) > 0 {
+		it.mapKeys = c.options.Headers
+	} else {
+		it.mapKeys, err = it.reader.Read()
+		if err != nil && err != io.EOF {
+			it.Close()
+			return nil, err
+		}
+	}
+
+	if it.mapKeys != nil {
+		it.headers = it.mapKeys
+		it.normalizeHeaders()
+		if err = it.resolveHeaders(); err != nil {
+			it.Close()
+			return nil, err
+		}
+	}
+
+	return it, nil
+}
+
+// Next returns the next record of the CSV which passes the row filter
+func (it *recordIterator) Next() (map[string]string, error) {
+	if err := it.saveCheckpoint(); err != nil {
+		it.finishMetrics(err)
+		return nil, err
+	}
+
+	record, err := it.nextRecord()
+	if err == io.EOF {
+		err = it.clearCheckpoint()
+		it.finishMetrics(err)
+		if err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	if err != nil {
+		it.finishMetrics(err)
+		return nil, err
+	}
+
+	if it.checkpoint != nil {
+		it.checkpoint.pending++
+	}
+	if it.options.Profiler != nil {
+		it.options.Profiler.add(it.keys, record)
+	}
+	return record, nil
+}
+
+// nextRecord returns the next record which passes the row filter & isn't a duplicate
+func (it *recordIterator) nextRecord() (map[string]string, error) {
+	if len(it.options.DedupeColumns) > 0 && it.options.DedupePolicy == DedupeKeepLast {
+		return it.nextLastRecord()
+	}
+
+	for {
+		record, err := it.nextFilteredRecord()
+		if err != nil {
+			return nil, err
+		}
+		if len(it.options.DedupeColumns) == 0 || !it.isDuplicate(record) {
+			return record, nil
+		}
+		it.options.Logger.Debug("Duplicate row dropped", "source", it.source, "line", it.line)
+	}
+}
+
+// nextFilteredRecord returns the next record which passes the row filter
+func (it *recordIterator) nextFilteredRecord() (map[string]string, error) {
+	for {
+		record, err := it.readRecord()
+		if err != nil {
+			return nil, err
+		}
+
+		if it.options.RowFilter == nil || it.options.RowFilter(record) {
+			return record, nil
+		}
+		it.options.Logger.Debug("Row filtered out", "source", it.source, "line", it.line)
+	}
+}
+
+// readRecord reads the next row of the CSV & builds its record
+func (it *recordIterator) readRecord() (map[string]string, error) {
+	if it.mapKeys == nil {
+		return nil, io.EOF
+	}
+	if err := it.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	next, err := it.readLine()
+	if err == io.EOF {
+		it.reportProgress(true)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	it.rows++
+	if it.options.MaxRows > 0 && it.rows > it.options.MaxRows {
+		return nil, &LimitError{Option: "MaxRows", Max: int64(it.options.MaxRows)}
+	}
+	it.reportProgress(false)
+	it.reportMetrics()
+
+	it.line = next.line
+	it.offset, it.endLine = next.offset, next.endLine
+
+	keys, skipColumns := it.mapKeys, it.skipColumns
+	if next.keys != nil {
+		keys, skipColumns = it.normalizeKeys(next.keys), nil
+	}
+
+	it.keys = keys
+	record := make(map[string]string)
+	for i, val := range next.fields {
+		if skipColumns != nil && skipColumns[i] {
+			continue
+		}
+		record[keys[i]] = it.trim(keys[i], val)
+	}
+	if it.options.LineColumn != "" {
+		record[it.options.LineColumn] = strconv.Itoa(next.line)
+	}
+	if it.options.SourceColumn != "" {
+		record[it.options.SourceColumn] = it.source
+	}
+	if it.options.TruncatedColumn != "" && len(next.truncated) > 0 {
+		truncated := make([]string, len(next.truncated))
+		for i, index := range next.truncated {
+			truncated[i] = keys[index]
+		}
+		record[it.options.TruncatedColumn] = strings.Join(truncated, ",")
+	}
+
+	return record, nil
+}
+
+// readLine returns the next row of the CSV, skipping the malformed rows of the lenient reads
+func (it *recordIterator) readLine() (bufferedLine, error) {
+	for {
+		next, err := it.readRow()
+		var rowErr *RowError
+		if it.options.Mode == ReadLenient && errors.As(err, &rowErr) {
+			it.skipRow(rowErr)
+			continue
+		}
+		return next, err
+	}
+}
+
+// readRow returns the next row of the CSV, holding back the footer rows
+func (it *recordIterator) readRow() (bufferedLine, error) {
+	for len(it.lookahead) <= it.options.SkipFooterRows {
+		fields, err := it.reader.Read()
+		if err == io.EOF {
+			// The rows left in the lookahead are the footer rows
+			if len(it.lookahead) > 0 {
+				it.options.Logger.Debug("Footer rows skipped", "source", it.source, "rows", len(it.lookahead))
+				it.lookahead = nil
+			}
+			return bufferedLine{}, io.EOF
+		}
+		if err != nil {
+			return bufferedLine{}, it.rowError(err)
+		}
+
+		row := bufferedLine{fields: fields, line: it.reader.Line() + it.lineOffset}
+		if keyed, ok := it.reader.(keyedRowReader); ok {
+			row.keys = keyed.Keys()
+		}
+		if positioned, ok := it.reader.(positionedRowReader); ok {
+			offset, endLine := positioned.Position()
+			row.offset, row.endLine = it.offsetBase+offset, endLine+it.lineOffset
+		}
+		if it.options.MaxCellBytes > 0 {
+			if err = it.limitCells(&row); err != nil {
+				return bufferedLine{}, err
+			}
+		}
+		it.lookahead = append(it.lookahead, row)
+	}
+
+	next := it.lookahead[0]
+	it.lookahead = it.lookahead[1:]
+	if next.keys != nil {
+		return next, nil
+	}
+
+	if it.options.PadShortRows && len(next.fields) < len(it.mapKeys) {
+		padding := make([]string, len(it.mapKeys)-len(next.fields))
+		next.fields = append(next.fields, padding...)
+		it.options.Logger.Debug("Short row padded", "source", it.source, "line", next.line, "fields", len(next.fields)-len(padding))
+	}
+	if len(next.fields) != len(it.mapKeys) {
+		parseErr := &gocsv.ParseError{StartLine: next.line, Line: next.line, Column: 1, Err: gocsv.ErrFieldCount}
+		return bufferedLine{}, &RowError{Source: it.source, Line: next.line, Reason: gocsv.ErrFieldCount.Error(), Err: parseErr}
+	}
+
+	return next, nil
+}
+
+// Close releases the underlying sources owned by the iterator
+func (it *recordIterator) Close() error {
+	// Reads which are abandoned before their end are over too
+	it.finishMetrics(nil)
+
+	var err error
+	for i := len(it.closers) - 1; i >= 0; i-- {
+		if closeErr := it.closers[i].Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// BOM returns the byte order mark stripped from the start of the CSV
+func (it *recordIterator) BOM() BOM {
+	return it.bom
+}
+
+// Line returns the line number of the last record returned by Next
+func (it *recordIterator) Line() int {
+	return it.line
+}
@@ -0,0 +1,165 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// KafkaPayload is the format of the payloads of the messages of a Kafka topic
+type KafkaPayload int
+
+const (
+	// KafkaCSV payloads are CSV lines without header row
+	KafkaCSV KafkaPayload = iota
+	// KafkaJSON payloads are JSON objects, one per line, which are flattened like NDJSON
+	KafkaJSON
+)
+
+// KafkaMessage is a message consumed from a Kafka topic
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Time      time.Time
+}
+
+// KafkaConsumer fetches the messages of a Kafka topic & commits their offsets, usually as a member of a consumer group
+// It wraps the Kafka client of the application (ex: FetchMessage & CommitMessages of the segmentio/kafka-go Reader)
+type KafkaConsumer interface {
+	FetchMessage(ctx context.Context) (KafkaMessage, error)
+	CommitMessages(ctx context.Context, messages ...KafkaMessage) error
+}
+
+// KafkaOptions consists of the Kafka source options available
+// CSVOptions apply to the payload of every message. Since messages don't carry the header row, Headers are required for CSV payloads
+// Payload is the format of the payloads. Default value is KafkaCSV
+// KeyDelimiter joins the keys of the nested objects of JSON payloads into column names. Default value is "."
+// CommitInterval is the number of handled messages whose offsets are committed together. Default value is 1
+// BeforeCommit is called with the messages about to be committed (ex: to flush a sink). Returning an error stops the consumption without committing them
+// AfterCommit is called with the messages once their offsets are committed
+type KafkaOptions struct {
+	CSVOptions
+	Payload        KafkaPayload
+	KeyDelimiter   string
+	CommitInterval int
+	BeforeCommit   func(ctx context.Context, messages []KafkaMessage) error
+	AfterCommit    func(ctx context.Context, messages []KafkaMessage)
+}
+
+// Kafka is a lightweight interface for consuming records from a Kafka topic
+type Kafka interface {
+	Consume(ctx context.Context, consumer KafkaConsumer, handle func(record Record, message KafkaMessage) error) error
+}
+
+type kafka struct {
+	csv     *csv
+	ndjson  *ndjson
+	options KafkaOptions
+}
+
+// Consume reads the records of the messages of the consumer until the context is cancelled or the consumer is exhausted
+// The offset of a message is committed once all its records are handled, so messages are processed at least once
+// Consumption stops at the first error of the consumer, of a payload or of handle, after committing the messages handled so far
+func (k *kafka) Consume(ctx context.Context, consumer KafkaConsumer, handle func(record Record, message KafkaMessage) error) error {
+	if k.options.Payload == KafkaCSV && len(k.options.Headers) == 0 {
+		return errors.New("Headers are required to read CSV payloads from Kafka")
+	}
+
+	var pending []KafkaMessage
+	// Handled messages are committed on the way out, even when the context is cancelled
+	commitPending := func(err error) error {
+		if len(pending) == 0 {
+			return err
+		}
+		if commitErr := k.commit(context.WithoutCancel(ctx), consumer, pending); commitErr != nil && err == nil {
+			return commitErr
+		}
+		return err
+	}
+
+	for {
+		message, err := consumer.FetchMessage(ctx)
+		if err == io.EOF {
+			return commitPending(nil)
+		}
+		if err != nil {
+			return commitPending(err)
+		}
+
+		if err = k.handleMessage(ctx, message, handle); err != nil {
+			return commitPending(err)
+		}
+
+		pending = append(pending, message)
+		if len(pending) >= k.options.CommitInterval {
+			if err = k.commit(ctx, consumer, pending); err != nil {
+				return err
+			}
+			pending = nil
+		}
+	}
+}
+
+// handleMessage reads the records of the payload of a message
+func (k *kafka) handleMessage(ctx context.Context, message KafkaMessage, handle func(record Record, message KafkaMessage) error) error {
+	var it *recordIterator
+	var err error
+	if k.options.Payload == KafkaJSON {
+		it, err = k.ndjson.open(ctx, message.Topic, bytes.NewReader(message.Value))
+	} else {
+		it, err = k.csv.newRecordIterator(ctx, message.Topic, bytes.NewReader(message.Value))
+	}
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for {
+		values, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = handle(Record{Values: values, Line: it.Line(), Source: message.Topic}, message); err != nil {
+			return err
+		}
+	}
+}
+
+func (k *kafka) commit(ctx context.Context, consumer KafkaConsumer, messages []KafkaMessage) error {
+	if k.options.BeforeCommit != nil {
+		if err := k.options.BeforeCommit(ctx, messages); err != nil {
+			return err
+		}
+	}
+
+	if err := consumer.CommitMessages(ctx, messages...); err != nil {
+		return err
+	}
+
+	if k.options.AfterCommit != nil {
+		k.options.AfterCommit(ctx, messages)
+	}
+	return nil
+}
+
+// NewKafka is the initialization method for the Kafka source
+func NewKafka(options KafkaOptions) Kafka {
+	if options.CommitInterval <= 0 {
+		options.CommitInterval = 1
+	}
+
+	return &kafka{
+		csv:     NewCSV(options.CSVOptions).(*csv),
+		ndjson:  NewNDJSON(NDJSONOptions{CSVOptions: options.CSVOptions, KeyDelimiter: options.KeyDelimiter}).(*ndjson),
+		options: options,
+	}
+}
@@ -0,0 +1,79 @@
+package reader
+
+import (
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// LimitError is returned when a read exceeds one of the configured limits
+// Option is the name of the exceeded option (ex: "MaxRows") & Max its value
+type LimitError struct {
+	Option string
+	Max    int64
+}
+
+func (e *LimitError) Error() string {
+	return "CSV exceeds the " + e.Option + " limit of " + strconv.FormatInt(e.Max, 10)
+}
+
+// limitedReader fails with a LimitError once more than max bytes are read
+type limitedReader struct {
+	reader io.Reader
+	max    int64
+	read   int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.read > l.max {
+		return 0, &LimitError{Option: "MaxBytes", Max: l.max}
+	}
+
+	// Read at most one byte past the limit to detect that it is exceeded
+	if remaining := l.max - l.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+
+	n, err := l.reader.Read(p)
+	l.read += int64(n)
+	if l.read > l.max {
+		return n - int(l.read-l.max), &LimitError{Option: "MaxBytes", Max: l.max}
+	}
+
+	return n, err
+}
+
+// limitReader returns a reader failing with a LimitError once more than max bytes are read, or r when max isn't set
+func limitReader(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &limitedReader{reader: r, max: max}
+}
+
+// readLimited reads all the data of r, up to max bytes when it is set
+func readLimited(r io.Reader, max int64) ([]byte, error) {
+	return io.ReadAll(limitReader(r, max))
+}
+
+// limitCells checks the size of the fields of a row against MaxCellBytes, truncating the fields which exceed it when TruncateCells is set
+func (it *recordIterator) limitCells(row *bufferedLine) error {
+	for i, field := range row.fields {
+		if len(field) <= it.options.MaxCellBytes {
+			continue
+		}
+		if !it.options.TruncateCells {
+			return &LimitError{Option: "MaxCellBytes", Max: int64(it.options.MaxCellBytes)}
+		}
+
+		// The field is cut at the start of a character
+		end := it.options.MaxCellBytes
+		for end > 0 && !utf8.RuneStart(field[end]) {
+			end--
+		}
+		row.fields[i] = field[:end]
+		row.truncated = append(row.truncated, i)
+		it.options.Logger.Warn("Cell truncated", "source", it.source, "line", row.line, "bytes", len(field))
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package reader
+
+import "net/url"
+
+// logURL returns a URL without its query string & credentials, which may hold secrets (ex: signatures, API keys), for the logs
+func logURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.ForceQuery = false
+	redacted.Fragment = ""
+	return redacted.String()
+}
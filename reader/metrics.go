@@ -0,0 +1,78 @@
+package reader
+
+import "time"
+
+// Metrics receives the measures of the reads, so that they can be exported (ex: as Prometheus metrics) by source
+// source is the path or URL the records are read from, if any
+// RowsRead & BytesRead are called with the rows & bytes read since their previous call, every ProgressInterval records & once the read is over. The parallel reads only report them once their chunks are merged, & the reads resumed from a checkpoint only report the rows after it
+// Duration is called with the duration of a read once it is over, whether it succeeded or not
+// Errors is called with the error which stopped a read
+type Metrics interface {
+	RowsRead(source string, rows int)
+	BytesRead(source string, bytes int64)
+	Duration(source string, duration time.Duration)
+	Errors(source string, err error)
+}
+
+// metricsState is what was already reported to the metrics of a read
+type metricsState struct {
+	started time.Time
+	rows    int
+	bytes   int64
+	done    bool
+}
+
+// reportMetrics reports the rows & bytes read since the last report every ProgressInterval records
+func (it *recordIterator) reportMetrics() {
+	if it.options.Metrics == nil || it.rows%it.options.ProgressInterval != 0 {
+		return
+	}
+	it.flushMetrics()
+}
+
+// finishMetrics reports the end of the read & the error which stopped it, if any
+func (it *recordIterator) finishMetrics(err error) {
+	if it.options.Metrics == nil || it.metrics.done {
+		return
+	}
+	it.metrics.done = true
+
+	it.flushMetrics()
+	it.options.Metrics.Duration(it.source, time.Since(it.metrics.started))
+	if err != nil {
+		it.options.Metrics.Errors(it.source, err)
+	}
+}
+
+func (it *recordIterator) flushMetrics() {
+	if rows := it.rows - it.metrics.rows; rows > 0 {
+		it.options.Metrics.RowsRead(it.source, rows)
+		it.metrics.rows = it.rows
+	}
+	if it.input == nil {
+		return
+	}
+	if bytes := it.input.read - it.metrics.bytes; bytes > 0 {
+		it.options.Metrics.BytesRead(it.source, bytes)
+		it.metrics.bytes = it.input.read
+	}
+}
+
+// reportReadMetrics reports the measures of a read without its own iterator (ex: the merged chunks of a parallel read), once it is over
+func (c *csv) reportReadMetrics(source string, started time.Time, rows int, bytes int64, err error) {
+	metrics := c.options.Metrics
+	if metrics == nil {
+		return
+	}
+
+	if rows > 0 {
+		metrics.RowsRead(source, rows)
+	}
+	if bytes > 0 {
+		metrics.BytesRead(source, bytes)
+	}
+	metrics.Duration(source, time.Since(started))
+	if err != nil {
+		metrics.Errors(source, err)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build unix
+
+package reader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"syscall"
+)
+
+// openMapped maps a local file in memory
+func openMapped(filePath string) (io.ReadCloser, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	// The mapping stays valid once the file is closed
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return &mappedFile{Reader: bytes.NewReader(nil)}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, errors.New("File too large to be memory mapped: " + filePath)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mappedFile{Reader: bytes.NewReader(data), data: data}, nil
+}
+
+// mappedFile reads the memory mapping of a file, which is released on Close
+type mappedFile struct {
+	*bytes.Reader
+	data []byte
+}
+
+func (m *mappedFile) Close() error {
+	if m.data == nil {
+		return nil
+	}
+
+	data := m.data
+	m.data = nil
+	return syscall.Munmap(data)
+}
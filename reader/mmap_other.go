@@ -0,0 +1,13 @@
+//go:build !unix
+
+package reader
+
+import (
+	"io"
+	"os"
+)
+
+// openMapped opens a local file, which is read as usual on the platforms without memory mappings
+func openMapped(filePath string) (io.ReadCloser, error) {
+	return os.Open(filePath)
+}
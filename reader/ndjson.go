@@ -0,0 +1,174 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// NDJSONOptions consists of the NDJSON reader options available
+// CSVOptions apply to the lines of the file, except for the ones specific to delimited text (ex: Delimiter, Headers)
+// KeyDelimiter joins the keys of nested objects & the indexes of arrays into column names (ex: company.0.name), which matches the array delimiter of the parser. Default value is "."
+type NDJSONOptions struct {
+	CSVOptions
+	KeyDelimiter string
+}
+
+// NDJSON is a lightweight interface for reading newline-delimited JSON files
+// Every line is a JSON object which is flattened into a record
+type NDJSON interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, src io.Reader) (RecordIterator, error)
+}
+
+type ndjson struct {
+	csv     *csv
+	options NDJSONOptions
+}
+
+// FromPath reads an NDJSON file from a file path
+func (n *ndjson) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	it, err := n.open(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads an NDJSON file from an io.Reader
+func (n *ndjson) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	it, err := n.open(ctx, "", r)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the NDJSON file read from src
+func (n *ndjson) Open(ctx context.Context, src io.Reader) (RecordIterator, error) {
+	return n.open(ctx, "", src)
+}
+
+func (n *ndjson) open(ctx context.Context, source string, src io.Reader) (*recordIterator, error) {
+	input, err := n.csv.openText(src)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &ndjsonRowReader{
+		reader:       bufio.NewReader(input.text),
+		keyDelimiter: n.options.KeyDelimiter,
+	}
+
+	return n.csv.newTextIterator(ctx, source, input, rows)
+}
+
+// ndjsonRowReader flattens the JSON objects of the lines into keyed rows
+type ndjsonRowReader struct {
+	reader       *bufio.Reader
+	keyDelimiter string
+	line         int
+	keys         []string
+}
+
+func (r *ndjsonRowReader) Read() ([]string, error) {
+	for {
+		line, err := r.reader.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		r.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+
+		var object map[string]interface{}
+		if err = decoder.Decode(&object); err != nil {
+			return nil, errors.New("Invalid JSON object on line " + strconv.Itoa(r.line) + ": " + err.Error())
+		}
+
+		flattened := make(map[string]string)
+		r.flatten("", object, flattened)
+
+		r.keys = make([]string, 0, len(flattened))
+		for key := range flattened {
+			r.keys = append(r.keys, key)
+		}
+		sort.Strings(r.keys)
+
+		fields := make([]string, len(r.keys))
+		for i, key := range r.keys {
+			fields[i] = flattened[key]
+		}
+		return fields, nil
+	}
+}
+
+// flatten adds the values of a JSON value to the record, with the keys joined by the key delimiter
+func (r *ndjsonRowReader) flatten(prefix string, value interface{}, record map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + r.keyDelimiter + key
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			r.flatten(join(key), child, record)
+		}
+	case []interface{}:
+		for i, child := range v {
+			r.flatten(join(strconv.Itoa(i)), child, record)
+		}
+	case nil:
+		record[prefix] = ""
+	case string:
+		record[prefix] = v
+	case json.Number:
+		record[prefix] = v.String()
+	case bool:
+		record[prefix] = strconv.FormatBool(v)
+	}
+}
+
+func (r *ndjsonRowReader) Line() int {
+	return r.line
+}
+
+func (r *ndjsonRowReader) Keys() []string {
+	return r.keys
+}
+
+// NewNDJSON is the initialization method for the NDJSON reader
+func NewNDJSON(options NDJSONOptions) NDJSON {
+	if options.KeyDelimiter == "" {
+		options.KeyDelimiter = "."
+	}
+
+	return &ndjson{
+		csv:     NewCSV(options.CSVOptions).(*csv),
+		options: options,
+	}
+}
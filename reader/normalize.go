@@ -0,0 +1,114 @@
+package reader
+
+import (
+	"strings"
+	"unicode"
+)
+
+// HeaderTransform transforms a column name before the records are built
+type HeaderTransform func(header string) string
+
+// TrimHeader removes the leading & trailing whitespace of a column name
+func TrimHeader(header string) string {
+	return strings.TrimSpace(header)
+}
+
+// LowercaseHeader lowercases a column name
+func LowercaseHeader(header string) string {
+	return strings.ToLower(header)
+}
+
+// CollapseWhitespaceHeader replaces the runs of whitespace in a column name with a single space
+func CollapseWhitespaceHeader(header string) string {
+	return strings.Join(strings.Fields(header), " ")
+}
+
+// StripInvisibleHeader removes the BOMs, zero width & other invisible characters from a column name
+func StripInvisibleHeader(header string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return r
+		}
+		if unicode.Is(unicode.Cf, r) || !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, header)
+}
+
+// SnakeCaseHeader converts a column name to snake case (ex: " First Name " & "firstName" become "first_name")
+func SnakeCaseHeader(header string) string {
+	var b strings.Builder
+	runes := []rune(strings.TrimSpace(header))
+	separate := false
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			separate = b.Len() > 0
+			continue
+		}
+
+		// Split camel case words
+		if unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])) {
+			separate = b.Len() > 0
+		}
+
+		if separate {
+			b.WriteRune('_')
+			separate = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// normalizeHeaders applies the header aliases & transforms to the column names of the iterator
+func (it *recordIterator) normalizeHeaders() {
+	if len(it.options.HeaderTransforms) == 0 && len(it.options.HeaderAliases) == 0 {
+		return
+	}
+
+	keys := make([]string, len(it.mapKeys))
+	for i, key := range it.mapKeys {
+		keys[i] = it.normalizeKey(key)
+	}
+	it.mapKeys = keys
+}
+
+// normalizeKeys applies the header aliases & transforms to the column names of a keyed row
+// The normalized names are cached, since keyed rows usually share their column names
+func (it *recordIterator) normalizeKeys(keys []string) []string {
+	if len(it.options.HeaderTransforms) == 0 && len(it.options.HeaderAliases) == 0 {
+		return keys
+	}
+	if it.normalizedKeys == nil {
+		it.normalizedKeys = make(map[string]string)
+	}
+
+	normalized := make([]string, len(keys))
+	for i, key := range keys {
+		name, ok := it.normalizedKeys[key]
+		if !ok {
+			name = it.normalizeKey(key)
+			it.normalizedKeys[key] = name
+		}
+		normalized[i] = name
+	}
+	return normalized
+}
+
+// normalizeKey renames a column with its alias, looked up by its name as read then as transformed, or else transforms it
+func (it *recordIterator) normalizeKey(key string) string {
+	if alias, ok := it.options.HeaderAliases[key]; ok {
+		return alias
+	}
+
+	for _, transform := range it.options.HeaderTransforms {
+		key = transform(key)
+	}
+	if alias, ok := it.options.HeaderAliases[key]; ok {
+		return alias
+	}
+	return key
+}
@@ -0,0 +1,340 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// odsMaxColumns is the maximum number of columns of a sheet
+const odsMaxColumns = 16384
+
+type ods struct {
+	csv     *csv
+	options SpreadsheetOptions
+}
+
+// FromPath reads the sheet of an ods file
+func (o *ods) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	it, err := o.Open(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads the sheet of an ods file from an io.Reader
+// The file is buffered in memory, since ods files are zip archives
+func (o *ods) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	data, err := readLimited(r, o.options.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := o.open(ctx, "", archive, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the sheet of an ods file
+func (o *ods) Open(ctx context.Context, filePath string) (RecordIterator, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.open(ctx, filePath, &archive.Reader, archive)
+}
+
+func (o *ods) open(ctx context.Context, source string, archive *zip.Reader, closer io.Closer) (*recordIterator, error) {
+	closers := []io.Closer{}
+	if closer != nil {
+		closers = append(closers, closer)
+	}
+
+	rows, err := o.openSheet(archive)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	return o.csv.newRowIterator(ctx, source, rows, append(closers, rows))
+}
+
+// openSheet positions the reader at the start of the table of the sheet
+func (o *ods) openSheet(archive *zip.Reader) (*odsRowReader, error) {
+	var contentFile *zip.File
+	for _, file := range archive.File {
+		if file.Name == "content.xml" {
+			contentFile = file
+		}
+	}
+	if contentFile == nil {
+		return nil, errors.New("Invalid ods file: missing content.xml")
+	}
+
+	content, err := contentFile.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &odsRowReader{
+		content: content,
+		decoder: xml.NewDecoder(limitReader(content, o.options.MaxBytes)),
+	}
+
+	index := 0
+	for {
+		token, err := rows.decoder.Token()
+		if err == io.EOF {
+			content.Close()
+			return nil, errors.New("Sheet not found in the ods file")
+		}
+		if err != nil {
+			content.Close()
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "table" {
+			continue
+		}
+
+		name := odsAttr(start, "name")
+		if (o.options.Sheet == "" && index == o.options.SheetIndex) || (o.options.Sheet != "" && name == o.options.Sheet) {
+			return rows, nil
+		}
+		index++
+		if err = rows.decoder.Skip(); err != nil {
+			content.Close()
+			return nil, err
+		}
+	}
+}
+
+// odsRowReader streams the rows of a table
+type odsRowReader struct {
+	content io.ReadCloser
+	decoder *xml.Decoder
+	line    int
+	done    bool
+
+	// repeatedRow is emitted repeat more times for the rows with a repeat count
+	repeatedRow []string
+	repeat      int
+}
+
+func (r *odsRowReader) Read() ([]string, error) {
+	if r.repeat > 0 {
+		r.repeat--
+		r.line++
+		return r.repeatedRow, nil
+	}
+	if r.done {
+		return nil, io.EOF
+	}
+
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.EndElement:
+			if element.Name.Local == "table" {
+				r.done = true
+				return nil, io.EOF
+			}
+		case xml.StartElement:
+			if element.Name.Local != "table-row" {
+				continue
+			}
+
+			values, err := r.readRow()
+			if err != nil {
+				return nil, err
+			}
+
+			repeat := odsRepeat(element, "number-rows-repeated")
+			r.line += repeat
+
+			// Empty rows are skipped like the empty lines of a CSV
+			if len(values) == 0 {
+				continue
+			}
+			if repeat > 1 {
+				r.line -= repeat - 1
+				r.repeatedRow = values
+				r.repeat = repeat - 1
+			}
+			return values, nil
+		}
+	}
+}
+
+// readRow reads the cells of a row up to its end element
+func (r *odsRowReader) readRow() ([]string, error) {
+	var values []string
+	column := 0
+
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.EndElement:
+			if element.Name.Local == "table-row" {
+				// Formatted empty cells are not part of the data
+				for len(values) > 0 && values[len(values)-1] == "" {
+					values = values[:len(values)-1]
+				}
+				return values, nil
+			}
+		case xml.StartElement:
+			if element.Name.Local != "table-cell" && element.Name.Local != "covered-table-cell" {
+				continue
+			}
+
+			value, err := r.readCell(element)
+			if err != nil {
+				return nil, err
+			}
+
+			repeat := odsRepeat(element, "number-columns-repeated")
+			if value == "" {
+				column += repeat
+				continue
+			}
+			for i := 0; i < repeat && column < odsMaxColumns; i++ {
+				for len(values) < column {
+					values = append(values, "")
+				}
+				values = append(values, value)
+				column++
+			}
+		}
+	}
+}
+
+// readCell returns the value of a cell & consumes its content
+func (r *odsRowReader) readCell(start xml.StartElement) (string, error) {
+	text, err := r.readCellText()
+	if err != nil {
+		return "", err
+	}
+
+	switch odsAttr(start, "value-type") {
+	case "float", "percentage", "currency":
+		return odsAttr(start, "value"), nil
+	case "boolean":
+		return odsAttr(start, "boolean-value"), nil
+	case "time":
+		return odsAttr(start, "time-value"), nil
+	case "date":
+		value := odsAttr(start, "date-value")
+		for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+			if date, err := time.Parse(layout, value); err == nil {
+				return date.Format(time.RFC3339), nil
+			}
+		}
+		return value, nil
+	}
+
+	return text, nil
+}
+
+// readCellText returns the text paragraphs of a cell. Annotations are ignored
+func (r *odsRowReader) readCellText() (string, error) {
+	var paragraphs []string
+	var text strings.Builder
+	depth := 0
+
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch element.Name.Local {
+			case "annotation":
+				if err = r.decoder.Skip(); err != nil {
+					return "", err
+				}
+				depth--
+			case "p":
+				text.Reset()
+			case "s":
+				text.WriteString(strings.Repeat(" ", odsRepeat(element, "c")))
+			case "tab":
+				text.WriteString("\t")
+			case "line-break":
+				text.WriteString("\n")
+			}
+		case xml.CharData:
+			text.Write(element)
+		case xml.EndElement:
+			if depth == 0 {
+				return strings.Join(paragraphs, "\n"), nil
+			}
+			depth--
+			if element.Name.Local == "p" {
+				paragraphs = append(paragraphs, text.String())
+			}
+		}
+	}
+}
+
+func (r *odsRowReader) Line() int {
+	return r.line
+}
+
+func (r *odsRowReader) Close() error {
+	return r.content.Close()
+}
+
+func odsAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// odsRepeat returns the repeat count of an element, which is 1 by default
+func odsRepeat(element xml.StartElement, name string) int {
+	repeat, err := strconv.Atoi(odsAttr(element, name))
+	if err != nil || repeat < 1 {
+		return 1
+	}
+	return repeat
+}
+
+// NewODS is the initialization method for the OpenDocument spreadsheet reader
+func NewODS(options SpreadsheetOptions) Spreadsheet {
+	return &ods{
+		csv:     newSpreadsheetReader(options),
+		options: options,
+	}
+}
@@ -0,0 +1,124 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestODSFromReader(t *testing.T) {
+	twoTables := `<table:table table:name="Sheet1">` + odsTestRow("sheet") + odsTestRow("first") + `</table:table>` +
+		`<table:table table:name="Other">` + odsTestRow("sheet") + odsTestRow("other") + `</table:table>`
+
+	tests := []struct {
+		name     string
+		tables   string
+		options  SpreadsheetOptions
+		expected []map[string]string
+		err      string
+	}{
+		{
+			name:     "strings",
+			tables:   `<table:table table:name="Sheet1">` + odsTestRow("id", "name") + odsTestRow("1", "a") + odsTestRow("2", "b") + `</table:table>`,
+			expected: []map[string]string{{"id": "1", "name": "a"}, {"id": "2", "name": "b"}},
+		},
+		{
+			name: "value types",
+			tables: `<table:table table:name="Sheet1">` + odsTestRow("float", "percentage", "currency", "boolean", "date", "datetime", "time") + `<table:table-row>` +
+				`<table:table-cell office:value-type="float" office:value="3.5"><text:p>3,50</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="percentage" office:value="0.25"><text:p>25%</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="currency" office:value="9.99"><text:p>$9.99</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="boolean" office:boolean-value="true"><text:p>TRUE</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="date" office:date-value="2023-03-15"><text:p>15/03/2023</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="date" office:date-value="2023-03-15T12:30:00"><text:p>15/03/2023 12:30</text:p></table:table-cell>` +
+				`<table:table-cell office:value-type="time" office:time-value="PT12H30M00S"><text:p>12:30</text:p></table:table-cell>` +
+				`</table:table-row></table:table>`,
+			expected: []map[string]string{{"float": "3.5", "percentage": "0.25", "currency": "9.99", "boolean": "true", "date": "2023-03-15T00:00:00Z", "datetime": "2023-03-15T12:30:00Z", "time": "PT12H30M00S"}},
+		},
+		{
+			name: "text",
+			tables: `<table:table table:name="Sheet1">` + odsTestRow("text") + `<table:table-row><table:table-cell office:value-type="string">` +
+				`<office:annotation><text:p>comment</text:p></office:annotation><text:p>a<text:s text:c="2"/>b<text:tab/>c</text:p><text:p>d<text:line-break/>e</text:p>` +
+				`</table:table-cell></table:table-row></table:table>`,
+			expected: []map[string]string{{"text": "a  b\tc\nd\ne"}},
+		},
+		{
+			name: "repeated cells & rows",
+			tables: `<table:table table:name="Sheet1">` + odsTestRow("a", "b", "c", "d") +
+				`<table:table-row table:number-rows-repeated="2"><table:table-cell office:value-type="float" office:value="1" table:number-columns-repeated="2"/><table:table-cell table:number-columns-repeated="1"/><table:table-cell office:value-type="string"><text:p>x</text:p></table:table-cell><table:table-cell table:number-columns-repeated="1000"/></table:table-row>` +
+				`<table:table-row table:number-rows-repeated="1000"><table:table-cell table:number-columns-repeated="4"/></table:table-row>` +
+				odsTestRow("2") + `</table:table>`,
+			expected: []map[string]string{
+				{"a": "1", "b": "1", "c": "", "d": "x"},
+				{"a": "1", "b": "1", "c": "", "d": "x"},
+				{"a": "2", "b": "", "c": "", "d": ""},
+			},
+		},
+		{
+			name:     "sheet index",
+			tables:   twoTables,
+			options:  SpreadsheetOptions{SheetIndex: 1},
+			expected: []map[string]string{{"sheet": "other"}},
+		},
+		{
+			name:     "sheet name",
+			tables:   twoTables,
+			options:  SpreadsheetOptions{Sheet: "Other"},
+			expected: []map[string]string{{"sheet": "other"}},
+		},
+		{
+			name:    "missing sheet",
+			tables:  twoTables,
+			options: SpreadsheetOptions{Sheet: "Missing"},
+			err:     "Sheet not found in the ods file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := odsTestFile(t, tt.tables)
+			records, err := NewODS(tt.options).FromReader(context.Background(), bytes.NewReader(data))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("FromReader() error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("FromReader() = %v, want %v", records, tt.expected)
+			}
+		})
+	}
+}
+
+func TestODSLines(t *testing.T) {
+	tables := `<table:table table:name="Sheet1">` + odsTestRow("id") + `<table:table-row table:number-rows-repeated="3"><table:table-cell/></table:table-row>` +
+		`<table:table-row table:number-rows-repeated="2">` + odsTestRow("1")[len("<table:table-row>"):] + odsTestRow("2") + `</table:table>`
+
+	data := odsTestFile(t, tables)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := NewODS(SpreadsheetOptions{}).(*ods).openSheet(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	// The empty rows are skipped, but they count in the line numbers
+	expected := []int{1, 5, 6, 7}
+	for _, line := range expected {
+		if _, err = rows.Read(); err != nil {
+			t.Fatal(err)
+		}
+		if rows.Line() != line {
+			t.Errorf("Line() = %d, want %d", rows.Line(), line)
+		}
+	}
+}
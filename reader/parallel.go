@@ -0,0 +1,241 @@
+package reader
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// minChunkSize is the smallest chunk of a file parsed on its own goroutine, below which the parallelism doesn't pay off
+const minChunkSize = 1 << 20
+
+// fileChunk is a range of rows of a file
+// lines is the number of lines before the chunk
+type fileChunk struct {
+	start int64
+	end   int64
+	lines int
+}
+
+// FromPathParallel reads a large local CSV by splitting it on row boundaries into chunks, which are parsed by up to workers goroutines. Default workers is the number of CPUs
+// The records are returned & profiled in the order of the file, & MaxRows stops all the chunks once they read as many rows together. RowFilter & the header transforms are called concurrently, so they must be safe for concurrent use
+// Files which can't be split (ex: compressed or encoded files) & the options which depend on the preceding rows (SkipRows, SkipFooterRows, LazyQuotes, OnProgress, DedupeColumns) fall back to a sequential read
+func (c *csv) FromPathParallel(ctx context.Context, filePath string, workers int) ([]map[string]string, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if c.options.SkipRows > 0 || c.options.SkipFooterRows > 0 || c.options.LazyQuotes || c.options.OnProgress != nil || len(c.options.DedupeColumns) > 0 {
+		return c.FromPath(ctx, filePath)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	seekable, err := c.isSeekable(file)
+	if err != nil {
+		return nil, err
+	}
+	if !seekable || (c.options.MaxBytes > 0 && info.Size() > c.options.MaxBytes) {
+		return c.FromPath(ctx, filePath)
+	}
+
+	// The chunks don't report their own metrics, which are reported once they are merged
+	started := time.Now()
+	records, rows, bytes, err := c.readChunks(ctx, filePath, file, info.Size(), workers)
+	c.reportReadMetrics(filePath, started, rows, bytes, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// readChunks reads the chunks of a file in parallel & returns their records, along with the rows & bytes read
+func (c *csv) readChunks(ctx context.Context, filePath string, file *os.File, size int64, workers int) ([]map[string]string, int, int64, error) {
+	// Several chunks per worker balance the load when the rows aren't evenly sized
+	chunkSize := size / int64(workers*4)
+	if chunkSize < minChunkSize {
+		chunkSize = minChunkSize
+	}
+	chunks, err := c.splitRows(file, size, chunkSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// The header row is read from the first chunk, before the other ones are parsed with its column names
+	// The records are profiled once they are back in the order of the file
+	options := c.options
+	options.Metrics = nil
+	options.Profiler = nil
+	first, err := NewCSV(options).(*csv).newRecordIterator(ctx, filePath, io.NewSectionReader(file, chunks[0].start, chunks[0].end-chunks[0].start))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer first.Close()
+	options.Headers = first.headers
+	chunkCSV := NewCSV(options).(*csv)
+
+	results := make([][]map[string]string, len(chunks))
+	keys := make([][][]string, len(chunks))
+	rows := make([]int, len(chunks))
+	bytes := make([]int64, len(chunks))
+	errs := make([]error, len(chunks))
+	// read is the number of rows read by all the chunks, which is checked against MaxRows as they are read
+	var read atomic.Int64
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, workers)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk fileChunk) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-semaphore }()
+
+			it := first
+			if i > 0 {
+				it, errs[i] = chunkCSV.newRecordIterator(ctx, filePath, io.NewSectionReader(file, chunk.start, chunk.end-chunk.start))
+				if errs[i] != nil {
+					cancel()
+					return
+				}
+				it.lineOffset = chunk.lines
+				defer it.Close()
+			}
+			// The rows & bytes of the failed chunks are read as well
+			defer func() {
+				rows[i] = it.rows
+				if it.input != nil {
+					bytes[i] = it.input.read
+				}
+			}()
+
+			counted := 0
+			for {
+				record, err := it.Next()
+				if err == nil && c.options.MaxRows > 0 {
+					// The rows dropped by the row filter count too
+					if read.Add(int64(it.rows-counted)) > int64(c.options.MaxRows) {
+						err = &LimitError{Option: "MaxRows", Max: int64(c.options.MaxRows)}
+					}
+					counted = it.rows
+				}
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					errs[i] = err
+					cancel()
+					return
+				}
+				results[i] = append(results[i], record)
+				keys[i] = append(keys[i], it.keys)
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	var lines []map[string]string
+	total := 0
+	var totalBytes int64
+	for i := range chunks {
+		total += rows[i]
+		totalBytes += bytes[i]
+		lines = append(lines, results[i]...)
+	}
+
+	// The chunks are cancelled once one of them fails, so the cancellations are only returned when the read itself is cancelled
+	var readErr error
+	for _, err := range errs {
+		if err != nil && (err != context.Canceled || parent.Err() != nil) {
+			readErr = err
+			break
+		}
+	}
+
+	// Like the sequential reads, the records are profiled in the order of the file, up to the first chunk which didn't complete
+	if c.options.Profiler != nil {
+		for i := range chunks {
+			for j, record := range results[i] {
+				c.options.Profiler.add(keys[i][j], record)
+			}
+			if errs[i] != nil {
+				break
+			}
+		}
+	}
+	if readErr != nil {
+		return nil, total, totalBytes, readErr
+	}
+
+	return lines, total, totalBytes, nil
+}
+
+// splitRows splits a file into chunks of about chunkSize bytes ending on row boundaries
+// The quotes are tracked to skip the line breaks of the quoted fields, along with the comment lines whose quotes aren't fields
+func (c *csv) splitRows(file *os.File, size int64, chunkSize int64) ([]fileChunk, error) {
+	var chunks []fileChunk
+	chunk := fileChunk{}
+
+	r := bufio.NewReaderSize(io.NewSectionReader(file, 0, size), 1<<16)
+	var offset int64
+	lines := 0
+	quoted, lineStart, comment := false, true, false
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		offset++
+
+		if lineStart && !quoted && c.options.Comment != 0 && rune(b) == c.options.Comment {
+			comment = true
+		}
+		lineStart = false
+
+		switch {
+		case b == '\n':
+			lines++
+			lineStart = true
+			if quoted {
+				continue
+			}
+			comment = false
+			if offset-chunk.start >= chunkSize && offset < size {
+				chunk.end = offset
+				chunks = append(chunks, chunk)
+				chunk = fileChunk{start: offset, lines: lines}
+			}
+		case b == '"' && !comment:
+			quoted = !quoted
+		}
+	}
+
+	chunk.end = size
+	return append(chunks, chunk), nil
+}
@@ -0,0 +1,268 @@
+package reader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// writeLargeCSV writes a CSV split into several chunks by FromPathParallel, whose id column repeats every ids rows
+func writeLargeCSV(t *testing.T, rows int, ids int) string {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("id,value\n")
+	for i := 0; i < rows; i++ {
+		b.WriteString(strconv.Itoa(i%ids) + ",value of the row " + strconv.Itoa(i) + "\n")
+	}
+	if b.Len() <= 2*minChunkSize {
+		t.Fatalf("CSV of %d bytes isn't split into chunks", b.Len())
+	}
+
+	filePath := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(filePath, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+func TestFromPathParallelDedupe(t *testing.T) {
+	filePath := writeLargeCSV(t, 100000, 10)
+
+	for _, policy := range []DedupePolicy{DedupeKeepFirst, DedupeKeepLast} {
+		t.Run(strconv.Itoa(int(policy)), func(t *testing.T) {
+			c := NewCSV(CSVOptions{DedupeColumns: []string{"id"}, DedupePolicy: policy})
+			sequential, err := c.FromPath(context.Background(), filePath)
+			if err != nil {
+				t.Fatalf("FromPath() error = %v", err)
+			}
+			parallel, err := c.FromPathParallel(context.Background(), filePath, 4)
+			if err != nil {
+				t.Fatalf("FromPathParallel() error = %v", err)
+			}
+
+			if len(sequential) != 10 {
+				t.Errorf("FromPath() returned %d records, want 10", len(sequential))
+			}
+			if !reflect.DeepEqual(parallel, sequential) {
+				t.Errorf("FromPathParallel() returned %d records, FromPath() %d", len(parallel), len(sequential))
+			}
+		})
+	}
+}
+
+// metricsRecorder sums the measures reported to the metrics
+type metricsRecorder struct {
+	mu        sync.Mutex
+	rows      int
+	bytes     int64
+	durations int
+	errors    []error
+}
+
+func (m *metricsRecorder) RowsRead(source string, rows int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rows += rows
+}
+
+func (m *metricsRecorder) BytesRead(source string, bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytes += bytes
+}
+
+func (m *metricsRecorder) Duration(source string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations++
+}
+
+func (m *metricsRecorder) Errors(source string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.errors = append(m.errors, err)
+}
+
+func TestFromPathParallelMetrics(t *testing.T) {
+	filePath := writeLargeCSV(t, 100000, 10)
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		maxRows int
+		errors  int
+	}{
+		{"read", 0, 0},
+		{"limit", 1000, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &metricsRecorder{}
+			_, err := NewCSV(CSVOptions{Metrics: metrics, MaxRows: tt.maxRows}).FromPathParallel(context.Background(), filePath, 4)
+			if (err != nil) != (tt.errors > 0) {
+				t.Fatalf("FromPathParallel() error = %v", err)
+			}
+
+			// The failed reads stop before the end of the file
+			if tt.errors == 0 && metrics.rows != 100000 {
+				t.Errorf("RowsRead() total = %d, want %d", metrics.rows, 100000)
+			}
+			if tt.errors == 0 && metrics.bytes != info.Size() {
+				t.Errorf("BytesRead() total = %d, want %d", metrics.bytes, info.Size())
+			}
+			if metrics.rows == 0 || metrics.bytes == 0 {
+				t.Errorf("RowsRead() total = %d & BytesRead() total = %d, want reads", metrics.rows, metrics.bytes)
+			}
+			if metrics.durations != 1 {
+				t.Errorf("Duration() called %d times, want 1", metrics.durations)
+			}
+			if len(metrics.errors) != tt.errors {
+				t.Errorf("Errors() called with %v, want %d errors", metrics.errors, tt.errors)
+			}
+			// The chunks stop once they read MaxRows rows together, up to a row per worker reading concurrently
+			if tt.maxRows > 0 && metrics.rows > tt.maxRows+4 {
+				t.Errorf("RowsRead() total = %d, want at most %d", metrics.rows, tt.maxRows+4)
+			}
+		})
+	}
+}
+
+func TestFromPathParallelProfiler(t *testing.T) {
+	filePath := writeLargeCSV(t, 100000, 10)
+
+	sequential := NewProfiler()
+	if _, err := NewCSV(CSVOptions{Profiler: sequential}).FromPath(context.Background(), filePath); err != nil {
+		t.Fatal(err)
+	}
+	parallel := NewProfiler()
+	if _, err := NewCSV(CSVOptions{Profiler: parallel}).FromPathParallel(context.Background(), filePath, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	// The estimates of the distinct values depend on the seeds of the profilers
+	columns, expected := parallel.Columns(), sequential.Columns()
+	for _, profiles := range [][]ColumnProfile{columns, expected} {
+		for i := range profiles {
+			if profiles[i].Distinct > exactDistinct {
+				profiles[i].Distinct = exactDistinct + 1
+			}
+		}
+	}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Columns() after FromPathParallel() = %v, want %v", columns, expected)
+	}
+	if len(columns) != 2 || columns[0].Count != 100000 {
+		t.Errorf("Columns() after FromPathParallel() = %v, want 2 columns of 100000 values", columns)
+	}
+}
+
+func TestSplitRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		comment   rune
+		chunkSize int64
+		expected  []fileChunk
+	}{
+		{name: "single chunk", content: "a\nb\nc\n", chunkSize: 100, expected: []fileChunk{{start: 0, end: 6}}},
+		{name: "rows", content: "a\nb\nc\nd\n", chunkSize: 4, expected: []fileChunk{{start: 0, end: 4}, {start: 4, end: 8, lines: 2}}},
+		{name: "no trailing line break", content: "a\nb", chunkSize: 1, expected: []fileChunk{{start: 0, end: 2}, {start: 2, end: 3, lines: 1}}},
+		{name: "CRLF", content: "a\r\nb\r\nc\r\n", chunkSize: 1, expected: []fileChunk{{start: 0, end: 3}, {start: 3, end: 6, lines: 1}, {start: 6, end: 9, lines: 2}}},
+		{
+			name:      "quoted line breaks",
+			content:   "\"a\nb\"\nc\nd\n",
+			chunkSize: 2,
+			expected:  []fileChunk{{start: 0, end: 6}, {start: 6, end: 8, lines: 2}, {start: 8, end: 10, lines: 3}},
+		},
+		{
+			name:      "escaped quotes",
+			content:   "\"a\"\"\nb\"\nc\n",
+			chunkSize: 1,
+			expected:  []fileChunk{{start: 0, end: 8}, {start: 8, end: 10, lines: 2}},
+		},
+		{
+			name:      "quotes in comments",
+			content:   "a\n# \"x\nb\nc\n",
+			comment:   '#',
+			chunkSize: 1,
+			expected:  []fileChunk{{start: 0, end: 2}, {start: 2, end: 7, lines: 1}, {start: 7, end: 9, lines: 2}, {start: 9, end: 11, lines: 3}},
+		},
+		{
+			// Without the Comment option, the quote of the line is the start of a quoted field
+			name:      "quotes without comments",
+			content:   "a\n# \"x\nb\nc\n",
+			chunkSize: 1,
+			expected:  []fileChunk{{start: 0, end: 2}, {start: 2, end: 11, lines: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "file.csv")
+			if err := os.WriteFile(filePath, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			file, err := os.Open(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			c := NewCSV(CSVOptions{Comment: tt.comment}).(*csv)
+			chunks, err := c.splitRows(file, int64(len(tt.content)), tt.chunkSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(chunks, tt.expected) {
+				t.Errorf("splitRows() = %v, want %v", chunks, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFromPathParallelQuotedLineBreaks(t *testing.T) {
+	// The chunks must not end on the quoted line breaks, nor be thrown off by the quotes of the comments
+	var b strings.Builder
+	b.WriteString("id,note\n")
+	for i := 0; i < 120000; i++ {
+		switch i % 3 {
+		case 0:
+			b.WriteString(strconv.Itoa(i) + ",\"multi\nline \"\"note\"\"\"\n")
+		case 1:
+			b.WriteString("# comment with a \" quote\n")
+		default:
+			b.WriteString(strconv.Itoa(i) + ",plain note\n")
+		}
+	}
+	if b.Len() <= 2*minChunkSize {
+		t.Fatalf("CSV of %d bytes isn't split into chunks", b.Len())
+	}
+	filePath := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(filePath, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCSV(CSVOptions{Comment: '#'})
+	sequential, err := c.FromPath(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("FromPath() error = %v", err)
+	}
+	parallel, err := c.FromPathParallel(context.Background(), filePath, 4)
+	if err != nil {
+		t.Fatalf("FromPathParallel() error = %v", err)
+	}
+	if !reflect.DeepEqual(parallel, sequential) {
+		t.Errorf("FromPathParallel() returned %d records, FromPath() %d", len(parallel), len(sequential))
+	}
+}
@@ -0,0 +1,366 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parquet physical types
+const (
+	parquetTypeBoolean           = 0
+	parquetTypeInt32             = 1
+	parquetTypeInt64             = 2
+	parquetTypeInt96             = 3
+	parquetTypeFloat             = 4
+	parquetTypeDouble            = 5
+	parquetTypeByteArray         = 6
+	parquetTypeFixedLenByteArray = 7
+)
+
+// Parquet repetition types
+const (
+	parquetRequired = 0
+	parquetOptional = 1
+	parquetRepeated = 2
+)
+
+var parquetMagic = []byte("PAR1")
+
+// ParquetOptions consists of the Parquet reader options available
+// CSVOptions apply to the rows of the file, except for the ones specific to text (ex: Delimiter, Encoding). Headers are always the column names
+// Columns are the names of the columns to read, in their order in the records. When empty, all the columns are read
+type ParquetOptions struct {
+	CSVOptions
+	Columns []string
+}
+
+// Parquet is a lightweight interface for reading Parquet files
+// The columns of nested groups are named after their path joined by "." (ex: address.city). Repeated columns are not supported
+// Values are formatted like in a CSV: dates & timestamps as RFC3339, decimals with their scale & nulls as empty strings
+// The pages may be uncompressed or compressed with the Snappy, gzip or zstd codecs, up to 256 MiB uncompressed
+type Parquet interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, filePath string) (RecordIterator, error)
+}
+
+type parquet struct {
+	options ParquetOptions
+}
+
+// FromPath reads a Parquet file from a file path
+func (p *parquet) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	it, err := p.Open(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads a Parquet file from an io.Reader
+// The file is buffered in memory, since the metadata of Parquet files is at their end
+func (p *parquet) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	data, err := readLimited(r, p.options.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := p.open(ctx, "", bytes.NewReader(data), int64(len(data)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of a Parquet file
+// The row groups of the file are read one at a time
+func (p *parquet) Open(ctx context.Context, filePath string) (RecordIterator, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return p.open(ctx, filePath, file, info.Size(), file)
+}
+
+func (p *parquet) open(ctx context.Context, source string, file io.ReaderAt, size int64, closer io.Closer) (*recordIterator, error) {
+	closers := []io.Closer{}
+	if closer != nil {
+		closers = append(closers, closer)
+	}
+
+	rows, err := p.openFile(file, size)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	options := p.options.CSVOptions
+	options.Headers = make([]string, len(rows.columns))
+	for i, column := range rows.columns {
+		options.Headers[i] = column.name
+	}
+
+	return NewCSV(options).(*csv).newRowIterator(ctx, source, rows, append(closers, rows))
+}
+
+// openFile reads the metadata at the end of the file & selects the columns to read
+func (p *parquet) openFile(file io.ReaderAt, size int64) (*parquetRowReader, error) {
+	if size < 12 {
+		return nil, errors.New("Invalid Parquet file: file is too small")
+	}
+
+	footer := make([]byte, 8)
+	if _, err := file.ReadAt(footer, size-8); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(footer[4:], parquetMagic) {
+		return nil, errors.New("Invalid Parquet file: missing magic number")
+	}
+
+	metadataSize := int64(binary.LittleEndian.Uint32(footer))
+	if metadataSize > size-12 {
+		return nil, errors.New("Invalid Parquet file: invalid metadata size")
+	}
+	metadataData := make([]byte, metadataSize)
+	if _, err := file.ReadAt(metadataData, size-8-metadataSize); err != nil {
+		return nil, err
+	}
+
+	decoder := &thriftDecoder{data: metadataData}
+	metadata, err := decoder.readStruct()
+	if err != nil {
+		return nil, errors.New("Invalid Parquet file: " + err.Error())
+	}
+
+	leaves, err := parquetLeaves(metadata.list(2))
+	if err != nil {
+		return nil, err
+	}
+
+	columns := leaves
+	if len(p.options.Columns) > 0 {
+		byName := make(map[string]*parquetColumn, len(leaves))
+		for _, leaf := range leaves {
+			byName[leaf.name] = leaf
+		}
+
+		columns = make([]*parquetColumn, len(p.options.Columns))
+		for i, name := range p.options.Columns {
+			column, ok := byName[name]
+			if !ok {
+				return nil, errors.New("Column not found in the Parquet file: " + name)
+			}
+			columns[i] = column
+		}
+	}
+	for _, column := range columns {
+		if column.repeated {
+			return nil, errors.New("Unsupported repeated column in the Parquet file: " + column.name)
+		}
+	}
+
+	rows := &parquetRowReader{
+		file:    file,
+		size:    size,
+		columns: columns,
+	}
+	for _, rowGroup := range metadata.list(4) {
+		if fields, ok := rowGroup.(thriftFields); ok {
+			rows.rowGroups = append(rows.rowGroups, fields)
+		}
+	}
+
+	return rows, nil
+}
+
+// parquetColumn is a leaf column of the schema of a Parquet file
+type parquetColumn struct {
+	name string
+	// index is the position of the column among the leaves, which is also its position in the row groups
+	index         int
+	physicalType  int64
+	typeLength    int
+	maxDefinition int
+	repeated      bool
+	logicalType   thriftFields
+	convertedType int64
+	scale         int
+}
+
+// parquetLeaves walks the flattened schema tree & returns its leaf columns
+func parquetLeaves(schema []interface{}) ([]*parquetColumn, error) {
+	elements := make([]thriftFields, len(schema))
+	for i, element := range schema {
+		fields, ok := element.(thriftFields)
+		if !ok {
+			return nil, errors.New("Invalid Parquet file: invalid schema")
+		}
+		elements[i] = fields
+	}
+	if len(elements) == 0 {
+		return nil, errors.New("Invalid Parquet file: empty schema")
+	}
+
+	var leaves []*parquetColumn
+	var walk func(index int, path []string, maxDefinition int, repeated bool) (int, error)
+	walk = func(index int, path []string, maxDefinition int, repeated bool) (int, error) {
+		if index >= len(elements) {
+			return 0, errors.New("Invalid Parquet file: invalid schema")
+		}
+		element := elements[index]
+
+		switch element.int(3) {
+		case parquetOptional:
+			maxDefinition++
+		case parquetRepeated:
+			maxDefinition++
+			repeated = true
+		}
+		path = append(path, element.string(4))
+
+		children := int(element.int(5))
+		if children == 0 && element.has(1) {
+			column := &parquetColumn{
+				name:          strings.Join(path, "."),
+				index:         len(leaves),
+				physicalType:  element.int(1),
+				typeLength:    int(element.int(2)),
+				maxDefinition: maxDefinition,
+				repeated:      repeated,
+				logicalType:   element.fields(10),
+				convertedType: -1,
+				scale:         int(element.int(7)),
+			}
+			if element.has(6) {
+				column.convertedType = element.int(6)
+			}
+			leaves = append(leaves, column)
+			return index + 1, nil
+		}
+
+		next := index + 1
+		for i := 0; i < children; i++ {
+			var err error
+			if next, err = walk(next, path[:len(path):len(path)], maxDefinition, repeated); err != nil {
+				return 0, err
+			}
+		}
+		return next, nil
+	}
+
+	// The root element is the message itself, which isn't part of the column names
+	root := elements[0]
+	next := 1
+	for i := 0; i < int(root.int(5)); i++ {
+		var err error
+		if next, err = walk(next, nil, 0, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return leaves, nil
+}
+
+// parquetRowReader decodes the columns of a row group at a time & returns their rows
+type parquetRowReader struct {
+	file      io.ReaderAt
+	size      int64
+	columns   []*parquetColumn
+	rowGroups []thriftFields
+	zstd      *zstd.Decoder
+
+	// values are the values of the columns of the current row group
+	values   [][]string
+	rowGroup int
+	row      int
+	rowCount int
+	line     int
+}
+
+func (r *parquetRowReader) Read() ([]string, error) {
+	for r.row >= r.rowCount {
+		if r.rowGroup >= len(r.rowGroups) {
+			return nil, io.EOF
+		}
+		if err := r.readRowGroup(r.rowGroups[r.rowGroup]); err != nil {
+			return nil, err
+		}
+		r.rowGroup++
+	}
+
+	fields := make([]string, len(r.columns))
+	for i := range r.columns {
+		fields[i] = r.values[i][r.row]
+	}
+	r.row++
+	r.line++
+
+	return fields, nil
+}
+
+func (r *parquetRowReader) readRowGroup(rowGroup thriftFields) error {
+	chunks := rowGroup.list(1)
+	rowCount := rowGroup.int(3)
+	if rowCount < 0 {
+		return errors.New("Invalid Parquet file: invalid row count")
+	}
+	// The row count of a file without columns isn't backed by any data
+	if len(r.columns) == 0 {
+		rowCount = 0
+	}
+
+	values := make([][]string, len(r.columns))
+	for i, column := range r.columns {
+		if column.index >= len(chunks) {
+			return errors.New("Invalid Parquet file: missing column chunk")
+		}
+		chunk, ok := chunks[column.index].(thriftFields)
+		if !ok {
+			return errors.New("Invalid Parquet file: invalid column chunk")
+		}
+
+		var err error
+		if values[i], err = r.readColumnChunk(column, chunk, int(rowCount)); err != nil {
+			return err
+		}
+	}
+
+	r.values = values
+	r.row = 0
+	r.rowCount = int(rowCount)
+	return nil
+}
+
+func (r *parquetRowReader) Line() int {
+	return r.line
+}
+
+func (r *parquetRowReader) Close() error {
+	if r.zstd != nil {
+		r.zstd.Close()
+	}
+	return nil
+}
+
+// NewParquet is the initialization method for the Parquet reader
+func NewParquet(options ParquetOptions) Parquet {
+	return &parquet{
+		options: options,
+	}
+}
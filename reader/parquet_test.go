@@ -0,0 +1,567 @@
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// thriftEncoder encodes the Thrift compact protocol, to write the metadata of the Parquet fixtures
+type thriftEncoder struct {
+	bytes.Buffer
+}
+
+// thriftField is a field of an encoded struct. The values are int64, bool, string, []thriftField (structs) or []interface{} (lists)
+type thriftField struct {
+	id    int16
+	value interface{}
+}
+
+func (e *thriftEncoder) writeStruct(fields []thriftField) {
+	var id int16
+	for _, field := range fields {
+		fieldType := thriftValueType(field.value)
+		if b, ok := field.value.(bool); ok && !b {
+			fieldType = thriftFalse
+		}
+		if delta := field.id - id; delta > 0 && delta <= 15 {
+			e.WriteByte(byte(delta)<<4 | fieldType)
+		} else {
+			e.WriteByte(fieldType)
+			e.writeVarint(int64(field.id))
+		}
+		id = field.id
+		if _, ok := field.value.(bool); !ok {
+			e.writeValue(field.value)
+		}
+	}
+	e.WriteByte(thriftStop)
+}
+
+func (e *thriftEncoder) writeValue(value interface{}) {
+	switch v := value.(type) {
+	case int64:
+		e.writeVarint(v)
+	case string:
+		e.writeUvarint(uint64(len(v)))
+		e.WriteString(v)
+	case []thriftField:
+		e.writeStruct(v)
+	case []interface{}:
+		elementType := byte(thriftStruct)
+		if len(v) > 0 {
+			elementType = thriftValueType(v[0])
+		}
+		if len(v) < 15 {
+			e.WriteByte(byte(len(v))<<4 | elementType)
+		} else {
+			e.WriteByte(0xf0 | elementType)
+			e.writeUvarint(uint64(len(v)))
+		}
+		for _, element := range v {
+			e.writeValue(element)
+		}
+	}
+}
+
+func (e *thriftEncoder) writeVarint(v int64) {
+	e.writeUvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (e *thriftEncoder) writeUvarint(v uint64) {
+	e.Write(binary.AppendUvarint(nil, v))
+}
+
+func thriftValueType(value interface{}) byte {
+	switch value.(type) {
+	case bool:
+		return thriftTrue
+	case int64:
+		return thriftI64
+	case string:
+		return thriftBinary
+	case []interface{}:
+		return thriftList
+	}
+	return thriftStruct
+}
+
+func thriftBytes(fields []thriftField) []byte {
+	var e thriftEncoder
+	e.writeStruct(fields)
+	return e.Bytes()
+}
+
+// parquetTestColumn is a column of a Parquet fixture, with nil values for the nulls of the optional columns
+type parquetTestColumn struct {
+	name         string
+	physicalType int64
+	optional     bool
+	dictionary   bool
+	logicalType  []thriftField
+	values       []interface{}
+}
+
+// parquetTestFile writes a Parquet file of a row group, with a page per column compressed with codec
+// The values of the dictionary columns are indexes into a dictionary page, or else plain encoded. v2 writes the second version of the data pages
+func parquetTestFile(t *testing.T, codec int64, v2 bool, columns ...parquetTestColumn) []byte {
+	t.Helper()
+
+	file := bytes.NewBuffer(append([]byte(nil), parquetMagic...))
+	schema := []interface{}{[]thriftField{{4, "schema"}, {5, int64(len(columns))}}}
+	var chunks []interface{}
+	for _, column := range columns {
+		repetition := int64(parquetRequired)
+		if column.optional {
+			repetition = parquetOptional
+		}
+		element := []thriftField{{1, column.physicalType}, {3, repetition}, {4, column.name}}
+		if column.logicalType != nil {
+			element = append(element, thriftField{10, column.logicalType})
+		}
+		schema = append(schema, element)
+
+		// The definition levels & the values which aren't null
+		var levels []int
+		var present []interface{}
+		for _, value := range column.values {
+			if value == nil {
+				levels = append(levels, 0)
+				continue
+			}
+			levels = append(levels, 1)
+			present = append(present, value)
+		}
+
+		start := int64(file.Len())
+		metadata := []thriftField{{1, column.physicalType}, {3, []interface{}{column.name}}, {4, codec}, {5, int64(len(column.values))}}
+
+		encoding := int64(parquetPlain)
+		values := parquetPlainValues(t, column.physicalType, present)
+		if column.dictionary {
+			// The dictionary holds the distinct values, in the order of their first occurrence
+			var dictionary []interface{}
+			var indexes []int
+			for _, value := range present {
+				index := len(dictionary)
+				for i, entry := range dictionary {
+					if reflect.DeepEqual(entry, value) {
+						index = i
+					}
+				}
+				if index == len(dictionary) {
+					dictionary = append(dictionary, value)
+				}
+				indexes = append(indexes, index)
+			}
+			page := parquetPlainValues(t, column.physicalType, dictionary)
+			compressed := parquetCompress(t, codec, page)
+			file.Write(thriftBytes([]thriftField{
+				{1, int64(parquetDictionaryPage)}, {2, int64(len(page))}, {3, int64(len(compressed))},
+				{7, []thriftField{{1, int64(len(dictionary))}, {2, int64(parquetPlain)}}},
+			}))
+			file.Write(compressed)
+			metadata = append(metadata, thriftField{11, start})
+
+			encoding = parquetRLEDictionary
+			bitWidth := parquetBitWidth(len(dictionary) - 1)
+			values = append([]byte{byte(bitWidth)}, parquetBitPacked(indexes, bitWidth)...)
+		}
+
+		dataPageOffset := int64(file.Len())
+		if v2 {
+			var definition []byte
+			if column.optional {
+				definition = parquetBitPacked(levels, 1)
+			}
+			compressed := parquetCompress(t, codec, values)
+			file.Write(thriftBytes([]thriftField{
+				{1, int64(parquetDataPageV2)}, {2, int64(len(definition) + len(values))}, {3, int64(len(definition) + len(compressed))},
+				{8, []thriftField{{1, int64(len(levels))}, {2, int64(len(levels) - len(present))}, {3, int64(len(levels))}, {4, encoding}, {5, int64(len(definition))}, {6, int64(0)}}},
+			}))
+			file.Write(definition)
+			file.Write(compressed)
+		} else {
+			page := values
+			if column.optional {
+				definition := parquetBitPacked(levels, 1)
+				page = binary.LittleEndian.AppendUint32(nil, uint32(len(definition)))
+				page = append(append(page, definition...), values...)
+			}
+			compressed := parquetCompress(t, codec, page)
+			file.Write(thriftBytes([]thriftField{
+				{1, int64(parquetDataPage)}, {2, int64(len(page))}, {3, int64(len(compressed))},
+				{5, []thriftField{{1, int64(len(levels))}, {2, encoding}, {3, int64(parquetRLE)}, {4, int64(parquetRLE)}}},
+			}))
+			file.Write(compressed)
+		}
+
+		metadata = append(metadata, thriftField{7, int64(file.Len()) - start}, thriftField{9, dataPageOffset})
+		chunks = append(chunks, []thriftField{{2, start}, {3, metadata}})
+	}
+
+	rowCount := int64(len(columns[0].values))
+	footer := thriftBytes([]thriftField{
+		{1, int64(1)},
+		{2, schema},
+		{3, rowCount},
+		{4, []interface{}{[]thriftField{{1, chunks}, {2, int64(file.Len())}, {3, rowCount}}}},
+		{6, "uniparse test"},
+	})
+	file.Write(footer)
+	file.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(footer))))
+	file.Write(parquetMagic)
+	return file.Bytes()
+}
+
+// parquetPlainValues encodes values with the plain encoding of a physical type
+func parquetPlainValues(t *testing.T, physicalType int64, values []interface{}) []byte {
+	t.Helper()
+
+	var data []byte
+	for i, value := range values {
+		switch v := value.(type) {
+		case bool:
+			if i%8 == 0 {
+				data = append(data, 0)
+			}
+			if v {
+				data[i/8] |= 1 << (i % 8)
+			}
+		case int32:
+			data = binary.LittleEndian.AppendUint32(data, uint32(v))
+		case int64:
+			data = binary.LittleEndian.AppendUint64(data, uint64(v))
+		case float64:
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		case string:
+			data = binary.LittleEndian.AppendUint32(data, uint32(len(v)))
+			data = append(data, v...)
+		default:
+			t.Fatalf("unsupported value of physical type %d: %v", physicalType, value)
+		}
+	}
+	return data
+}
+
+// parquetBitPacked encodes values with the bit-packed runs of the RLE / bit-packing hybrid encoding
+func parquetBitPacked(values []int, bitWidth int) []byte {
+	groups := (len(values) + 7) / 8
+	data := binary.AppendUvarint(nil, uint64(groups)<<1|1)
+	packed := make([]byte, groups*bitWidth)
+	for i, value := range values {
+		for b := 0; b < bitWidth; b++ {
+			if value>>b&1 == 1 {
+				bit := i*bitWidth + b
+				packed[bit/8] |= 1 << (bit % 8)
+			}
+		}
+	}
+	return append(data, packed...)
+}
+
+func parquetCompress(t *testing.T, codec int64, data []byte) []byte {
+	t.Helper()
+
+	switch codec {
+	case parquetSnappy:
+		return s2.EncodeSnappy(nil, data)
+	case parquetGzip:
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		writer.Write(data)
+		writer.Close()
+		return compressed.Bytes()
+	case parquetZstd:
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer encoder.Close()
+		return encoder.EncodeAll(data, nil)
+	}
+	return data
+}
+
+func parquetTestColumns(dictionary bool) []parquetTestColumn {
+	stringType := []thriftField{{1, []thriftField{}}}
+	decimalType := []thriftField{{5, []thriftField{{1, int64(2)}, {2, int64(9)}}}}
+	return []parquetTestColumn{
+		{name: "id", physicalType: parquetTypeInt64, values: []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5), int64(6), int64(7), int64(8), int64(9)}},
+		{name: "city", physicalType: parquetTypeByteArray, optional: true, dictionary: dictionary, logicalType: stringType, values: []interface{}{"Paris", "Lyon", nil, "Paris", "Paris", nil, "Nice", "Lyon", "Paris"}},
+		{name: "price", physicalType: parquetTypeInt32, optional: true, logicalType: decimalType, values: []interface{}{int32(1250), nil, int32(-5), int32(0), nil, nil, int32(99), int32(100), int32(7)}},
+		{name: "score", physicalType: parquetTypeDouble, dictionary: dictionary, values: []interface{}{1.5, 2.0, 1.5, 0.25, 1.5, 2.0, 3.0, 0.25, 1e-7}},
+		{name: "active", physicalType: parquetTypeBoolean, values: []interface{}{true, false, true, true, false, false, true, false, true}},
+	}
+}
+
+func TestParquetFromReader(t *testing.T) {
+	expected := []map[string]string{
+		{"id": "1", "city": "Paris", "price": "12.50", "score": "1.5", "active": "true"},
+		{"id": "2", "city": "Lyon", "price": "", "score": "2", "active": "false"},
+		{"id": "3", "city": "", "price": "-0.05", "score": "1.5", "active": "true"},
+		{"id": "4", "city": "Paris", "price": "0.00", "score": "0.25", "active": "true"},
+		{"id": "5", "city": "Paris", "price": "", "score": "1.5", "active": "false"},
+		{"id": "6", "city": "", "price": "", "score": "2", "active": "false"},
+		{"id": "7", "city": "Nice", "price": "0.99", "score": "3", "active": "true"},
+		{"id": "8", "city": "Lyon", "price": "1.00", "score": "0.25", "active": "false"},
+		{"id": "9", "city": "Paris", "price": "0.07", "score": "1e-07", "active": "true"},
+	}
+
+	codecs := []struct {
+		name  string
+		codec int64
+	}{
+		{"uncompressed", parquetUncompressed},
+		{"snappy", parquetSnappy},
+		{"gzip", parquetGzip},
+		{"zstd", parquetZstd},
+	}
+	for _, codec := range codecs {
+		for _, dictionary := range []bool{false, true} {
+			for _, v2 := range []bool{false, true} {
+				name := codec.name
+				if dictionary {
+					name += " dictionary"
+				}
+				if v2 {
+					name += " v2"
+				}
+				t.Run(name, func(t *testing.T) {
+					file := parquetTestFile(t, codec.codec, v2, parquetTestColumns(dictionary)...)
+
+					records, err := NewParquet(ParquetOptions{}).FromReader(context.Background(), bytes.NewReader(file))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !reflect.DeepEqual(records, expected) {
+						t.Errorf("FromReader() = %v, want %v", records, expected)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestParquetColumns(t *testing.T) {
+	file := parquetTestFile(t, parquetUncompressed, false, parquetTestColumns(true)...)
+
+	rows, err := NewParquet(ParquetOptions{Columns: []string{"city", "id"}}).(*parquet).openFile(bytes.NewReader(file), int64(len(file)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields, err := rows.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"Paris", "1"}; !reflect.DeepEqual(fields, expected) {
+		t.Errorf("Read() = %v, want %v", fields, expected)
+	}
+
+	if _, err = NewParquet(ParquetOptions{Columns: []string{"missing"}}).FromReader(context.Background(), bytes.NewReader(file)); err == nil {
+		t.Error("FromReader() error = nil, want an error")
+	}
+}
+
+func TestParquetInvalid(t *testing.T) {
+	valid := parquetTestFile(t, parquetZstd, false, parquetTestColumns(true)...)
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{"empty", nil},
+		{"missing magic number", bytes.TrimSuffix(valid, parquetMagic)},
+		{"truncated", valid[:len(valid)/2]},
+		{"metadata size", append(append(valid[:len(valid)-8:len(valid)-8], 0xff, 0xff, 0xff, 0x7f), parquetMagic...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParquet(ParquetOptions{}).FromReader(context.Background(), bytes.NewReader(tt.file))
+			if err == nil || !strings.HasPrefix(err.Error(), "Invalid Parquet file") {
+				t.Errorf("FromReader() error = %v, want an invalid Parquet file error", err)
+			}
+		})
+	}
+}
+
+func TestParquetDecompressBounds(t *testing.T) {
+	page := make([]byte, 1<<20)
+
+	tests := []struct {
+		name  string
+		codec int64
+		size  int
+	}{
+		{"snappy beyond the page size", parquetSnappy, 1000},
+		{"gzip beyond the page size", parquetGzip, 1000},
+		{"zstd beyond the page size", parquetZstd, 1000},
+		{"maximum page size", parquetZstd, parquetMaxPageSize + 1},
+		{"negative page size", parquetGzip, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := &parquetRowReader{}
+			defer rows.Close()
+
+			if _, err := rows.decompress(tt.codec, parquetCompress(t, tt.codec, page), tt.size); err != errInvalidParquetPage {
+				t.Errorf("decompress() error = %v, want %v", err, errInvalidParquetPage)
+			}
+		})
+	}
+}
+
+func TestParquetCountBounds(t *testing.T) {
+	// The counts claim far more values than the data holds, which mustn't be allocated upfront
+	const count = 1 << 40
+
+	if _, err := parquetHybrid(parquetBitPacked([]int{1, 0, 1}, 1), 1, count); err != errInvalidParquetPage {
+		t.Errorf("parquetHybrid() error = %v, want %v", err, errInvalidParquetPage)
+	}
+
+	var delta thriftEncoder
+	for _, v := range []uint64{128, 4, count} {
+		delta.writeUvarint(v)
+	}
+	delta.writeVarint(1)
+	if _, _, err := parquetDeltaBinary(delta.Bytes(), count); err != errInvalidParquetPage {
+		t.Errorf("parquetDeltaBinary() error = %v, want %v", err, errInvalidParquetPage)
+	}
+
+	var list thriftEncoder
+	list.WriteByte(0xf0 | thriftI64)
+	list.writeUvarint(count)
+	decoder := &thriftDecoder{data: list.Bytes()}
+	if _, err := decoder.readValue(thriftList, 0); err != errInvalidThrift {
+		t.Errorf("readValue() error = %v, want %v", err, errInvalidThrift)
+	}
+
+	var binary thriftEncoder
+	binary.writeUvarint(count)
+	decoder = &thriftDecoder{data: binary.Bytes()}
+	if _, err := decoder.readValue(thriftBinary, 0); err != errInvalidThrift {
+		t.Errorf("readValue() error = %v, want %v", err, errInvalidThrift)
+	}
+}
+
+// parquetRawFile writes a Parquet file of a required int64 column of rowCount rows, in a chunk of the given bytes
+// The chunk is described by its metadata, which may claim other sizes & offsets than the ones of the bytes
+func parquetRawFile(chunk []byte, rowCount int64, metadata []thriftField) []byte {
+	file := append(append([]byte(nil), parquetMagic...), chunk...)
+	footer := thriftBytes([]thriftField{
+		{1, int64(1)},
+		{2, []interface{}{
+			[]thriftField{{4, "schema"}, {5, int64(1)}},
+			[]thriftField{{1, int64(parquetTypeInt64)}, {3, int64(parquetRequired)}, {4, "id"}},
+		}},
+		{3, rowCount},
+		{4, []interface{}{[]thriftField{{1, []interface{}{[]thriftField{{2, int64(4)}, {3, metadata}}}}, {2, int64(len(chunk))}, {3, rowCount}}}},
+	})
+	file = append(file, footer...)
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(footer)))
+	return append(file, parquetMagic...)
+}
+
+func TestParquetMalformed(t *testing.T) {
+	values := binary.LittleEndian.AppendUint64(nil, 1)
+	page := func(uncompressed, compressed, count int64) []byte {
+		return thriftBytes([]thriftField{
+			{1, int64(parquetDataPage)}, {2, uncompressed}, {3, compressed},
+			{5, []thriftField{{1, count}, {2, int64(parquetPlain)}, {3, int64(parquetRLE)}, {4, int64(parquetRLE)}}},
+		})
+	}
+	chunkMetadata := func(chunk []byte) []thriftField {
+		return []thriftField{{1, int64(parquetTypeInt64)}, {3, []interface{}{"id"}}, {4, int64(parquetUncompressed)}, {5, int64(1)}, {7, int64(len(chunk))}, {9, int64(4)}}
+	}
+	valid := append(page(8, 8, 1), values...)
+
+	// The name of the column claims more bytes than the metadata holds
+	var badName thriftEncoder
+	badName.writeStruct([]thriftField{{1, int64(1)}})
+	badName.Truncate(badName.Len() - 1)
+	badName.WriteByte(0x19)
+	badName.WriteByte(0x1c)
+	badName.WriteByte(0x48)
+	badName.writeUvarint(1 << 30)
+	badNameFile := append(append([]byte(nil), parquetMagic...), badName.Bytes()...)
+	badNameFile = binary.LittleEndian.AppendUint32(badNameFile, uint32(badName.Len()))
+	badNameFile = append(badNameFile, parquetMagic...)
+
+	// The rows of a file without columns
+	noColumns := thriftBytes([]thriftField{
+		{1, int64(1)},
+		{2, []interface{}{[]thriftField{{4, "schema"}, {5, int64(0)}}}},
+		{3, int64(1 << 40)},
+		{4, []interface{}{[]thriftField{{1, []interface{}{}}, {2, int64(0)}, {3, int64(1 << 40)}}}},
+	})
+	noColumnsFile := append(append(append([]byte(nil), parquetMagic...), noColumns...), binary.LittleEndian.AppendUint32(nil, uint32(len(noColumns)))...)
+	noColumnsFile = append(noColumnsFile, parquetMagic...)
+
+	tests := []struct {
+		name     string
+		file     []byte
+		expected string
+	}{
+		{"valid", parquetRawFile(valid, 1, chunkMetadata(valid)), ""},
+		{"thrift binary length", badNameFile, "Invalid Parquet file: Invalid Thrift data"},
+		{"truncated page header", parquetRawFile(valid[:5], 1, chunkMetadata(valid[:5])), "Invalid Parquet file: Invalid Thrift data"},
+		{"chunk beyond the file", parquetRawFile(valid, 1, append(chunkMetadata(valid), thriftField{9, int64(1 << 20)})), "Invalid Parquet file: invalid column chunk"},
+		{"chunk size beyond the file", parquetRawFile(valid, 1, append(chunkMetadata(valid)[:4], thriftField{7, int64(1 << 40)}, thriftField{9, int64(4)})), "Invalid Parquet file: invalid column chunk"},
+		{"page beyond the chunk", parquetRawFile(append(page(8, 1<<20, 1), values...), 1, chunkMetadata(append(page(8, 1<<20, 1), values...))), "Invalid Parquet file: invalid page"},
+		{"negative page size", parquetRawFile(append(page(8, -1, 1), values...), 1, chunkMetadata(append(page(8, -1, 1), values...))), "Invalid Parquet file: invalid page"},
+		{"oversized page", parquetRawFile(append(page(parquetMaxPageSize+1, 8, 1), values...), 1, chunkMetadata(append(page(parquetMaxPageSize+1, 8, 1), values...))), "Invalid Parquet file: invalid page"},
+		{"page count beyond the rows", parquetRawFile(append(page(8, 8, 2), values...), 1, chunkMetadata(append(page(8, 8, 2), values...))), "Invalid Parquet file: invalid page"},
+		{"missing values", parquetRawFile(valid, 2, chunkMetadata(valid)), "Invalid Parquet file: missing values in column id"},
+		{"negative row count", parquetRawFile(valid, -1, chunkMetadata(valid)), "Invalid Parquet file: invalid row count"},
+		{"rows without columns", noColumnsFile, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewParquet(ParquetOptions{}).FromReader(context.Background(), bytes.NewReader(tt.file))
+			if tt.expected == "" {
+				if err != nil {
+					t.Errorf("FromReader() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("FromReader() error = %v, want %s", err, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParquetCorrupted reads copies of a file with each of its bytes overwritten & truncated at each byte, which must fail or succeed without panicking
+func TestParquetCorrupted(t *testing.T) {
+	valid := parquetTestFile(t, parquetSnappy, false, parquetTestColumns(true)...)
+
+	read := func(name string, file []byte) {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("FromReader() of the file %s panicked: %v", name, r)
+			}
+		}()
+		NewParquet(ParquetOptions{}).FromReader(context.Background(), bytes.NewReader(file))
+	}
+
+	for i := range valid {
+		for _, b := range []byte{0x00, 0x7f, 0xff} {
+			corrupted := append([]byte(nil), valid...)
+			corrupted[i] = b
+			read("with byte "+strconv.Itoa(i)+" set to "+strconv.Itoa(int(b)), corrupted)
+		}
+		read("truncated at "+strconv.Itoa(i), valid[:i])
+	}
+}
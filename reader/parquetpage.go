@@ -0,0 +1,747 @@
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Parquet page types
+const (
+	parquetDataPage       = 0
+	parquetDictionaryPage = 2
+	parquetDataPageV2     = 3
+)
+
+// Parquet value encodings
+const (
+	parquetPlain                = 0
+	parquetPlainDictionary      = 2
+	parquetRLE                  = 3
+	parquetDeltaBinaryPacked    = 5
+	parquetDeltaLengthByteArray = 6
+	parquetDeltaByteArray       = 7
+	parquetRLEDictionary        = 8
+	parquetByteStreamSplit      = 9
+)
+
+// Parquet compression codecs
+const (
+	parquetUncompressed = 0
+	parquetSnappy       = 1
+	parquetGzip         = 2
+	parquetZstd         = 6
+)
+
+// parquetMaxPageSize is the maximum uncompressed size of a page, which bounds the buffers allocated to decompress it
+const parquetMaxPageSize = 256 << 20
+
+// parquetJulianEpoch is the Julian day of the Unix epoch, which is used by the legacy INT96 timestamps
+const parquetJulianEpoch = 2440588
+
+var errInvalidParquetPage = errors.New("Invalid Parquet file: invalid page")
+
+// parquetInt96 is a legacy timestamp: nanoseconds of the day followed by a Julian day
+type parquetInt96 [12]byte
+
+// readColumnChunk decodes the pages of a column chunk into the formatted values of the rows
+func (r *parquetRowReader) readColumnChunk(column *parquetColumn, chunk thriftFields, rowCount int) ([]string, error) {
+	if chunk.string(1) != "" {
+		return nil, errors.New("Unsupported external column chunk in the Parquet file: " + column.name)
+	}
+	metadata := chunk.fields(3)
+	if metadata == nil {
+		return nil, errors.New("Invalid Parquet file: missing column metadata")
+	}
+
+	// The dictionary page, if any, precedes the data pages
+	start := metadata.int(9)
+	if dictionaryOffset := metadata.int(11); dictionaryOffset > 0 && dictionaryOffset < start {
+		start = dictionaryOffset
+	}
+	length := metadata.int(7)
+	if start < int64(len(parquetMagic)) || length < 0 || length > r.size || start > r.size-length {
+		return nil, errors.New("Invalid Parquet file: invalid column chunk")
+	}
+
+	data := make([]byte, length)
+	if _, err := r.file.ReadAt(data, start); err != nil {
+		return nil, err
+	}
+
+	codec := metadata.int(4)
+	// The counts of the file are only trusted as far as the data can hold them
+	values := make([]string, 0, min(rowCount, len(data)))
+	var dictionary []string
+	decoder := &thriftDecoder{data: data}
+	for len(values) < rowCount && decoder.pos < len(data) {
+		header, err := decoder.readStruct()
+		if err != nil {
+			return nil, errors.New("Invalid Parquet file: " + err.Error())
+		}
+
+		compressedSize := int(header.int(3))
+		uncompressedSize := int(header.int(2))
+		if compressedSize < 0 || compressedSize > len(data)-decoder.pos {
+			return nil, errInvalidParquetPage
+		}
+		page := data[decoder.pos : decoder.pos+compressedSize]
+		decoder.pos += compressedSize
+
+		switch header.int(1) {
+		case parquetDictionaryPage:
+			if page, err = r.decompress(codec, page, uncompressedSize); err != nil {
+				return nil, err
+			}
+			raw, err := column.decodePlain(page, int(header.fields(7).int(1)))
+			if err != nil {
+				return nil, err
+			}
+			dictionary = column.formatAll(raw)
+
+		case parquetDataPage:
+			pageHeader := header.fields(5)
+			count := int(pageHeader.int(1))
+			if count < 0 || count > rowCount-len(values) {
+				return nil, errInvalidParquetPage
+			}
+			if page, err = r.decompress(codec, page, uncompressedSize); err != nil {
+				return nil, err
+			}
+
+			// The definition levels of the first data page version are prefixed by their length
+			var levels []int
+			if column.maxDefinition > 0 {
+				if len(page) < 4 || int64(binary.LittleEndian.Uint32(page)) > int64(len(page)-4) {
+					return nil, errInvalidParquetPage
+				}
+				levelsLength := int(binary.LittleEndian.Uint32(page))
+				if levels, err = parquetHybrid(page[4:4+levelsLength], parquetBitWidth(column.maxDefinition), count); err != nil {
+					return nil, err
+				}
+				page = page[4+levelsLength:]
+			}
+
+			if values, err = column.appendPage(values, page, pageHeader.int(2), count, levels, dictionary); err != nil {
+				return nil, err
+			}
+
+		case parquetDataPageV2:
+			pageHeader := header.fields(8)
+			count := int(pageHeader.int(1))
+			if count < 0 || count > rowCount-len(values) {
+				return nil, errInvalidParquetPage
+			}
+
+			// The levels of the second data page version are never compressed
+			definitionLength := int(pageHeader.int(5))
+			repetitionLength := int(pageHeader.int(6))
+			if definitionLength < 0 || repetitionLength < 0 || definitionLength > len(page)-repetitionLength {
+				return nil, errInvalidParquetPage
+			}
+
+			var levels []int
+			if column.maxDefinition > 0 {
+				if levels, err = parquetHybrid(page[repetitionLength:repetitionLength+definitionLength], parquetBitWidth(column.maxDefinition), count); err != nil {
+					return nil, err
+				}
+			}
+
+			page = page[repetitionLength+definitionLength:]
+			if pageHeader.bool(7, true) {
+				if page, err = r.decompress(codec, page, uncompressedSize-repetitionLength-definitionLength); err != nil {
+					return nil, err
+				}
+			}
+
+			if values, err = column.appendPage(values, page, pageHeader.int(4), count, levels, dictionary); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(values) < rowCount {
+		return nil, errors.New("Invalid Parquet file: missing values in column " + column.name)
+	}
+	return values, nil
+}
+
+// decompress decompresses a page of the uncompressed size of its header, which the decompressed data can't exceed
+func (r *parquetRowReader) decompress(codec int64, data []byte, size int) ([]byte, error) {
+	if size < 0 || size > parquetMaxPageSize {
+		return nil, errInvalidParquetPage
+	}
+	if codec == parquetUncompressed {
+		return data, nil
+	}
+
+	var decompressed []byte
+	switch codec {
+	case parquetSnappy:
+		// S2 decodes the Snappy blocks as well
+		length, err := s2.DecodedLen(data)
+		if err != nil {
+			return nil, err
+		}
+		if length > size {
+			return nil, errInvalidParquetPage
+		}
+		return s2.Decode(make([]byte, length), data)
+	case parquetGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		if decompressed, err = io.ReadAll(io.LimitReader(reader, int64(size)+1)); err != nil {
+			return nil, err
+		}
+	case parquetZstd:
+		var err error
+		if r.zstd == nil {
+			if r.zstd, err = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(parquetMaxPageSize)); err != nil {
+				return nil, err
+			}
+		}
+		if decompressed, err = r.zstd.DecodeAll(data, make([]byte, 0, size)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("Unsupported Parquet compression codec: " + strconv.FormatInt(codec, 10))
+	}
+
+	if len(decompressed) > size {
+		return nil, errInvalidParquetPage
+	}
+	return decompressed, nil
+}
+
+// appendPage decodes the values of a data page & appends them to the values of the column
+// The null values, which have a definition level lower than the maximum, are empty
+func (c *parquetColumn) appendPage(values []string, data []byte, encoding int64, count int, levels []int, dictionary []string) ([]string, error) {
+	present := count
+	if levels != nil {
+		present = 0
+		for _, level := range levels {
+			if level == c.maxDefinition {
+				present++
+			}
+		}
+	}
+
+	decoded, err := c.decodeValues(data, encoding, present, dictionary)
+	if err != nil {
+		return nil, err
+	}
+
+	if levels == nil {
+		return append(values, decoded...), nil
+	}
+
+	next := 0
+	for _, level := range levels {
+		if level == c.maxDefinition {
+			values = append(values, decoded[next])
+			next++
+		} else {
+			values = append(values, "")
+		}
+	}
+	return values, nil
+}
+
+func (c *parquetColumn) decodeValues(data []byte, encoding int64, count int, dictionary []string) ([]string, error) {
+	switch encoding {
+	case parquetPlainDictionary, parquetRLEDictionary:
+		if dictionary == nil {
+			return nil, errors.New("Invalid Parquet file: missing dictionary page")
+		}
+		if count == 0 {
+			return nil, nil
+		}
+		if len(data) == 0 {
+			return nil, errInvalidParquetPage
+		}
+
+		// The indexes are prefixed by their bit width
+		indexes, err := parquetHybrid(data[1:], int(data[0]), count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, count)
+		for i, index := range indexes {
+			if index >= len(dictionary) {
+				return nil, errInvalidParquetPage
+			}
+			values[i] = dictionary[index]
+		}
+		return values, nil
+
+	case parquetRLE:
+		if c.physicalType != parquetTypeBoolean {
+			break
+		}
+		if len(data) < 4 || int64(binary.LittleEndian.Uint32(data)) > int64(len(data)-4) {
+			return nil, errInvalidParquetPage
+		}
+		bits, err := parquetHybrid(data[4:4+binary.LittleEndian.Uint32(data)], 1, count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]string, count)
+		for i, bit := range bits {
+			values[i] = strconv.FormatBool(bit == 1)
+		}
+		return values, nil
+	}
+
+	raw, err := c.decodeRaw(data, encoding, count)
+	if err != nil {
+		return nil, err
+	}
+	return c.formatAll(raw), nil
+}
+
+// decodeRaw decodes the values of the encodings which aren't dictionary or run length based
+func (c *parquetColumn) decodeRaw(data []byte, encoding int64, count int) ([]interface{}, error) {
+	switch encoding {
+	case parquetPlain:
+		return c.decodePlain(data, count)
+
+	case parquetDeltaBinaryPacked:
+		if c.physicalType != parquetTypeInt32 && c.physicalType != parquetTypeInt64 {
+			break
+		}
+		ints, _, err := parquetDeltaBinary(data, count)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]interface{}, count)
+		for i, v := range ints {
+			if c.physicalType == parquetTypeInt32 {
+				values[i] = int32(v)
+			} else {
+				values[i] = v
+			}
+		}
+		return values, nil
+
+	case parquetDeltaLengthByteArray:
+		if c.physicalType != parquetTypeByteArray {
+			break
+		}
+		return parquetDeltaLength(data, count)
+
+	case parquetDeltaByteArray:
+		if c.physicalType != parquetTypeByteArray && c.physicalType != parquetTypeFixedLenByteArray {
+			break
+		}
+		prefixes, n, err := parquetDeltaBinary(data, count)
+		if err != nil {
+			return nil, err
+		}
+		suffixes, err := parquetDeltaLength(data[n:], count)
+		if err != nil {
+			return nil, err
+		}
+
+		// Every value shares a prefix with the previous one
+		var previous []byte
+		for i, suffix := range suffixes {
+			prefix := prefixes[i]
+			if prefix < 0 || prefix > int64(len(previous)) {
+				return nil, errInvalidParquetPage
+			}
+			value := append(previous[:prefix:prefix], suffix.([]byte)...)
+			suffixes[i] = value
+			previous = value
+		}
+		return suffixes, nil
+
+	case parquetByteStreamSplit:
+		// The bytes of the values are split into a stream per byte position
+		width := c.valueWidth()
+		if width <= 0 {
+			break
+		}
+		if len(data)/width < count {
+			return nil, errInvalidParquetPage
+		}
+		plain := make([]byte, count*width)
+		for i := 0; i < count; i++ {
+			for b := 0; b < width; b++ {
+				plain[i*width+b] = data[b*count+i]
+			}
+		}
+		return c.decodePlain(plain, count)
+	}
+
+	return nil, errors.New("Unsupported Parquet encoding of column " + c.name + ": " + strconv.FormatInt(encoding, 10))
+}
+
+// valueWidth returns the size in bytes of the fixed size values
+func (c *parquetColumn) valueWidth() int {
+	switch c.physicalType {
+	case parquetTypeInt32, parquetTypeFloat:
+		return 4
+	case parquetTypeInt64, parquetTypeDouble:
+		return 8
+	case parquetTypeInt96:
+		return 12
+	case parquetTypeFixedLenByteArray:
+		return c.typeLength
+	}
+	return 0
+}
+
+func (c *parquetColumn) decodePlain(data []byte, count int) ([]interface{}, error) {
+	if count < 0 {
+		return nil, errInvalidParquetPage
+	}
+
+	switch c.physicalType {
+	case parquetTypeBoolean:
+		// Booleans are bit packed
+		if len(data) < (count+7)/8 {
+			return nil, errInvalidParquetPage
+		}
+		values := make([]interface{}, count)
+		for i := range values {
+			values[i] = data[i/8]>>(i%8)&1 == 1
+		}
+		return values, nil
+
+	case parquetTypeByteArray:
+		// Byte arrays are prefixed by their length
+		var values []interface{}
+		pos := 0
+		for i := 0; i < count; i++ {
+			if len(data)-pos < 4 || int64(binary.LittleEndian.Uint32(data[pos:])) > int64(len(data)-pos-4) {
+				return nil, errInvalidParquetPage
+			}
+			length := int(binary.LittleEndian.Uint32(data[pos:]))
+			values = append(values, data[pos+4:pos+4+length])
+			pos += 4 + length
+		}
+		return values, nil
+	}
+
+	width := c.valueWidth()
+	if width <= 0 {
+		return nil, errors.New("Invalid Parquet file: invalid type of column " + c.name)
+	}
+	if len(data)/width < count {
+		return nil, errInvalidParquetPage
+	}
+
+	values := make([]interface{}, count)
+	for i := range values {
+		value := data[i*width : (i+1)*width]
+		switch c.physicalType {
+		case parquetTypeInt32:
+			values[i] = int32(binary.LittleEndian.Uint32(value))
+		case parquetTypeInt64:
+			values[i] = int64(binary.LittleEndian.Uint64(value))
+		case parquetTypeInt96:
+			var v parquetInt96
+			copy(v[:], value)
+			values[i] = v
+		case parquetTypeFloat:
+			values[i] = math.Float32frombits(binary.LittleEndian.Uint32(value))
+		case parquetTypeDouble:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(value))
+		case parquetTypeFixedLenByteArray:
+			values[i] = value
+		}
+	}
+	return values, nil
+}
+
+func (c *parquetColumn) formatAll(raw []interface{}) []string {
+	values := make([]string, len(raw))
+	for i, v := range raw {
+		values[i] = c.format(v)
+	}
+	return values
+}
+
+// format formats a value according to the logical type of the column
+func (c *parquetColumn) format(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case int32:
+		if c.isUnsigned() {
+			return strconv.FormatUint(uint64(uint32(v)), 10)
+		}
+		return c.formatInt(int64(v))
+	case int64:
+		if c.isUnsigned() {
+			return strconv.FormatUint(uint64(v), 10)
+		}
+		return c.formatInt(v)
+	case parquetInt96:
+		nanoseconds := int64(binary.LittleEndian.Uint64(v[:8]))
+		days := int64(binary.LittleEndian.Uint32(v[8:]))
+		return time.Unix((days-parquetJulianEpoch)*24*60*60, nanoseconds).UTC().Format(time.RFC3339Nano)
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	case []byte:
+		return c.formatBytes(v)
+	}
+	return ""
+}
+
+func (c *parquetColumn) formatInt(v int64) string {
+	logical := c.logicalType
+	switch {
+	case logical.has(5) || c.convertedType == 5:
+		return formatDecimal(big.NewInt(v), c.decimalScale())
+	case logical.has(6) || c.convertedType == 6:
+		return formatDate(v)
+	case logical.has(8) || c.convertedType == 9 || c.convertedType == 10:
+		return formatTimestamp(v, c.timeUnit(), !logical.has(8) || logical.fields(8).bool(1, true))
+	case logical.has(7) || c.convertedType == 7 || c.convertedType == 8:
+		return formatTime(v, c.timeUnit())
+	}
+
+	return strconv.FormatInt(v, 10)
+}
+
+func (c *parquetColumn) formatBytes(v []byte) string {
+	logical := c.logicalType
+	switch {
+	case logical.has(5) || c.convertedType == 5:
+		return formatDecimalBytes(v, c.decimalScale())
+	case logical.has(14) && len(v) == 16:
+		return formatUUID(v)
+	case logical.has(15) && len(v) == 2:
+		return formatFloat(float64(parquetFloat16(binary.LittleEndian.Uint16(v))), 32)
+	}
+
+	return string(v)
+}
+
+func (c *parquetColumn) isUnsigned() bool {
+	if c.logicalType.has(10) {
+		return !c.logicalType.fields(10).bool(2, true)
+	}
+	return c.convertedType >= 11 && c.convertedType <= 14
+}
+
+func (c *parquetColumn) decimalScale() int {
+	if c.logicalType.has(5) {
+		return int(c.logicalType.fields(5).int(1))
+	}
+	return c.scale
+}
+
+// timeUnit returns the duration of the unit of the times & timestamps of the column
+func (c *parquetColumn) timeUnit() time.Duration {
+	var unit thriftFields
+	if c.logicalType.has(8) {
+		unit = c.logicalType.fields(8).fields(2)
+	} else if c.logicalType.has(7) {
+		unit = c.logicalType.fields(7).fields(2)
+	}
+
+	switch {
+	case unit.has(2) || c.convertedType == 8 || c.convertedType == 10:
+		return time.Microsecond
+	case unit.has(3):
+		return time.Nanosecond
+	}
+	return time.Millisecond
+}
+
+// parquetFloat16 converts a half-precision float
+func parquetFloat16(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exponent := uint32(h>>10) & 0x1f
+	mantissa := uint32(h) & 0x3ff
+
+	switch {
+	case exponent == 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | mantissa<<13)
+	case exponent == 0:
+		// Subnormal numbers
+		v := float32(mantissa) / (1 << 24)
+		if sign != 0 {
+			return -v
+		}
+		return v
+	}
+	return math.Float32frombits(sign | (exponent+127-15)<<23 | mantissa<<13)
+}
+
+// parquetBitWidth returns the number of bits needed to store the values up to max
+func parquetBitWidth(max int) int {
+	width := 0
+	for max > 0 {
+		width++
+		max >>= 1
+	}
+	return width
+}
+
+// parquetHybrid decodes count values of the RLE / bit-packing hybrid encoding
+func parquetHybrid(data []byte, bitWidth int, count int) ([]int, error) {
+	if bitWidth < 0 || bitWidth > 32 {
+		return nil, errInvalidParquetPage
+	}
+
+	// The runs of repeated values can hold more values than bytes, so count only bounds the values decoded
+	values := make([]int, 0, min(count, len(data)*8))
+	byteWidth := (bitWidth + 7) / 8
+	pos := 0
+	for len(values) < count {
+		header, n := binary.Uvarint(data[pos:])
+		if n <= 0 {
+			return nil, errInvalidParquetPage
+		}
+		pos += n
+
+		if header&1 == 0 {
+			// Run of a repeated value
+			if len(data)-pos < byteWidth {
+				return nil, errInvalidParquetPage
+			}
+			value := 0
+			for i := 0; i < byteWidth; i++ {
+				value |= int(data[pos+i]) << (8 * i)
+			}
+			pos += byteWidth
+
+			for run := header >> 1; run > 0 && len(values) < count; run-- {
+				values = append(values, value)
+			}
+			continue
+		}
+
+		// Groups of 8 bit packed values
+		groups := header >> 1
+		if groups > uint64(len(data)-pos) {
+			return nil, errInvalidParquetPage
+		}
+		size := int(groups) * bitWidth
+		if size > len(data)-pos {
+			return nil, errInvalidParquetPage
+		}
+		packed := data[pos : pos+size]
+		for i := 0; i < int(groups)*8 && len(values) < count; i++ {
+			values = append(values, int(parquetUnpack(packed, uint64(i*bitWidth), uint(bitWidth))))
+		}
+		pos += size
+	}
+
+	return values, nil
+}
+
+// parquetUnpack reads the value of bitWidth bits at a bit offset, with the least significant bits first
+func parquetUnpack(data []byte, offset uint64, bitWidth uint) uint64 {
+	var v uint64
+	for read := uint(0); read < bitWidth; {
+		b := uint64(data[offset/8] >> (offset % 8))
+		n := 8 - uint(offset%8)
+		if n > bitWidth-read {
+			n = bitWidth - read
+		}
+		v |= (b & (1<<n - 1)) << read
+		read += n
+		offset += uint64(n)
+	}
+	return v
+}
+
+// parquetDeltaBinary decodes count integers of the delta binary packed encoding & returns the size of the encoded data
+func parquetDeltaBinary(data []byte, count int) ([]int64, int, error) {
+	decoder := &thriftDecoder{data: data}
+	blockSize, err := decoder.readUvarint()
+	if err != nil {
+		return nil, 0, errInvalidParquetPage
+	}
+	miniblocks, err := decoder.readUvarint()
+	if err != nil {
+		return nil, 0, errInvalidParquetPage
+	}
+	total, err := decoder.readUvarint()
+	if err != nil {
+		return nil, 0, errInvalidParquetPage
+	}
+	first, err := decoder.readVarint()
+	if err != nil {
+		return nil, 0, errInvalidParquetPage
+	}
+	if total != uint64(count) || blockSize == 0 || miniblocks == 0 || blockSize%miniblocks != 0 || (blockSize/miniblocks)%8 != 0 || blockSize > 1<<20 {
+		return nil, 0, errInvalidParquetPage
+	}
+	miniblockSize := int(blockSize / miniblocks)
+
+	values := make([]int64, 0, min(count, len(data)*8))
+	if count > 0 {
+		values = append(values, first)
+	}
+	last := first
+	for len(values) < count {
+		minDelta, err := decoder.readVarint()
+		if err != nil {
+			return nil, 0, errInvalidParquetPage
+		}
+		if uint64(len(data)-decoder.pos) < miniblocks {
+			return nil, 0, errInvalidParquetPage
+		}
+		widths := data[decoder.pos : decoder.pos+int(miniblocks)]
+		decoder.pos += int(miniblocks)
+
+		for _, width := range widths {
+			if len(values) == count {
+				break
+			}
+			if width > 64 {
+				return nil, 0, errInvalidParquetPage
+			}
+			size := miniblockSize * int(width) / 8
+			if size > len(data)-decoder.pos {
+				return nil, 0, errInvalidParquetPage
+			}
+
+			packed := data[decoder.pos : decoder.pos+size]
+			for i := 0; i < miniblockSize && len(values) < count; i++ {
+				last += minDelta + int64(parquetUnpack(packed, uint64(i)*uint64(width), uint(width)))
+				values = append(values, last)
+			}
+			decoder.pos += size
+		}
+	}
+
+	return values, decoder.pos, nil
+}
+
+// parquetDeltaLength decodes count byte arrays of the delta length byte array encoding
+func parquetDeltaLength(data []byte, count int) ([]interface{}, error) {
+	lengths, pos, err := parquetDeltaBinary(data, count)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, count)
+	for i, length := range lengths {
+		if length < 0 || length > int64(len(data)-pos) {
+			return nil, errInvalidParquetPage
+		}
+		values[i] = data[pos : pos+int(length)]
+		pos += int(length)
+	}
+	return values, nil
+}
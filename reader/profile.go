@@ -0,0 +1,236 @@
+package reader
+
+import (
+	"hash/maphash"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ColumnType is the type of the values of a column inferred by the Profiler
+type ColumnType string
+
+// Column types
+// ColumnEmpty is the type of the columns without values
+// ColumnBoolean is the type of the columns of true/false, yes/no, y/n or on/off values, ignoring their case. The 1/0 values are integers
+// ColumnString is the type of the columns whose values have different types
+const (
+	ColumnEmpty     ColumnType = "empty"
+	ColumnBoolean   ColumnType = "boolean"
+	ColumnInteger   ColumnType = "integer"
+	ColumnNumber    ColumnType = "number"
+	ColumnDate      ColumnType = "date"
+	ColumnTimestamp ColumnType = "timestamp"
+	ColumnString    ColumnType = "string"
+)
+
+// exactDistinct is the number of distinct values counted exactly, beyond which they are estimated
+const exactDistinct = 1000
+
+// hllPrecision is the number of bits of the hashes indexing the registers of the distinct count estimates, which makes their standard error about 1.6%
+const hllPrecision = 12
+
+// ColumnProfile is the statistics of the values of a column
+// Count is the number of values & Nulls the number of empty ones
+// Distinct is the number of distinct non empty values. It is exact up to 1000 values, then estimated
+// MinLength & MaxLength are the lengths in characters of the shortest & longest non empty values
+type ColumnProfile struct {
+	Name      string
+	Count     int
+	Nulls     int
+	Distinct  int
+	MinLength int
+	MaxLength int
+	Type      ColumnType
+}
+
+// Profiler computes the statistics of the columns of the records read, in the same pass
+// It is safe for concurrent use, so that it can profile the records of several reads (ex: FromURLs)
+type Profiler struct {
+	mu      sync.Mutex
+	seed    maphash.Seed
+	columns map[string]*columnStats
+	order   []string
+}
+
+// columnStats is the running statistics of a column
+type columnStats struct {
+	profile ColumnProfile
+	types   map[ColumnType]bool
+	// values holds the distinct values until there are too many to count them exactly, then registers holds their estimate
+	values    map[string]struct{}
+	registers []uint8
+}
+
+// NewProfiler is the initialization method for a Profiler
+func NewProfiler() *Profiler {
+	return &Profiler{
+		seed:    maphash.MakeSeed(),
+		columns: make(map[string]*columnStats),
+	}
+}
+
+// Add adds the values of a record to the statistics
+func (p *Profiler) Add(record map[string]string) {
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	// New columns are listed in a predictable order
+	sort.Strings(keys)
+
+	p.add(keys, record)
+}
+
+// add adds the values of a record to the statistics, listing its new columns in the order of keys
+func (p *Profiler) add(keys []string, record map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Duplicate columns are only profiled once
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		value, ok := record[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		stats, ok := p.columns[key]
+		if !ok {
+			stats = &columnStats{
+				profile: ColumnProfile{Name: key},
+				types:   make(map[ColumnType]bool),
+				values:  make(map[string]struct{}),
+			}
+			p.columns[key] = stats
+			p.order = append(p.order, key)
+		}
+		p.observe(stats, value)
+	}
+}
+
+func (p *Profiler) observe(stats *columnStats, value string) {
+	stats.profile.Count++
+	if value == "" {
+		stats.profile.Nulls++
+		return
+	}
+
+	length := utf8.RuneCountInString(value)
+	if stats.profile.Count-stats.profile.Nulls == 1 || length < stats.profile.MinLength {
+		stats.profile.MinLength = length
+	}
+	if length > stats.profile.MaxLength {
+		stats.profile.MaxLength = length
+	}
+
+	stats.types[inferType(value)] = true
+
+	if stats.registers == nil {
+		stats.values[value] = struct{}{}
+		if len(stats.values) <= exactDistinct {
+			return
+		}
+		// Too many distinct values to keep, so they are estimated from now on
+		stats.registers = make([]uint8, 1<<hllPrecision)
+		for v := range stats.values {
+			p.addHash(stats, v)
+		}
+		stats.values = nil
+		return
+	}
+	p.addHash(stats, value)
+}
+
+// addHash adds a value to the HyperLogLog registers of a column
+func (p *Profiler) addHash(stats *columnStats, value string) {
+	hash := maphash.String(p.seed, value)
+	index := hash >> (64 - hllPrecision)
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1))) + 1
+	if rank > stats.registers[index] {
+		stats.registers[index] = rank
+	}
+}
+
+// Columns returns the statistics of the columns, in the order they were first seen
+func (p *Profiler) Columns() []ColumnProfile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	profiles := make([]ColumnProfile, len(p.order))
+	for i, key := range p.order {
+		stats := p.columns[key]
+		profile := stats.profile
+		profile.Type = columnType(stats.types)
+		if stats.registers == nil {
+			profile.Distinct = len(stats.values)
+		} else {
+			profile.Distinct = estimateDistinct(stats.registers)
+		}
+		profiles[i] = profile
+	}
+	return profiles
+}
+
+// estimateDistinct is the HyperLogLog estimate of the number of distinct values
+func estimateDistinct(registers []uint8) int {
+	m := float64(len(registers))
+	sum, zeros := 0.0, 0
+	for _, rank := range registers {
+		sum += math.Ldexp(1, -int(rank))
+		if rank == 0 {
+			zeros++
+		}
+	}
+
+	estimate := 0.7213 / (1 + 1.079/m) * m * m / sum
+	// Linear counting is more accurate for the small cardinalities
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return int(math.Round(estimate))
+}
+
+// inferType returns the type of a value
+func inferType(value string) ColumnType {
+	switch strings.ToLower(value) {
+	case "true", "false", "yes", "no", "y", "n", "on", "off":
+		return ColumnBoolean
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return ColumnInteger
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return ColumnNumber
+	}
+	if _, err := time.Parse(time.DateOnly, value); err == nil {
+		return ColumnDate
+	}
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return ColumnTimestamp
+	}
+	return ColumnString
+}
+
+// columnType returns the type which fits all the types of the values of a column
+func columnType(types map[ColumnType]bool) ColumnType {
+	switch {
+	case len(types) == 0:
+		return ColumnEmpty
+	case len(types) == 1:
+		for t := range types {
+			return t
+		}
+	case len(types) == 2 && types[ColumnInteger] && types[ColumnNumber]:
+		return ColumnNumber
+	case len(types) == 2 && types[ColumnDate] && types[ColumnTimestamp]:
+		return ColumnTimestamp
+	}
+	return ColumnString
+}
@@ -0,0 +1,33 @@
+package reader
+
+import "io"
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	reader io.Reader
+	read   int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.reader.Read(p)
+	c.read += int64(n)
+	return n, err
+}
+
+// reportProgress calls the progress callback every ProgressInterval records & once the read is complete
+func (it *recordIterator) reportProgress(done bool) {
+	if it.options.OnProgress == nil || it.progressDone {
+		return
+	}
+	if !done && it.rows%it.options.ProgressInterval != 0 {
+		return
+	}
+
+	var bytesRead int64
+	if it.input != nil {
+		bytesRead = it.input.read
+	}
+
+	it.progressDone = done
+	it.options.OnProgress(it.rows, bytesRead)
+}
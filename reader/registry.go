@@ -0,0 +1,171 @@
+package reader
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"strings"
+	"sync"
+)
+
+// FormatOpener returns an iterator over the records of data in a format
+// name is the name of the source the data is read from, if any. The options apply to the records
+type FormatOpener func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error)
+
+// formatRegistry holds the registered formats & the file extensions they are detected from
+var formatRegistry = struct {
+	sync.RWMutex
+	openers    map[string]FormatOpener
+	extensions map[string]string
+}{
+	openers:    make(map[string]FormatOpener),
+	extensions: make(map[string]string),
+}
+
+// RegisterFormat registers the opener of a format & the file extensions (ex: ".csv") it is detected from
+// Registering a format again replaces it, which allows changing the format specific options of the built-in formats (ex: the sheet of xlsx files)
+func RegisterFormat(format string, extensions []string, open FormatOpener) {
+	formatRegistry.Lock()
+	defer formatRegistry.Unlock()
+
+	formatRegistry.openers[format] = open
+	for _, extension := range extensions {
+		formatRegistry.extensions[strings.ToLower(extension)] = format
+	}
+}
+
+// OpenSource opens a source & returns an iterator over its records
+// The data is read with the opener of format, or of the format detected from the extension of the name of the source when format is empty. Gzip & zstd compressed files are detected from their inner extension (ex: .csv.gz, .parquet.zst)
+func OpenSource(ctx context.Context, src Source, format string, options CSVOptions) (RecordIterator, error) {
+	name := sourceName(src)
+	if format == "" {
+		format = detectFormat(name)
+		if format == "" {
+			return nil, errors.New("Unknown format of source: " + name)
+		}
+	}
+
+	formatRegistry.RLock()
+	open, ok := formatRegistry.openers[format]
+	formatRegistry.RUnlock()
+	if !ok {
+		return nil, errors.New("Unknown format: " + format)
+	}
+
+	body, err := src.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := open(ctx, name, body, options)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+
+	return &sourceIterator{RecordIterator: it, body: body}, nil
+}
+
+// ReadSource reads all the records of a source, see OpenSource
+func ReadSource(ctx context.Context, src Source, format string, options CSVOptions) ([]map[string]string, error) {
+	it, err := OpenSource(ctx, src, format, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// detectFormat returns the format registered for the extension of a file name
+func detectFormat(name string) string {
+	// Query strings of URLs aren't part of the file name
+	if i := strings.IndexAny(name, "?#"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.ToLower(name)
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".gz"), ".zst")
+
+	formatRegistry.RLock()
+	defer formatRegistry.RUnlock()
+	return formatRegistry.extensions[path.Ext(name)]
+}
+
+// sourceIterator closes the data of the source along with the iterator over its records
+type sourceIterator struct {
+	RecordIterator
+	body io.Closer
+}
+
+func (it *sourceIterator) Close() error {
+	err := it.RecordIterator.Close()
+	if closeErr := it.body.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func init() {
+	RegisterFormat("csv", []string{".csv", ".txt"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		return NewCSV(options).(*csv).newRecordIterator(ctx, name, r)
+	})
+	RegisterFormat("tsv", []string{".tsv", ".tab"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		return NewTSV(options).(*csv).newRecordIterator(ctx, name, r)
+	})
+	RegisterFormat("ndjson", []string{".ndjson", ".jsonl"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		return NewNDJSON(NDJSONOptions{CSVOptions: options}).(*ndjson).open(ctx, name, r)
+	})
+	RegisterFormat("xml", []string{".xml"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		return NewXML(XMLOptions{CSVOptions: options}).(*xmlReader).open(ctx, name, r)
+	})
+	RegisterFormat("avro", []string{".avro"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		return NewAvro(AvroOptions{CSVOptions: options}).(*avro).open(ctx, name, r)
+	})
+
+	// Spreadsheets & Parquet files need random access, so their data is buffered in memory
+	RegisterFormat("parquet", []string{".parquet"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		data, err := readBuffered(r, options)
+		if err != nil {
+			return nil, err
+		}
+		return NewParquet(ParquetOptions{CSVOptions: options}).(*parquet).open(ctx, name, bytes.NewReader(data), int64(len(data)), nil)
+	})
+	RegisterFormat("xlsx", []string{".xlsx"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		archive, err := readZip(r, options)
+		if err != nil {
+			return nil, err
+		}
+		return NewXLSX(SpreadsheetOptions{CSVOptions: options}).(*xlsx).open(ctx, name, archive, nil)
+	})
+	RegisterFormat("ods", []string{".ods"}, func(ctx context.Context, name string, r io.Reader, options CSVOptions) (RecordIterator, error) {
+		archive, err := readZip(r, options)
+		if err != nil {
+			return nil, err
+		}
+		return NewODS(SpreadsheetOptions{CSVOptions: options}).(*ods).open(ctx, name, archive, nil)
+	})
+}
+
+// readBuffered decompresses & buffers data in memory, up to MaxBytes
+func readBuffered(r io.Reader, options CSVOptions) ([]byte, error) {
+	data, err := decompress(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	defer data.Close()
+
+	return readLimited(data, options.MaxBytes)
+}
+
+// readZip buffers a zip archive in memory, up to MaxBytes
+func readZip(r io.Reader, options CSVOptions) (*zip.Reader, error) {
+	data, err := readBuffered(r, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return zip.NewReader(bytes.NewReader(data), int64(len(data)))
+}
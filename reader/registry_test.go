@@ -0,0 +1,91 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "data.csv", expected: "csv"},
+		{name: "DATA.TSV", expected: "tsv"},
+		{name: "data.csv.gz", expected: "csv"},
+		{name: "data.parquet.zst", expected: "parquet"},
+		{name: "https://example.com/data.jsonl?token=1#part", expected: "ndjson"},
+		{name: "data.gz", expected: ""},
+		{name: "data.bin", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if format := detectFormat(tt.name); format != tt.expected {
+				t.Errorf("detectFormat() = %q, want %q", format, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadSourceCompressed(t *testing.T) {
+	xlsxData := xlsxTestFile(t, xlsxTestRow("id", "name")+xlsxTestRow("1", "a"), nil)
+	fsys := fstest.MapFS{
+		"data.xlsx.gz":  {Data: gzipData(t, string(xlsxData))},
+		"data.xlsx.zst": {Data: zstdData(t, string(xlsxData))},
+	}
+
+	for _, name := range []string{"data.xlsx.gz", "data.xlsx.zst"} {
+		t.Run(name, func(t *testing.T) {
+			records, err := ReadSource(context.Background(), FSSource(fsys, name), "", CSVOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := []map[string]string{{"id": "1", "name": "a"}}; !reflect.DeepEqual(records, expected) {
+				t.Errorf("ReadSource() = %v, want %v", records, expected)
+			}
+		})
+	}
+}
+
+func TestReadSourceMaxBytes(t *testing.T) {
+	// The archives are small, but their XML is large once decompressed
+	largeRows := strings.Repeat(xlsxTestRow("1", strings.Repeat("a", 100)), 1000)
+	largeSharedStrings := `<sst>` + strings.Repeat("<si><t>"+strings.Repeat("a", 100)+"</t></si>", 1000) + `</sst>`
+	largeTable := `<table:table table:name="Sheet1">` + strings.Repeat(odsTestRow("1", strings.Repeat("a", 100)), 1000) + `</table:table>`
+
+	// The archives are read whole when they are under the limit
+	tests := []struct {
+		name    string
+		data    []byte
+		archive bool
+	}{
+		{name: "data.parquet", data: []byte(strings.Repeat("a", 20000))},
+		{name: "data.xlsx", data: []byte(strings.Repeat("a", 20000))},
+		{name: "data.ods", data: []byte(strings.Repeat("a", 20000))},
+		{name: "data.csv.gz", data: gzipData(t, "id\n"+strings.Repeat("1\n", 10000))},
+		{name: "data.parquet.gz", data: gzipData(t, strings.Repeat("a", 20000))},
+		{name: "sheet.xlsx", data: xlsxTestFile(t, xlsxTestRow("id", "name")+largeRows, nil), archive: true},
+		{name: "shared strings.xlsx", data: xlsxTestFile(t, xlsxTestRow("id"), map[string]string{"xl/sharedStrings.xml": largeSharedStrings}), archive: true},
+		{name: "content.ods", data: odsTestFile(t, largeTable), archive: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.archive && len(tt.data) > 10000 {
+				t.Fatalf("archive of %d bytes, want less than the limit", len(tt.data))
+			}
+			fsys := fstest.MapFS{tt.name: {Data: tt.data}}
+
+			_, err := ReadSource(context.Background(), FSSource(fsys, tt.name), "", CSVOptions{MaxBytes: 10000})
+			var limitErr *LimitError
+			if !errors.As(err, &limitErr) || limitErr.Option != "MaxBytes" {
+				t.Errorf("ReadSource() error = %v, want a MaxBytes *LimitError", err)
+			}
+		})
+	}
+}
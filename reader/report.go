@@ -0,0 +1,82 @@
+package reader
+
+import (
+	gocsv "encoding/csv"
+	"errors"
+	"sync"
+)
+
+// ReadMode is the handling of the malformed rows (ex: rows with a wrong number of fields or bad quotes)
+type ReadMode int
+
+// Read modes
+// ReadStrict fails the read at the first malformed row. This is the default mode
+// ReadLenient skips the malformed rows & adds them to the Report
+const (
+	ReadStrict ReadMode = iota
+	ReadLenient
+)
+
+// RowError is a malformed row
+// Source is the path or URL the row is read from, if any. Line is the line of the row & Reason what is wrong with it
+// Err is the underlying error, usually a *csv.ParseError
+type RowError struct {
+	Source string
+	Line   int
+	Reason string
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadReport collects the malformed rows skipped by the lenient reads
+// It is safe for concurrent use, so that it can be shared by several reads
+type ReadReport struct {
+	mu      sync.Mutex
+	skipped []RowError
+}
+
+// Skipped returns the malformed rows skipped so far
+func (r *ReadReport) Skipped() []RowError {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]RowError(nil), r.skipped...)
+}
+
+func (r *ReadReport) add(rowErr RowError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.skipped = append(r.skipped, rowErr)
+}
+
+// rowError turns the parse errors of the rows into RowErrors, with the line numbers of the whole CSV
+func (it *recordIterator) rowError(err error) error {
+	var parseErr *gocsv.ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	if it.lineOffset != 0 {
+		shifted := *parseErr
+		shifted.StartLine += it.lineOffset
+		shifted.Line += it.lineOffset
+		parseErr = &shifted
+	}
+	return &RowError{Source: it.source, Line: parseErr.StartLine, Reason: parseErr.Err.Error(), Err: parseErr}
+}
+
+// skipRow adds a malformed row to the report of a lenient read
+func (it *recordIterator) skipRow(rowErr *RowError) {
+	it.options.Logger.Warn("Malformed row skipped", "source", it.source, "line", rowErr.Line, "reason", rowErr.Reason)
+	if it.options.Report != nil {
+		it.options.Report.add(*rowErr)
+	}
+}
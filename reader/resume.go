@@ -0,0 +1,103 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// resumableBody is a response body which resumes an interrupted download with an HTTP range request
+type resumableBody struct {
+	ctx       context.Context
+	client    *http.Client
+	req       *http.Request
+	body      io.ReadCloser
+	validator string
+	offset    int64
+	resumes   int
+	// maxResumes is the number of times the download can be resumed
+	maxResumes int
+	logger     *slog.Logger
+}
+
+// resumable wraps the body of the response so that it is resumed on failures, when the server allows it
+// A download is resumed only for GET requests whose response has a validator to check that the remote file hasn't changed
+func (c *csv) resumable(ctx context.Context, client *http.Client, req *http.Request, resp *http.Response) io.ReadCloser {
+	if c.options.MaxResumes <= 0 || req.Method != http.MethodGet || resp.Uncompressed {
+		return resp.Body
+	}
+	if resp.Header.Get("Accept-Ranges") != "bytes" {
+		return resp.Body
+	}
+
+	// If-Range requires a strong validator
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if validator == "" {
+		return resp.Body
+	}
+
+	return &resumableBody{
+		ctx:        ctx,
+		client:     client,
+		req:        req,
+		body:       resp.Body,
+		validator:  validator,
+		maxResumes: c.options.MaxResumes,
+		logger:     c.options.Logger,
+	}
+}
+
+func (r *resumableBody) Read(p []byte) (int, error) {
+	for {
+		n, err := r.body.Read(p)
+		r.offset += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			// The error is returned again by the next read
+			return n, nil
+		}
+		if r.ctx.Err() != nil || r.resumes >= r.maxResumes {
+			return 0, err
+		}
+
+		r.resumes++
+		r.logger.Warn("Resuming interrupted download", "url", logURL(r.req.URL), "offset", r.offset, "error", err)
+		if resumeErr := r.resume(); resumeErr != nil {
+			return 0, err
+		}
+	}
+}
+
+// resume requests the rest of the file from the current offset
+func (r *resumableBody) resume() error {
+	req := r.req.Clone(r.ctx)
+	req.Header.Set("Range", "bytes="+strconv.FormatInt(r.offset, 10)+"-")
+	req.Header.Set("If-Range", r.validator)
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		return errors.New("Unexpected HTTP status code: " + strconv.Itoa(resp.StatusCode))
+	}
+
+	r.body.Close()
+	r.body = resp.Body
+	return nil
+}
+
+// Close closes the current response body
+func (r *resumableBody) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,81 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryOptions consists of the retry options for the HTTP based reads
+// MaxAttempts is the maximum number of attempts for a request. Default value is 1, which disables retries
+// InitialBackoff is the wait before the first retry. It is doubled after every attempt. Default value is 500ms
+// MaxBackoff is the maximum wait between two attempts. Default value is 10s
+// RetryableStatusCodes are the HTTP status codes which are retried. Default value is 429, 502, 503 & 504
+type RetryOptions struct {
+	MaxAttempts          int
+	InitialBackoff       time.Duration
+	MaxBackoff           time.Duration
+	RetryableStatusCodes []int
+}
+
+// do sends the request, retrying transient failures with an exponential backoff
+// Only 200 responses are returned, & 304 responses when notModified is set for the conditional requests of a cached entry. The caller is responsible for closing the body of the returned response
+func (c *csv) do(ctx context.Context, client *http.Client, req *http.Request, notModified bool) (*http.Response, error) {
+	options := c.options.Retry
+	backoff := options.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		resp, err := client.Do(attemptReq)
+		if err == nil && (resp.StatusCode == http.StatusOK || (notModified && resp.StatusCode == http.StatusNotModified)) {
+			return resp, nil
+		}
+
+		if err == nil {
+			resp.Body.Close()
+			err = errors.New("Unexpected HTTP status code: " + strconv.Itoa(resp.StatusCode))
+			if !c.isRetryableStatus(resp.StatusCode) {
+				return nil, err
+			}
+		}
+		if ctx.Err() != nil || attempt >= options.MaxAttempts {
+			return nil, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// The body can't be sent again
+			return nil, err
+		}
+
+		c.options.Logger.Warn("Retrying request", "url", logURL(req.URL), "attempt", attempt, "backoff", backoff, "error", err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		backoff *= 2
+		if backoff > options.MaxBackoff {
+			backoff = options.MaxBackoff
+		}
+	}
+}
+
+func (c *csv) isRetryableStatus(statusCode int) bool {
+	for _, code := range c.options.Retry.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
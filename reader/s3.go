@@ -0,0 +1,161 @@
+package reader
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Options consists of the options used to read objects from S3
+// Region is the region of the bucket. Defaults to the AWS_REGION environment variable
+// AccessKeyID, SecretAccessKey & SessionToken are the credentials used to sign the requests. Default to the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY & AWS_SESSION_TOKEN environment variables
+// Endpoint is required only for S3 compatible stores (ex: MinIO). Objects are then addressed path style as <Endpoint>/<bucket>/<key>
+type S3Options struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Endpoint        string
+}
+
+const (
+	s3Algorithm        = "AWS4-HMAC-SHA256"
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// FromS3 reads the CSV from an S3 object
+func (c *csv) FromS3(ctx context.Context, bucket string, key string) ([]map[string]string, error) {
+	return c.FromSource(ctx, c.s3Source(bucket, key))
+}
+
+func (c *csv) s3Source(bucket string, key string) NamedSource {
+	return &requestSource{
+		csv:    c,
+		name:   "s3://" + bucket + "/" + key,
+		client: c.options.HTTPClient,
+		newRequest: func(ctx context.Context) (*http.Request, error) {
+			return c.newS3Request(ctx, bucket, key)
+		},
+	}
+}
+
+func (c *csv) newS3Request(ctx context.Context, bucket string, key string) (*http.Request, error) {
+	options := c.options.S3
+	if options.Region == "" {
+		options.Region = os.Getenv("AWS_REGION")
+	}
+	if options.AccessKeyID == "" {
+		options.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		options.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		options.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if options.Region == "" {
+		return nil, errors.New("S3 region is not configured")
+	}
+	if options.AccessKeyID == "" || options.SecretAccessKey == "" {
+		return nil, errors.New("S3 credentials are not configured")
+	}
+
+	objectURL := "https://" + bucket + ".s3." + options.Region + ".amazonaws.com/" + s3EscapePath(key)
+	if options.Endpoint != "" {
+		objectURL = strings.TrimSuffix(options.Endpoint, "/") + "/" + bucket + "/" + s3EscapePath(key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s3Sign(req, options, time.Now().UTC())
+
+	return req, nil
+}
+
+// s3Sign signs the request with AWS Signature Version 4
+func s3Sign(req *http.Request, options S3Options, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	scope := strings.Join([]string{now.Format("20060102"), options.Region, "s3", "aws4_request"}, "/")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3EmptyPayloadHash)
+	if options.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", options.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		lowerName := strings.ToLower(name)
+		if strings.HasPrefix(lowerName, "x-amz-") {
+			headers[lowerName] = strings.TrimSpace(req.Header.Get(name))
+		}
+	}
+
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		s3EmptyPayloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, s3SHA256Hex(canonicalRequest)}, "\n")
+
+	signingKey := []byte("AWS4" + options.SecretAccessKey)
+	for _, part := range strings.Split(scope, "/") {
+		signingKey = s3HMAC(signingKey, part)
+	}
+	signature := hex.EncodeToString(s3HMAC(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", s3Algorithm+" Credential="+options.AccessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// s3EscapePath escapes every segment of an object key while keeping the separators
+// Like the canonical URIs of Signature Version 4, every byte but the RFC 3986 unreserved characters is escaped (ex: = & : in date=2024-01-01/part.csv)
+func s3EscapePath(key string) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var escaped strings.Builder
+	for i := 0; i < len(key); i++ {
+		b := key[i]
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '.', b == '_', b == '~', b == '/':
+			escaped.WriteByte(b)
+		default:
+			escaped.WriteByte('%')
+			escaped.WriteByte(hexDigits[b>>4])
+			escaped.WriteByte(hexDigits[b&0x0F])
+		}
+	}
+	return escaped.String()
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func s3SHA256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+package reader
+
+import (
+	"context"
+	"testing"
+)
+
+func TestS3EscapePath(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"data/file.csv", "data/file.csv"},
+		{"date=2024-01-01/part.csv", "date%3D2024-01-01/part.csv"},
+		{"time/12:30:00.csv", "time/12%3A30%3A00.csv"},
+		{"a+b c@d&e$f~g_h.csv", "a%2Bb%20c%40d%26e%24f~g_h.csv"},
+		{"café.csv", "caf%C3%A9.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if escaped := s3EscapePath(tt.key); escaped != tt.expected {
+				t.Errorf("s3EscapePath() = %s, want %s", escaped, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewS3RequestCanonicalPath(t *testing.T) {
+	c := NewCSV(CSVOptions{S3: S3Options{Region: "eu-west-1", AccessKeyID: "id", SecretAccessKey: "secret"}}).(*csv)
+
+	tests := []struct {
+		key      string
+		expected string
+	}{
+		{"date=2024-01-01/part.csv", "/date%3D2024-01-01/part.csv"},
+		{"time/12:30:00.csv", "/time/12%3A30%3A00.csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			req, err := c.newS3Request(context.Background(), "bucket", tt.key)
+			if err != nil {
+				t.Fatalf("newS3Request() error = %v", err)
+			}
+			// The signed canonical URI is the escaped path of the request
+			if path := req.URL.EscapedPath(); path != tt.expected {
+				t.Errorf("EscapedPath() = %s, want %s", path, tt.expected)
+			}
+			if req.URL.Path != "/"+tt.key {
+				t.Errorf("Path = %s, want /%s", req.URL.Path, tt.key)
+			}
+		})
+	}
+}
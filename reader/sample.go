@@ -0,0 +1,82 @@
+package reader
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"sort"
+)
+
+// SampleMode is the selection of the records returned by Sample
+type SampleMode int
+
+// Sample modes
+// SampleHead returns the first records & stops reading as soon as they are read
+// SampleTail returns the last records
+// SampleRandom returns uniformly selected records (reservoir sampling) in the order they were read
+const (
+	SampleHead SampleMode = iota
+	SampleTail
+	SampleRandom
+)
+
+type sampledRecord struct {
+	record map[string]string
+	index  int
+}
+
+// Sample reads at most n records of the CSV from src, selected by mode
+// Only the sampled records are kept in memory
+func (c *csv) Sample(ctx context.Context, src io.Reader, mode SampleMode, n int) ([]map[string]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	it, err := c.newRecordIterator(ctx, "", src)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	samples := make([]sampledRecord, 0, n)
+	for index := 0; ; index++ {
+		if mode == SampleHead && len(samples) == n {
+			break
+		}
+
+		record, err := it.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case len(samples) < n:
+			samples = append(samples, sampledRecord{record: record, index: index})
+		case mode == SampleTail:
+			// The samples are used as a ring buffer
+			samples[index%n] = sampledRecord{record: record, index: index}
+		case mode == SampleRandom:
+			if j := rand.IntN(index + 1); j < n {
+				samples[j] = sampledRecord{record: record, index: index}
+			}
+		}
+	}
+
+	return orderSamples(samples), nil
+}
+
+// orderSamples returns the sampled records in the order they were read
+func orderSamples(samples []sampledRecord) []map[string]string {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].index < samples[j].index
+	})
+
+	records := make([]map[string]string, len(samples))
+	for i, sample := range samples {
+		records[i] = sample.record
+	}
+	return records
+}
@@ -0,0 +1,415 @@
+package reader
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// SFTP packet types & constants of the version 3 protocol used by the reader
+const (
+	sftpVersion = 3
+
+	sftpPacketInit    = 1
+	sftpPacketVersion = 2
+	sftpPacketOpen    = 3
+	sftpPacketClose   = 4
+	sftpPacketRead    = 5
+	sftpPacketStatus  = 101
+	sftpPacketHandle  = 102
+	sftpPacketData    = 103
+
+	sftpStatusOK  = 0
+	sftpStatusEOF = 1
+
+	sftpOpenRead  = 0x00000001
+	sftpChunkSize = 32 * 1024
+	sftpMaxReads  = 16
+
+	// sftpMaxPacketLength bounds the length of the packets received: 256 KiB of data, with the type, request id & data length of the data packets
+	sftpMaxPacketLength = 256*1024 + 9
+)
+
+// FromSFTP reads the CSV from a file on an SFTP server
+// config holds the user, authentication methods & host key verification used for the SSH connection
+func (c *csv) FromSFTP(ctx context.Context, addr string, filePath string, config *ssh.ClientConfig) ([]map[string]string, error) {
+	return c.FromSource(ctx, SFTPSource(addr, filePath, config))
+}
+
+// sftpSource is a file on an SFTP server, read through a new SSH connection every time it is opened
+type sftpSource struct {
+	addr     string
+	filePath string
+	config   *ssh.ClientConfig
+}
+
+func (s *sftpSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abort the SSH session if the context is cancelled mid transfer
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+	closeConn := func() {
+		close(done)
+		conn.Close()
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, s.addr, s.config)
+	if err != nil {
+		closeConn()
+		return nil, err
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	file, err := openSFTPFile(client, s.filePath)
+	if err != nil {
+		client.Close()
+		closeConn()
+		return nil, err
+	}
+	file.closeConn = func() {
+		client.Close()
+		closeConn()
+	}
+
+	return file, nil
+}
+
+func (s *sftpSource) Name() string {
+	return "sftp://" + s.addr + "/" + strings.TrimPrefix(s.filePath, "/")
+}
+
+// sftpFile is a sequential reader over a remote file opened through the SFTP subsystem
+// The reads are pipelined: sftpMaxReads requests of the next chunks are in flight while a chunk is returned
+type sftpFile struct {
+	session *ssh.Session
+	stdin   io.WriteCloser
+	stdout  io.Reader
+	handle  string
+	nextID  uint32
+	// offset is the offset of the next chunk requested
+	offset uint64
+	// reads are the read requests in flight, in the order of their offsets
+	reads []sftpRead
+	// responses are the responses received before they were waited for, by request id
+	responses map[uint32]sftpResponse
+	// inFlight are the ids of the requests without a response yet
+	inFlight map[uint32]bool
+	pending  []byte
+	eof      bool
+	// err is the error which desynchronized the responses, after which no response is received
+	err       error
+	closeConn func()
+}
+
+// sftpRead is a read request of length bytes at an offset
+type sftpRead struct {
+	id     uint32
+	offset uint64
+	length uint32
+}
+
+type sftpResponse struct {
+	packetType byte
+	data       []byte
+}
+
+func openSFTPFile(client *ssh.Client, filePath string) (*sftpFile, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	file := &sftpFile{session: session, responses: make(map[uint32]sftpResponse), inFlight: make(map[uint32]bool)}
+	if file.stdin, err = session.StdinPipe(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	if file.stdout, err = session.StdoutPipe(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	if err = session.RequestSubsystem("sftp"); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	if err = file.init(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	if err = file.open(filePath); err != nil {
+		session.Close()
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (f *sftpFile) init() error {
+	if err := f.send(sftpPacketInit, sftpUint32(sftpVersion)); err != nil {
+		return err
+	}
+
+	packetType, _, err := f.receive()
+	if err != nil {
+		return err
+	}
+	if packetType != sftpPacketVersion {
+		return errors.New("Unexpected SFTP packet type: " + strconv.Itoa(int(packetType)))
+	}
+
+	return nil
+}
+
+func (f *sftpFile) open(filePath string) error {
+	payload := sftpString(filePath)
+	payload = append(payload, sftpUint32(sftpOpenRead)...)
+	// Empty attributes
+	payload = append(payload, sftpUint32(0)...)
+
+	packetType, data, err := f.request(sftpPacketOpen, payload)
+	if err != nil {
+		return err
+	}
+
+	switch packetType {
+	case sftpPacketHandle:
+		handle, _, err := sftpReadString(data)
+		if err != nil {
+			return err
+		}
+		f.handle = handle
+		return nil
+	case sftpPacketStatus:
+		return sftpStatusError(data)
+	default:
+		return errors.New("Unexpected SFTP packet type: " + strconv.Itoa(int(packetType)))
+	}
+}
+
+// Read reads the remote file sequentially in chunks
+func (f *sftpFile) Read(p []byte) (int, error) {
+	for len(f.pending) == 0 {
+		if f.eof {
+			return 0, io.EOF
+		}
+		if err := f.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, f.pending)
+	f.pending = f.pending[n:]
+	return n, nil
+}
+
+// readChunk requests the next chunks up to sftpMaxReads requests in flight, then waits for the first of them
+func (f *sftpFile) readChunk() error {
+	for len(f.reads) < sftpMaxReads {
+		read, err := f.requestRead(f.offset, sftpChunkSize)
+		if err != nil {
+			return err
+		}
+		f.reads = append(f.reads, read)
+		f.offset += sftpChunkSize
+	}
+
+	read := f.reads[0]
+	f.reads = f.reads[1:]
+	packetType, data, err := f.wait(read.id)
+	if err != nil {
+		return err
+	}
+
+	switch packetType {
+	case sftpPacketData:
+		chunk, _, err := sftpReadString(data)
+		if err != nil {
+			return err
+		}
+		// The end of the file is reported by a status, so an empty chunk would be read forever
+		if len(chunk) == 0 || len(chunk) > int(read.length) {
+			return errors.New("Invalid SFTP data length")
+		}
+		f.pending = []byte(chunk)
+
+		// The rest of a short chunk is read before the chunks already requested
+		if len(chunk) < int(read.length) {
+			rest, err := f.requestRead(read.offset+uint64(len(chunk)), read.length-uint32(len(chunk)))
+			if err != nil {
+				return err
+			}
+			f.reads = append([]sftpRead{rest}, f.reads...)
+		}
+		return nil
+	case sftpPacketStatus:
+		if len(data) >= 4 && binary.BigEndian.Uint32(data) == sftpStatusEOF {
+			f.eof = true
+			return nil
+		}
+		return sftpStatusError(data)
+	default:
+		return errors.New("Unexpected SFTP packet type: " + strconv.Itoa(int(packetType)))
+	}
+}
+
+func (f *sftpFile) requestRead(offset uint64, length uint32) (sftpRead, error) {
+	payload := sftpString(f.handle)
+	payload = binary.BigEndian.AppendUint64(payload, offset)
+	payload = append(payload, sftpUint32(length)...)
+
+	id, err := f.sendRequest(sftpPacketRead, payload)
+	if err != nil {
+		return sftpRead{}, err
+	}
+	return sftpRead{id: id, offset: offset, length: length}, nil
+}
+
+// Close releases the remote file handle & the SFTP session, along with the connection of the source
+func (f *sftpFile) Close() error {
+	defer func() {
+		f.session.Close()
+		if f.closeConn != nil {
+			f.closeConn()
+		}
+	}()
+
+	// The responses to the reads still in flight are received first
+	_, data, err := f.request(sftpPacketClose, sftpString(f.handle))
+	if err != nil {
+		return err
+	}
+	if len(data) >= 4 && binary.BigEndian.Uint32(data) != sftpStatusOK {
+		return sftpStatusError(data)
+	}
+
+	return nil
+}
+
+// request sends a packet with a request id & returns the response without its id
+func (f *sftpFile) request(packetType byte, payload []byte) (byte, []byte, error) {
+	id, err := f.sendRequest(packetType, payload)
+	if err != nil {
+		return 0, nil, err
+	}
+	return f.wait(id)
+}
+
+// sendRequest sends a packet with the next request id & returns the id
+func (f *sftpFile) sendRequest(packetType byte, payload []byte) (uint32, error) {
+	f.nextID++
+	id := f.nextID
+
+	if err := f.send(packetType, append(sftpUint32(id), payload...)); err != nil {
+		return 0, err
+	}
+	f.inFlight[id] = true
+	return id, nil
+}
+
+// wait returns the response to a request without its id, keeping the responses to the other requests in flight received meanwhile
+func (f *sftpFile) wait(id uint32) (byte, []byte, error) {
+	for {
+		if response, ok := f.responses[id]; ok {
+			delete(f.responses, id)
+			return response.packetType, response.data, nil
+		}
+		if f.err != nil {
+			return 0, nil, f.err
+		}
+
+		responseType, data, err := f.receive()
+		if err == nil && (len(data) < 4 || !f.inFlight[binary.BigEndian.Uint32(data)]) {
+			err = errors.New("Unexpected SFTP response id")
+		}
+		if err != nil {
+			f.err = err
+			return 0, nil, err
+		}
+
+		responseID := binary.BigEndian.Uint32(data)
+		delete(f.inFlight, responseID)
+		f.responses[responseID] = sftpResponse{packetType: responseType, data: data[4:]}
+	}
+}
+
+func (f *sftpFile) send(packetType byte, payload []byte) error {
+	packet := sftpUint32(uint32(len(payload) + 1))
+	packet = append(packet, packetType)
+	packet = append(packet, payload...)
+
+	_, err := f.stdin.Write(packet)
+	return err
+}
+
+func (f *sftpFile) receive() (byte, []byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(f.stdout, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header)
+	if length == 0 || length > sftpMaxPacketLength {
+		return 0, nil, errors.New("Invalid SFTP packet length")
+	}
+
+	data := make([]byte, length-1)
+	if _, err := io.ReadFull(f.stdout, data); err != nil {
+		return 0, nil, err
+	}
+
+	return header[4], data, nil
+}
+
+func sftpStatusError(data []byte) error {
+	if len(data) < 4 {
+		return errors.New("Invalid SFTP status packet")
+	}
+
+	code := binary.BigEndian.Uint32(data)
+	message, _, err := sftpReadString(data[4:])
+	if err != nil || message == "" {
+		message = "status code " + strconv.Itoa(int(code))
+	}
+
+	return errors.New("SFTP error: " + message)
+}
+
+func sftpUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func sftpString(s string) []byte {
+	return append(sftpUint32(uint32(len(s))), s...)
+}
+
+func sftpReadString(data []byte) (string, []byte, error) {
+	if len(data) < 4 {
+		return "", nil, errors.New("Invalid SFTP string")
+	}
+
+	length := binary.BigEndian.Uint32(data)
+	if uint32(len(data)-4) < length {
+		return "", nil, errors.New("Invalid SFTP string")
+	}
+
+	return string(data[4 : 4+length]), data[4+length:], nil
+}
@@ -0,0 +1,307 @@
+package reader
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpTestServer serves the files of an in-process SSH server through a minimal SFTP subsystem
+// respond can replace the response to a request, given its type, id & payload
+// maxChunk limits the length of the chunks read, if set, & reorder sends the responses to every other read after the response to the next request
+type sftpTestServer struct {
+	files    map[string]string
+	respond  func(packetType byte, id uint32, payload []byte) []byte
+	maxChunk int
+	reorder  bool
+}
+
+// start starts the server & returns its address & the client configuration trusting its host key
+func (s *sftpTestServer) start(t *testing.T) (string, *ssh.ClientConfig) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), &ssh.ClientConfig{User: "test", HostKeyCallback: ssh.FixedHostKey(signer.PublicKey())}
+}
+
+func (s *sftpTestServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		go s.serveSFTP(channel)
+	}
+}
+
+func (s *sftpTestServer) serveSFTP(channel ssh.Channel) {
+	defer channel.Close()
+
+	handles := make(map[string]string)
+	// held is the response to a read sent after the response to the next request
+	var held []byte
+	for {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(channel, header); err != nil {
+			return
+		}
+		packet := make([]byte, binary.BigEndian.Uint32(header)-1)
+		if _, err := io.ReadFull(channel, packet); err != nil {
+			return
+		}
+
+		// The init packet holds the version in place of the id
+		packetType := header[4]
+		id := binary.BigEndian.Uint32(packet)
+		payload := packet[4:]
+		if s.respond != nil {
+			if response := s.respond(packetType, id, payload); response != nil {
+				channel.Write(response)
+				continue
+			}
+		}
+
+		switch packetType {
+		case sftpPacketInit:
+			channel.Write(sftpTestPacket(sftpPacketVersion, sftpUint32(sftpVersion)))
+
+		case sftpPacketOpen:
+			filePath, _, _ := sftpReadString(payload)
+			if _, ok := s.files[filePath]; !ok {
+				channel.Write(sftpTestStatus(id, 2, "No such file"))
+				continue
+			}
+			handle := strconv.Itoa(len(handles))
+			handles[handle] = filePath
+			channel.Write(sftpTestPacket(sftpPacketHandle, append(sftpUint32(id), sftpString(handle)...)))
+
+		case sftpPacketRead:
+			handle, rest, _ := sftpReadString(payload)
+			offset := binary.BigEndian.Uint64(rest)
+			length := uint64(binary.BigEndian.Uint32(rest[8:]))
+			content := s.files[handles[handle]]
+			if s.maxChunk > 0 {
+				length = min(length, uint64(s.maxChunk))
+			}
+			response := sftpTestStatus(id, sftpStatusEOF, "EOF")
+			if offset < uint64(len(content)) {
+				chunk := content[offset:min(offset+length, uint64(len(content)))]
+				response = sftpTestPacket(sftpPacketData, append(sftpUint32(id), sftpString(chunk)...))
+			}
+			// Only the full chunks of the first reads are held, so that the next request is always in flight
+			if s.reorder && held == nil && offset%(2*sftpChunkSize) == 0 && offset+length <= uint64(len(content)) {
+				held = response
+				continue
+			}
+			channel.Write(response)
+
+		case sftpPacketClose:
+			channel.Write(sftpTestStatus(id, sftpStatusOK, ""))
+		}
+
+		if held != nil && packetType != sftpPacketInit {
+			channel.Write(held)
+			held = nil
+		}
+	}
+}
+
+func sftpTestPacket(packetType byte, payload []byte) []byte {
+	return append(append(sftpUint32(uint32(len(payload)+1)), packetType), payload...)
+}
+
+func sftpTestStatus(id uint32, code uint32, message string) []byte {
+	payload := append(sftpUint32(id), sftpUint32(code)...)
+	payload = append(payload, sftpString(message)...)
+	// Empty language tag
+	return sftpTestPacket(sftpPacketStatus, append(payload, sftpUint32(0)...))
+}
+
+func TestFromSFTP(t *testing.T) {
+	// The file spans several chunks of the reader
+	var content strings.Builder
+	content.WriteString("id,name\n")
+	for i := 0; i < 10000; i++ {
+		content.WriteString(strconv.Itoa(i) + ",name " + strconv.Itoa(i) + "\n")
+	}
+
+	server := &sftpTestServer{files: map[string]string{"/data/file.csv": content.String()}}
+	addr, config := server.start(t)
+
+	records, err := NewCSV(CSVOptions{}).FromSFTP(context.Background(), addr, "/data/file.csv", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 10000 {
+		t.Fatalf("FromSFTP() returned %d records, want %d", len(records), 10000)
+	}
+	if last := records[9999]; last["id"] != "9999" || last["name"] != "name 9999" {
+		t.Errorf("FromSFTP() last record = %v, want id 9999", last)
+	}
+}
+
+func TestSFTPSource(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("id,name\n")
+	for i := 0; i < 10000; i++ {
+		content.WriteString(strconv.Itoa(i) + ",name " + strconv.Itoa(i) + "\n")
+	}
+
+	tests := []struct {
+		name   string
+		server *sftpTestServer
+	}{
+		{name: "in order", server: &sftpTestServer{}},
+		// The rest of the short chunks is read before the next chunks
+		{name: "short chunks", server: &sftpTestServer{maxChunk: 1000}},
+		// The responses to the pipelined reads are matched by id
+		{name: "reordered responses", server: &sftpTestServer{reorder: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.server.files = map[string]string{"/data/file.csv": content.String()}
+			addr, config := tt.server.start(t)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			src := SFTPSource(addr, "/data/file.csv", config)
+			records, err := NewCSV(CSVOptions{SourceColumn: "source"}).FromSource(ctx, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(records) != 10000 {
+				t.Fatalf("FromSource() returned %d records, want %d", len(records), 10000)
+			}
+			for i, record := range records {
+				if record["id"] != strconv.Itoa(i) || record["name"] != "name "+strconv.Itoa(i) {
+					t.Fatalf("FromSource() record %d = %v, want id %d", i, record, i)
+				}
+			}
+			if expected := "sftp://" + addr + "/data/file.csv"; records[0]["source"] != expected {
+				t.Errorf("FromSource() source = %s, want %s", records[0]["source"], expected)
+			}
+		})
+	}
+}
+
+func TestFromSFTPErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		filePath string
+		respond  func(packetType byte, id uint32, payload []byte) []byte
+		expected string
+	}{
+		{
+			name:     "status",
+			filePath: "/missing.csv",
+			expected: "SFTP error: No such file",
+		},
+		{
+			name:     "handshake",
+			filePath: "/data/file.csv",
+			respond: func(packetType byte, id uint32, payload []byte) []byte {
+				if packetType != sftpPacketInit {
+					return nil
+				}
+				return sftpTestPacket(sftpPacketHandle, append(sftpUint32(id), sftpString("0")...))
+			},
+			expected: "Unexpected SFTP packet type: 102",
+		},
+		{
+			name:     "response id",
+			filePath: "/data/file.csv",
+			respond: func(packetType byte, id uint32, payload []byte) []byte {
+				if packetType != sftpPacketRead {
+					return nil
+				}
+				return sftpTestStatus(id+1, sftpStatusEOF, "EOF")
+			},
+			expected: "Unexpected SFTP response id",
+		},
+		{
+			name:     "empty data",
+			filePath: "/data/file.csv",
+			respond: func(packetType byte, id uint32, payload []byte) []byte {
+				if packetType != sftpPacketRead {
+					return nil
+				}
+				// The chunk is empty without reaching the end of the file
+				return sftpTestPacket(sftpPacketData, append(sftpUint32(id), sftpString("")...))
+			},
+			expected: "Invalid SFTP data length",
+		},
+		{
+			name:     "packet length",
+			filePath: "/data/file.csv",
+			respond: func(packetType byte, id uint32, payload []byte) []byte {
+				if packetType != sftpPacketRead {
+					return nil
+				}
+				// The length claims far more than the maximum length, which mustn't be allocated
+				return append(sftpUint32(0xffffffff), sftpPacketData)
+			},
+			expected: "Invalid SFTP packet length",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &sftpTestServer{files: map[string]string{"/data/file.csv": "id\n1\n"}, respond: tt.respond}
+			addr, config := server.start(t)
+
+			_, err := NewCSV(CSVOptions{}).FromSFTP(context.Background(), addr, tt.filePath, config)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("FromSFTP() error = %v, want %s", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package reader
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// googleSheetsURL is the CSV export endpoint of Google Sheets
+const googleSheetsURL = "https://docs.google.com/spreadsheets/d/"
+
+// FromGoogleSheet reads a tab of a Google Sheets spreadsheet through its CSV export
+// sheet is the name of the tab & cellRange an optional A1 range (ex: "A1:F"). The first row of the range is used as the header row
+// Private spreadsheets require the BearerToken option or an authorized HTTPClient
+func (c *csv) FromGoogleSheet(ctx context.Context, spreadsheetID string, sheet string, cellRange string) ([]map[string]string, error) {
+	query := url.Values{}
+	query.Set("tqx", "out:csv")
+	query.Set("headers", "1")
+	if sheet != "" {
+		query.Set("sheet", sheet)
+	}
+	if cellRange != "" {
+		query.Set("range", cellRange)
+	}
+
+	sheetURL := googleSheetsURL + url.PathEscape(spreadsheetID) + "/gviz/tq?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sheetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.prepareRequest(req); err != nil {
+		return nil, err
+	}
+
+	return c.getRecordsFromRequest(ctx, sheetURL, c.options.HTTPClient, req)
+}
@@ -0,0 +1,317 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	gocsv "encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffSize is the number of bytes read by Sniff
+const sniffSize = 64 * 1024
+
+// sniffLines is the maximum number of rows looked at by Sniff
+const sniffLines = 50
+
+// sniffDelimiters are the delimiters detected by Sniff, in order of preference
+var sniffDelimiters = []rune{',', ';', '\t', '|', ':'}
+
+// Dialect is the format of a CSV detected by Sniff
+// Delimiter is the field delimiter. Quote is the quote character of the quoted fields, or 0 if no field is quoted
+// Encoding is the name of the character encoding, which is accepted by the Encoding option (ex: "utf-8", "utf-16le", "windows-1252")
+// BOM is the byte order mark at the start of the CSV & Compressed is true for gzip or zstd compressed CSVs
+// Header is true when the first row looks like a header row rather than a record
+// LineTerminator is the line break of the rows (ex: "\n", "\r\n")
+type Dialect struct {
+	Delimiter      rune
+	Quote          rune
+	Encoding       string
+	BOM            BOM
+	Compressed     bool
+	Header         bool
+	LineTerminator string
+}
+
+// Options returns the reader options matching the dialect, based on options
+// Headerless CSVs still need their column names in the Headers option
+func (d Dialect) Options(options CSVOptions) CSVOptions {
+	options.Delimiter = d.Delimiter
+	options.Encoding = ""
+	// UTF-16 is detected from the BOM while reading
+	if d.Encoding != "utf-8" && d.BOM != BOMUTF16LE && d.BOM != BOMUTF16BE {
+		options.Encoding = d.Encoding
+	}
+	return options
+}
+
+// Sniff detects the dialect of the CSV read from src, from its first bytes, so that it can be previewed before it is read
+// Comment lines & the leading rows of SkipRows are ignored. The options set for the dialect (ex: Delimiter) aren't used
+func (c *csv) Sniff(ctx context.Context, src io.Reader) (Dialect, error) {
+	var dialect Dialect
+
+	buffered := bufio.NewReader(src)
+	magic, err := buffered.Peek(len(zstdMagic))
+	if err != nil && err != io.EOF {
+		return dialect, err
+	}
+	dialect.Compressed = isCompressed(magic)
+
+	data, err := decompress(buffered)
+	if err != nil {
+		return dialect, err
+	}
+	defer data.Close()
+
+	sample, err := io.ReadAll(io.LimitReader(data, sniffSize))
+	if err != nil {
+		return dialect, err
+	}
+	if err = ctx.Err(); err != nil {
+		return dialect, err
+	}
+	truncated := len(sample) == sniffSize
+
+	text, bom, err := stripBOM(bufio.NewReader(bytes.NewReader(sample)))
+	if err != nil {
+		return dialect, err
+	}
+	dialect.BOM = bom
+	switch bom {
+	case BOMUTF16LE:
+		dialect.Encoding = "utf-16le"
+	case BOMUTF16BE:
+		dialect.Encoding = "utf-16be"
+	default:
+		dialect.Encoding = "utf-8"
+		if !validUTF8(sample, truncated) {
+			// Windows-1252 is the most common single byte encoding, & a superset of ISO-8859-1 for the printable characters
+			dialect.Encoding = "windows-1252"
+			if text, err = decode(text, dialect.Encoding); err != nil {
+				return dialect, err
+			}
+		}
+	}
+
+	decoded, err := io.ReadAll(text)
+	if err != nil {
+		return dialect, err
+	}
+
+	rows := c.sniffRows(string(decoded), truncated)
+	dialect.LineTerminator = sniffLineTerminator(string(decoded))
+	dialect.Delimiter = sniffDelimiter(rows)
+	dialect.Quote = sniffQuote(rows, dialect.Delimiter)
+	dialect.Header = sniffHeader(rows, dialect.Delimiter)
+
+	return dialect, nil
+}
+
+// validUTF8 checks if the sample is UTF-8, ignoring the character which may be cut at its end
+func validUTF8(sample []byte, truncated bool) bool {
+	if truncated {
+		for i := 0; i < utf8.UTFMax-1 && len(sample) > 0; i++ {
+			if r, size := utf8.DecodeLastRune(sample); r != utf8.RuneError || size != 1 {
+				break
+			}
+			sample = sample[:len(sample)-1]
+		}
+	}
+	return utf8.Valid(sample)
+}
+
+// sniffRows splits the text into its rows, without the line breaks of the quoted fields
+// The last row of a truncated sample is incomplete, so it is left out
+func (c *csv) sniffRows(text string, truncated bool) []string {
+	var rows []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '"':
+			quoted = !quoted
+		case '\n':
+			if !quoted {
+				rows = append(rows, strings.TrimSuffix(text[start:i], "\r"))
+				start = i + 1
+			}
+		}
+	}
+	if start < len(text) && !truncated {
+		rows = append(rows, strings.TrimSuffix(text[start:], "\r"))
+	}
+
+	var kept []string
+	for i, row := range rows {
+		if i < c.options.SkipRows || row == "" {
+			continue
+		}
+		if c.options.Comment != 0 && strings.HasPrefix(row, string(c.options.Comment)) {
+			continue
+		}
+		kept = append(kept, row)
+		if len(kept) == sniffLines {
+			break
+		}
+	}
+	return kept
+}
+
+// sniffLineTerminator returns the first line break of the text
+func sniffLineTerminator(text string) string {
+	i := strings.IndexAny(text, "\r\n")
+	switch {
+	case i < 0:
+		return "\n"
+	case strings.HasPrefix(text[i:], "\r\n"):
+		return "\r\n"
+	default:
+		return text[i : i+1]
+	}
+}
+
+// sniffDelimiter returns the delimiter which splits the rows into the most consistent number of fields
+func sniffDelimiter(rows []string) rune {
+	best, bestConsistency, bestCount := ',', 0.0, 0
+	for _, delimiter := range sniffDelimiters {
+		frequencies := make(map[int]int)
+		for _, row := range rows {
+			frequencies[countDelimiters(row, delimiter)]++
+		}
+
+		// The most frequent number of delimiters per row, & the share of the rows having it
+		count, rowsWithCount := 0, 0
+		for n, frequency := range frequencies {
+			if frequency > rowsWithCount || (frequency == rowsWithCount && n > count) {
+				count, rowsWithCount = n, frequency
+			}
+		}
+		if count == 0 {
+			continue
+		}
+
+		consistency := float64(rowsWithCount) / float64(len(rows))
+		if consistency > bestConsistency || (consistency == bestConsistency && count > bestCount) {
+			best, bestConsistency, bestCount = delimiter, consistency, count
+		}
+	}
+	return best
+}
+
+// countDelimiters counts the delimiters of a row which aren't quoted
+func countDelimiters(row string, delimiter rune) int {
+	count := 0
+	quoted := false
+	for _, r := range row {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == delimiter && !quoted:
+			count++
+		}
+	}
+	return count
+}
+
+// sniffQuote returns the character which encloses fields, if any
+func sniffQuote(rows []string, delimiter rune) rune {
+	counts := map[rune]int{}
+	for _, row := range rows {
+		for _, field := range strings.Split(row, string(delimiter)) {
+			field = strings.TrimSpace(field)
+			if len(field) < 2 {
+				continue
+			}
+			for _, quote := range []rune{'"', '\''} {
+				if strings.HasPrefix(field, string(quote)) {
+					counts[quote]++
+				}
+			}
+		}
+	}
+
+	switch {
+	case counts['"'] > 0 && counts['"'] >= counts['\'']:
+		return '"'
+	case counts['\''] > 0:
+		return '\''
+	}
+	return 0
+}
+
+// sniffHeader checks if the first row is a header row
+// Every column votes for a header row when its first value doesn't look like the other ones (ex: text above numbers, or above values which all have the same length)
+func sniffHeader(rows []string, delimiter rune) bool {
+	reader := gocsv.NewReader(strings.NewReader(strings.Join(rows, "\n")))
+	reader.Comma = delimiter
+	reader.LazyQuotes = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil || len(records) == 0 {
+		return false
+	}
+
+	header := records[0]
+	if len(records) == 1 {
+		return isHeaderRow(header)
+	}
+
+	votes := 0
+	for i, name := range header {
+		numeric, length := true, -1
+		for _, record := range records[1:] {
+			if i >= len(record) {
+				continue
+			}
+			value := strings.TrimSpace(record[i])
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				numeric = false
+			}
+			switch {
+			case length == -1:
+				length = utf8.RuneCountInString(value)
+			case length != utf8.RuneCountInString(value):
+				length = -2
+			}
+		}
+
+		switch {
+		case numeric:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(name), 64); err != nil {
+				votes++
+			} else {
+				votes--
+			}
+		case length >= 0:
+			if utf8.RuneCountInString(strings.TrimSpace(name)) != length {
+				votes++
+			} else {
+				votes--
+			}
+		}
+	}
+
+	if votes == 0 {
+		return isHeaderRow(header)
+	}
+	return votes > 0
+}
+
+// isHeaderRow checks if a row could be column names: unique, non empty & non numeric values
+func isHeaderRow(row []string) bool {
+	seen := make(map[string]bool, len(row))
+	for _, name := range row {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			return false
+		}
+		if _, err := strconv.ParseFloat(name, 64); err == nil {
+			return false
+		}
+		seen[name] = true
+	}
+	return true
+}
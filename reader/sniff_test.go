@@ -0,0 +1,112 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		options  CSVOptions
+		expected Dialect
+	}{
+		{
+			name:     "comma",
+			data:     []byte("id,name\n1,a\n2,b\n"),
+			expected: Dialect{Delimiter: ',', Encoding: "utf-8", Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "quoted semicolons",
+			data:     []byte("\"id\";\"name\"\r\n\"1\";\"a;b\"\r\n\"2\";\"c\"\r\n"),
+			expected: Dialect{Delimiter: ';', Quote: '"', Encoding: "utf-8", Header: true, LineTerminator: "\r\n"},
+		},
+		{
+			name:     "tab without header",
+			data:     []byte("1\t2\n3\t4\n"),
+			expected: Dialect{Delimiter: '\t', Encoding: "utf-8", LineTerminator: "\n"},
+		},
+		{
+			name:     "pipe with UTF-8 BOM",
+			data:     []byte("\xEF\xBB\xBFname|code\nalice|12\nbob|34\n"),
+			expected: Dialect{Delimiter: '|', Encoding: "utf-8", BOM: BOMUTF8, Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "quoted line breaks",
+			data:     []byte("id,note\n1,\"a\nb;c;d\"\n2,e\n"),
+			expected: Dialect{Delimiter: ',', Quote: '"', Encoding: "utf-8", Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "comments & skipped rows",
+			data:     []byte("title, exported\n# a|b|c\nid;name\n1;a\n2;b\n"),
+			options:  CSVOptions{Comment: '#', SkipRows: 1},
+			expected: Dialect{Delimiter: ';', Encoding: "utf-8", Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "windows-1252",
+			data:     []byte("nom,ville\ncaf\xe9,Paris\n"),
+			expected: Dialect{Delimiter: ',', Encoding: "windows-1252", Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "UTF-16LE",
+			data:     append([]byte{0xFF, 0xFE}, utf16Data(utf16.Encode([]rune("id;name\n1;é\n")), false)...),
+			expected: Dialect{Delimiter: ';', Encoding: "utf-16le", BOM: BOMUTF16LE, Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "gzip",
+			data:     gzipData(t, "id,name\n1,a\n"),
+			expected: Dialect{Delimiter: ',', Encoding: "utf-8", Compressed: true, Header: true, LineTerminator: "\n"},
+		},
+		{
+			name:     "empty",
+			data:     nil,
+			expected: Dialect{Delimiter: ',', Encoding: "utf-8", LineTerminator: "\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, err := NewCSV(tt.options).Sniff(context.Background(), bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(dialect, tt.expected) {
+				t.Errorf("Sniff() = %+v, want %+v", dialect, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSniffRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected []map[string]string
+	}{
+		{name: "semicolons", data: []byte("id;name\n1;a\n"), expected: []map[string]string{{"id": "1", "name": "a"}}},
+		{name: "windows-1252", data: []byte("id\tname\n1\tcaf\xe9\n"), expected: []map[string]string{{"id": "1", "name": "café"}}},
+		{name: "UTF-16BE", data: append([]byte{0xFE, 0xFF}, utf16Data(utf16.Encode([]rune("id|name\n1|é\n")), true)...), expected: []map[string]string{{"id": "1", "name": "é"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dialect, err := NewCSV(CSVOptions{}).Sniff(context.Background(), bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The records are read with the options of the dialect
+			records, err := NewCSV(dialect.Options(CSVOptions{})).FromReader(context.Background(), bytes.NewReader(tt.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("FromReader() with the dialect options = %v, want %v", records, tt.expected)
+			}
+		})
+	}
+}
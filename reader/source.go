@@ -0,0 +1,129 @@
+package reader
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Source is a location the data of a file is read from (ex: a local file, a URL or an object store)
+// Storages which aren't supported by the package can be read by implementing it
+type Source interface {
+	Open(ctx context.Context) (io.ReadCloser, error)
+}
+
+// NamedSource is a Source with a name (ex: its path or URL), which is used as the value of the SourceColumn & to detect the format of the data
+type NamedSource interface {
+	Source
+	Name() string
+}
+
+// FileSource returns the source of a local file
+func FileSource(filePath string) NamedSource {
+	return &fileSource{path: filePath}
+}
+
+// FSSource returns the source of a file of a fs.FS (ex: embed.FS)
+func FSSource(fsys fs.FS, filePath string) NamedSource {
+	return &fileSource{fsys: fsys, path: filePath}
+}
+
+// URLSource returns the source of a URL
+// The HTTP options (ex: HTTPClient, Retry, BearerToken, MaxResumes) apply to its requests
+func URLSource(url string, options CSVOptions) NamedSource {
+	c := NewCSV(options).(*csv)
+	return &requestSource{
+		csv:    c,
+		name:   url,
+		client: c.options.HTTPClient,
+		newRequest: func(ctx context.Context) (*http.Request, error) {
+			return c.newURLRequest(ctx, url)
+		},
+	}
+}
+
+// S3Source returns the source of an S3 object, read with the S3 & HTTP options
+func S3Source(bucket string, key string, options CSVOptions) NamedSource {
+	return NewCSV(options).(*csv).s3Source(bucket, key)
+}
+
+// GCSSource returns the source of a Cloud Storage object addressed as gs://bucket/object, read with the GCS & HTTP options
+func GCSSource(uri string, options CSVOptions) NamedSource {
+	return NewCSV(options).(*csv).gcsSource(uri)
+}
+
+// FTPSource returns the source of a file on an FTP server, read with the FTP options
+func FTPSource(addr string, filePath string, options CSVOptions) NamedSource {
+	return NewCSV(options).(*csv).ftpSource(addr, filePath)
+}
+
+// SFTPSource returns the source of a file on an SFTP server
+// config holds the user, authentication methods & host key verification used for the SSH connection
+func SFTPSource(addr string, filePath string, config *ssh.ClientConfig) NamedSource {
+	return &sftpSource{addr: addr, filePath: filePath, config: config}
+}
+
+type fileSource struct {
+	fsys fs.FS
+	path string
+	// mapped reads the file through a memory mapping, see MemoryMap
+	mapped bool
+}
+
+func (s *fileSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	if s.fsys != nil {
+		return s.fsys.Open(s.path)
+	}
+	if s.mapped {
+		return openMapped(s.path)
+	}
+	return os.Open(s.path)
+}
+
+func (s *fileSource) Name() string {
+	return s.path
+}
+
+// requestSource is the body of the response of an HTTP request, which is retried & resumed with the HTTP options
+type requestSource struct {
+	csv        *csv
+	name       string
+	client     *http.Client
+	newRequest func(ctx context.Context) (*http.Request, error)
+}
+
+func (s *requestSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.csv.openRequest(ctx, s.client, req)
+}
+
+func (s *requestSource) Name() string {
+	return s.name
+}
+
+// sourceName returns the name of a source, if it has one
+func sourceName(src Source) string {
+	if named, ok := src.(NamedSource); ok {
+		return named.Name()
+	}
+	return ""
+}
+
+// FromSource reads the CSV from a source
+func (c *csv) FromSource(ctx context.Context, src Source) ([]map[string]string, error) {
+	body, err := src.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return c.getRecords(ctx, sourceName(src), body)
+}
@@ -0,0 +1,47 @@
+package reader
+
+import (
+	"context"
+	"io"
+)
+
+// SpreadsheetOptions consists of the spreadsheet reader options available
+// CSVOptions apply to the rows of the sheet, except for the ones specific to delimited text (ex: Delimiter, Encoding). Short rows are always padded, since spreadsheets omit the empty trailing cells
+// Sheet is the name of the sheet to read. When empty, the sheet at SheetIndex is read
+// SheetIndex is the position (0-indexed) of the sheet to read. Default value is 0
+type SpreadsheetOptions struct {
+	CSVOptions
+	Sheet      string
+	SheetIndex int
+}
+
+// Spreadsheet is a lightweight interface for reading spreadsheet files
+// The first row of the sheet is used as the header row
+type Spreadsheet interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, filePath string) (RecordIterator, error)
+}
+
+// newSpreadsheetReader returns the csv reader used to build the records of the sheets
+func newSpreadsheetReader(options SpreadsheetOptions) *csv {
+	options.PadShortRows = true
+	return NewCSV(options.CSVOptions).(*csv)
+}
+
+// readAll reads all the records of the iterator
+func readAll(it RecordIterator) ([]map[string]string, error) {
+	defer it.Close()
+
+	var lines []map[string]string
+	for {
+		record, err := it.Next()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, record)
+	}
+}
@@ -0,0 +1,76 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// zipTestFile returns a zip archive of files, by name
+func zipTestFile(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return archive.Bytes()
+}
+
+// xlsxTestFile returns an xlsx file with a sheet named Sheet1 holding sheetData, along with the other files of the archive (ex: xl/sharedStrings.xml)
+func xlsxTestFile(t *testing.T, sheetData string, files map[string]string) []byte {
+	t.Helper()
+
+	archive := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + sheetData + `</sheetData></worksheet>`,
+	}
+	for name, content := range files {
+		archive[name] = content
+	}
+	return zipTestFile(t, archive)
+}
+
+// xlsxTestRow returns a row of inline string cells
+func xlsxTestRow(values ...string) string {
+	var row strings.Builder
+	row.WriteString("<row>")
+	for _, value := range values {
+		row.WriteString(`<c t="inlineStr"><is><t>` + value + `</t></is></c>`)
+	}
+	row.WriteString("</row>")
+	return row.String()
+}
+
+// odsTestFile returns an ods file with the tables of content.xml
+func odsTestFile(t *testing.T, tables string) []byte {
+	t.Helper()
+
+	return zipTestFile(t, map[string]string{
+		"mimetype":    "application/vnd.oasis.opendocument.spreadsheet",
+		"content.xml": `<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"><office:body><office:spreadsheet>` + tables + `</office:spreadsheet></office:body></office:document-content>`,
+	})
+}
+
+// odsTestRow returns a row of string cells
+func odsTestRow(values ...string) string {
+	var row strings.Builder
+	row.WriteString("<table:table-row>")
+	for _, value := range values {
+		row.WriteString(`<table:table-cell office:value-type="string"><text:p>` + value + `</text:p></table:table-cell>`)
+	}
+	row.WriteString("</table:table-row>")
+	return row.String()
+}
@@ -0,0 +1,130 @@
+package reader
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strconv"
+	"time"
+)
+
+// SQLOptions consists of the SQL reader options available
+// CSVOptions apply to the rows of the query, except for the ones specific to text (ex: Delimiter, Encoding). Headers are always the column names
+type SQLOptions struct {
+	CSVOptions
+}
+
+// SQLQueryer executes queries, such as *sql.DB, *sql.Tx & *sql.Conn
+type SQLQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// SQL is a lightweight interface for reading the rows of database queries
+// Values are formatted like in a CSV: timestamps as RFC3339 & nulls as empty strings
+type SQL interface {
+	FromQuery(ctx context.Context, db SQLQueryer, query string, args ...interface{}) ([]map[string]string, error)
+	Open(ctx context.Context, db SQLQueryer, query string, args ...interface{}) (RecordIterator, error)
+}
+
+type sqlReader struct {
+	options SQLOptions
+}
+
+// FromQuery executes a query & reads its rows
+func (s *sqlReader) FromQuery(ctx context.Context, db SQLQueryer, query string, args ...interface{}) ([]map[string]string, error) {
+	it, err := s.Open(ctx, db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open executes a query & returns an iterator over its rows
+// The rows are fetched as they are read, so the iterator should be closed to release the connection
+func (s *sqlReader) Open(ctx context.Context, db SQLQueryer, query string, args ...interface{}) (RecordIterator, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	options := s.options.CSVOptions
+	options.Headers = columns
+
+	return NewCSV(options).(*csv).newRowIterator(ctx, "", &sqlRowReader{rows: rows, columns: len(columns)}, []io.Closer{rows})
+}
+
+// sqlRowReader formats the values of the rows of a query
+type sqlRowReader struct {
+	rows    *sql.Rows
+	columns int
+	line    int
+}
+
+func (r *sqlRowReader) Read() ([]string, error) {
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	values := make([]interface{}, r.columns)
+	pointers := make([]interface{}, r.columns)
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := r.rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+	r.line++
+
+	fields := make([]string, r.columns)
+	for i, value := range values {
+		fields[i] = formatSQLValue(value)
+	}
+	return fields, nil
+}
+
+func (r *sqlRowReader) Line() int {
+	return r.line
+}
+
+// formatSQLValue formats the values of the types returned by the drivers
+func formatSQLValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return formatFloat(v, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+
+	// Some drivers return their own types, which usually describe themselves
+	if s, ok := value.(interface{ String() string }); ok {
+		return s.String()
+	}
+	return ""
+}
+
+// NewSQL is the initialization method for the SQL reader
+func NewSQL(options SQLOptions) SQL {
+	return &sqlReader{
+		options: options,
+	}
+}
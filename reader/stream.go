@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"context"
+	"io"
+)
+
+// Record is a single CSV record emitted by Stream
+// Line is the line number of the record in the CSV & Source the path or URL it was read from, if any
+type Record struct {
+	Values map[string]string
+	Line   int
+	Source string
+}
+
+// Stream reads the CSV from src in the background and emits the records on a bounded channel
+// Both channels are closed once the read is complete. At most one error is sent on the error channel
+func (c *csv) Stream(ctx context.Context, src io.Reader) (<-chan Record, <-chan error) {
+	records := make(chan Record, c.options.StreamBufferSize)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(records)
+		defer close(errs)
+
+		it, err := c.newRecordIterator(ctx, "", src)
+		if err != nil {
+			errs <- err
+			return
+		}
+		defer it.Close()
+
+		for {
+			values, err := it.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case records <- Record{Values: values, Line: it.Line(), Source: it.source}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return records, errs
+}
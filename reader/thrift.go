@@ -0,0 +1,238 @@
+package reader
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// Thrift compact protocol types
+const (
+	thriftStop   = 0
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI16    = 4
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftDouble = 7
+	thriftBinary = 8
+	thriftList   = 9
+	thriftSet    = 10
+	thriftMap    = 11
+	thriftStruct = 12
+)
+
+// thriftMaxDepth is the maximum nesting of the decoded structs & lists
+const thriftMaxDepth = 64
+
+var errInvalidThrift = errors.New("Invalid Thrift data")
+
+// thriftFields are the fields of a decoded Thrift struct, by field id
+// Integers are decoded as int64, binaries as []byte, lists & sets as []interface{} and structs as thriftFields. Maps are skipped
+type thriftFields map[int16]interface{}
+
+func (f thriftFields) has(id int16) bool {
+	_, ok := f[id]
+	return ok
+}
+
+func (f thriftFields) int(id int16) int64 {
+	v, _ := f[id].(int64)
+	return v
+}
+
+func (f thriftFields) bool(id int16, def bool) bool {
+	v, ok := f[id].(bool)
+	if !ok {
+		return def
+	}
+	return v
+}
+
+func (f thriftFields) string(id int16) string {
+	v, _ := f[id].([]byte)
+	return string(v)
+}
+
+func (f thriftFields) fields(id int16) thriftFields {
+	v, _ := f[id].(thriftFields)
+	return v
+}
+
+func (f thriftFields) list(id int16) []interface{} {
+	v, _ := f[id].([]interface{})
+	return v
+}
+
+// thriftDecoder decodes the Thrift compact protocol, which is used by the Parquet metadata
+type thriftDecoder struct {
+	data []byte
+	pos  int
+}
+
+// readStruct decodes a struct & returns its fields
+func (d *thriftDecoder) readStruct() (thriftFields, error) {
+	return d.readFields(0)
+}
+
+func (d *thriftDecoder) readFields(depth int) (thriftFields, error) {
+	if depth > thriftMaxDepth {
+		return nil, errInvalidThrift
+	}
+
+	fields := make(thriftFields)
+	var id int16
+	for {
+		header, err := d.readByte()
+		if err != nil {
+			return nil, err
+		}
+		if header == thriftStop {
+			return fields, nil
+		}
+
+		// The field id is either a delta from the previous one or follows the header
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			v, err := d.readVarint()
+			if err != nil {
+				return nil, err
+			}
+			id = int16(v)
+		}
+
+		fieldType := header & 0x0f
+		switch fieldType {
+		case thriftTrue:
+			fields[id] = true
+		case thriftFalse:
+			fields[id] = false
+		default:
+			if fields[id], err = d.readValue(fieldType, depth); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (d *thriftDecoder) readValue(valueType byte, depth int) (interface{}, error) {
+	switch valueType {
+	case thriftTrue, thriftFalse:
+		// Booleans of lists are single bytes
+		b, err := d.readByte()
+		return b == thriftTrue, err
+	case thriftByte:
+		b, err := d.readByte()
+		return int64(int8(b)), err
+	case thriftI16, thriftI32, thriftI64:
+		return d.readVarint()
+	case thriftDouble:
+		if len(d.data)-d.pos < 8 {
+			return nil, errInvalidThrift
+		}
+		v := math.Float64frombits(binary.LittleEndian.Uint64(d.data[d.pos:]))
+		d.pos += 8
+		return v, nil
+	case thriftBinary:
+		return d.readBinary()
+	case thriftList, thriftSet:
+		return d.readList(depth + 1)
+	case thriftMap:
+		return nil, d.skipMap(depth + 1)
+	case thriftStruct:
+		return d.readFields(depth + 1)
+	}
+	return nil, errInvalidThrift
+}
+
+func (d *thriftDecoder) readList(depth int) ([]interface{}, error) {
+	header, err := d.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(header >> 4)
+	if size == 15 {
+		v, err := d.readUvarint()
+		if err != nil {
+			return nil, err
+		}
+		// Every element takes at least a byte
+		if v > uint64(len(d.data)-d.pos) {
+			return nil, errInvalidThrift
+		}
+		size = int(v)
+	}
+
+	list := make([]interface{}, size)
+	for i := range list {
+		if list[i], err = d.readValue(header&0x0f, depth); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+func (d *thriftDecoder) skipMap(depth int) error {
+	size, err := d.readUvarint()
+	if err != nil || size == 0 {
+		return err
+	}
+	if size > uint64(len(d.data)-d.pos) {
+		return errInvalidThrift
+	}
+
+	types, err := d.readByte()
+	if err != nil {
+		return err
+	}
+	for i := uint64(0); i < size; i++ {
+		if _, err = d.readValue(types>>4, depth); err != nil {
+			return err
+		}
+		if _, err = d.readValue(types&0x0f, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *thriftDecoder) readBinary() ([]byte, error) {
+	size, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+	if size > uint64(len(d.data)-d.pos) {
+		return nil, errInvalidThrift
+	}
+
+	b := d.data[d.pos : d.pos+int(size)]
+	d.pos += int(size)
+	return b, nil
+}
+
+func (d *thriftDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errInvalidThrift
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+// readVarint reads a zigzag encoded integer
+func (d *thriftDecoder) readVarint() (int64, error) {
+	v, err := d.readUvarint()
+	return int64(v>>1) ^ -int64(v&1), err
+}
+
+func (d *thriftDecoder) readUvarint() (uint64, error) {
+	v, n := binary.Uvarint(d.data[d.pos:])
+	if n <= 0 {
+		return 0, errInvalidThrift
+	}
+	d.pos += n
+	return v, nil
+}
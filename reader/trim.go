@@ -0,0 +1,40 @@
+package reader
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TrimPolicy is the handling of the whitespace around the values
+type TrimPolicy int
+
+// Trim policies
+// TrimSpace removes the leading & trailing whitespace. This is the default policy
+// TrimNone keeps the values as they are, for the fields where the whitespace is significant
+// TrimLeading removes the leading whitespace only
+// TrimTrailing removes the trailing whitespace only
+const (
+	TrimSpace TrimPolicy = iota
+	TrimNone
+	TrimLeading
+	TrimTrailing
+)
+
+// trim applies the trim policy of a column to one of its values
+func (it *recordIterator) trim(key string, value string) string {
+	policy := it.options.Trim
+	if columnPolicy, ok := it.options.ColumnTrim[key]; ok {
+		policy = columnPolicy
+	}
+
+	switch policy {
+	case TrimNone:
+		return value
+	case TrimLeading:
+		return strings.TrimLeftFunc(value, unicode.IsSpace)
+	case TrimTrailing:
+		return strings.TrimRightFunc(value, unicode.IsSpace)
+	default:
+		return strings.TrimSpace(value)
+	}
+}
@@ -0,0 +1,49 @@
+package reader
+
+import (
+	"context"
+	"sync"
+)
+
+// FromURLs reads the CSVs of several urls in parallel, with at most concurrency requests at a time
+// The records of the successful reads are combined in the order of the urls. The failed reads are returned by url
+func (c *csv) FromURLs(ctx context.Context, urls []string, concurrency int) ([]map[string]string, map[string]error) {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([][]map[string]string, len(urls))
+	errs := make([]error, len(urls))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, concurrency)
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-semaphore }()
+
+			results[i], errs[i] = c.FromURL(ctx, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	var lines []map[string]string
+	urlErrs := make(map[string]error)
+	for i, url := range urls {
+		if errs[i] != nil {
+			urlErrs[url] = errs[i]
+			continue
+		}
+		lines = append(lines, results[i]...)
+	}
+
+	return lines, urlErrs
+}
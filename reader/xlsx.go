@@ -0,0 +1,422 @@
+package reader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type xlsx struct {
+	csv     *csv
+	options SpreadsheetOptions
+}
+
+type xlsxWorkbook struct {
+	Properties struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText is a plain or rich text string. Phonetic runs are ignored
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+
+	var b strings.Builder
+	b.WriteString(t.Text)
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type xlsxRow struct {
+	Number int        `xml:"r,attr"`
+	Cells  []xlsxCell `xml:"c"`
+}
+
+type xlsxCell struct {
+	Ref    string        `xml:"r,attr"`
+	Type   string        `xml:"t,attr"`
+	Style  int           `xml:"s,attr"`
+	Value  string        `xml:"v"`
+	Inline *xlsxRichText `xml:"is"`
+}
+
+// FromPath reads the sheet of an xlsx file
+func (x *xlsx) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	it, err := x.Open(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads the sheet of an xlsx file from an io.Reader
+// The file is buffered in memory, since xlsx files are zip archives
+func (x *xlsx) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	data, err := readLimited(r, x.options.MaxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	it, err := x.open(ctx, "", archive, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the sheet of an xlsx file
+func (x *xlsx) Open(ctx context.Context, filePath string) (RecordIterator, error) {
+	archive, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.open(ctx, filePath, &archive.Reader, archive)
+}
+
+func (x *xlsx) open(ctx context.Context, source string, archive *zip.Reader, closer io.Closer) (*recordIterator, error) {
+	closers := []io.Closer{}
+	if closer != nil {
+		closers = append(closers, closer)
+	}
+
+	rows, err := x.openSheet(archive)
+	if err != nil {
+		closeAll(closers)
+		return nil, err
+	}
+
+	return x.csv.newRowIterator(ctx, source, rows, append(closers, rows))
+}
+
+func (x *xlsx) openSheet(archive *zip.Reader) (*xlsxRowReader, error) {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var workbook xlsxWorkbook
+	if err := xlsxDecode(files, "xl/workbook.xml", &workbook, x.options.MaxBytes); err != nil {
+		return nil, err
+	}
+	var relationships xlsxRelationships
+	if err := xlsxDecode(files, "xl/_rels/workbook.xml.rels", &relationships, x.options.MaxBytes); err != nil {
+		return nil, err
+	}
+
+	// Find the relationship id of the sheet
+	relationshipID := ""
+	for i, sheet := range workbook.Sheets {
+		if (x.options.Sheet == "" && i == x.options.SheetIndex) || (x.options.Sheet != "" && sheet.Name == x.options.Sheet) {
+			for _, attr := range sheet.Attrs {
+				if attr.Name.Local == "id" {
+					relationshipID = attr.Value
+				}
+			}
+			break
+		}
+	}
+	if relationshipID == "" {
+		return nil, errors.New("Sheet not found in the xlsx file")
+	}
+
+	sheetPath := ""
+	for _, relationship := range relationships.Relationships {
+		if relationship.ID != relationshipID {
+			continue
+		}
+		if strings.HasPrefix(relationship.Target, "/") {
+			sheetPath = path.Clean(strings.TrimPrefix(relationship.Target, "/"))
+		} else {
+			sheetPath = path.Join("xl", relationship.Target)
+		}
+	}
+	sheetFile, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New("Sheet not found in the xlsx file")
+	}
+
+	// Shared strings & styles are optional
+	var sharedStrings xlsxSharedStrings
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := xlsxDecode(files, "xl/sharedStrings.xml", &sharedStrings, x.options.MaxBytes); err != nil {
+			return nil, err
+		}
+	}
+	var styles xlsxStyles
+	if _, ok := files["xl/styles.xml"]; ok {
+		if err := xlsxDecode(files, "xl/styles.xml", &styles, x.options.MaxBytes); err != nil {
+			return nil, err
+		}
+	}
+
+	sheet, err := sheetFile.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := &xlsxRowReader{
+		sheet:         sheet,
+		decoder:       xml.NewDecoder(limitReader(sheet, x.options.MaxBytes)),
+		sharedStrings: make([]string, len(sharedStrings.Items)),
+		dateStyles:    xlsxDateStyles(styles),
+		epoch:         time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC),
+	}
+	for i, item := range sharedStrings.Items {
+		rows.sharedStrings[i] = item.String()
+	}
+	if workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true" {
+		rows.epoch = time.Date(1904, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return rows, nil
+}
+
+// xlsxRowReader streams the rows of a sheet
+type xlsxRowReader struct {
+	sheet         io.ReadCloser
+	decoder       *xml.Decoder
+	sharedStrings []string
+	dateStyles    map[int]bool
+	epoch         time.Time
+	line          int
+}
+
+func (r *xlsxRowReader) Read() ([]string, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRow
+		if err = r.decoder.DecodeElement(&row, &start); err != nil {
+			return nil, err
+		}
+
+		if row.Number == 0 {
+			row.Number = r.line + 1
+		}
+		r.line = row.Number
+
+		// Empty rows are skipped like the empty lines of a CSV
+		values := r.rowValues(row)
+		if len(values) == 0 {
+			continue
+		}
+		return values, nil
+	}
+}
+
+func (r *xlsxRowReader) Line() int {
+	return r.line
+}
+
+func (r *xlsxRowReader) Close() error {
+	return r.sheet.Close()
+}
+
+func (r *xlsxRowReader) rowValues(row xlsxRow) []string {
+	var values []string
+	column := -1
+	for _, cell := range row.Cells {
+		if index, ok := xlsxColumnIndex(cell.Ref); ok {
+			column = index
+		} else {
+			column++
+		}
+
+		for len(values) <= column {
+			values = append(values, "")
+		}
+		values[column] = r.cellValue(cell)
+	}
+
+	// Formatted empty cells are not part of the data
+	for len(values) > 0 && values[len(values)-1] == "" {
+		values = values[:len(values)-1]
+	}
+
+	return values
+}
+
+func (r *xlsxRowReader) cellValue(cell xlsxCell) string {
+	switch cell.Type {
+	case "s":
+		index, err := strconv.Atoi(cell.Value)
+		if err != nil || index < 0 || index >= len(r.sharedStrings) {
+			return ""
+		}
+		return r.sharedStrings[index]
+	case "inlineStr":
+		if cell.Inline == nil {
+			return ""
+		}
+		return cell.Inline.String()
+	case "b":
+		return strconv.FormatBool(cell.Value == "1")
+	case "str", "e", "d":
+		return cell.Value
+	}
+
+	if !r.dateStyles[cell.Style] || cell.Value == "" {
+		return cell.Value
+	}
+
+	// Dates are stored as the number of days since the epoch of the workbook
+	serial, err := strconv.ParseFloat(cell.Value, 64)
+	if err != nil {
+		return cell.Value
+	}
+	seconds := math.Round(serial * 24 * 60 * 60)
+	return r.epoch.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339)
+}
+
+// xlsxColumnIndex returns the column index (0-indexed) of a cell reference such as "AB12"
+func xlsxColumnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' || letters == 3 {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	// Excel sheets have at most 16384 columns (XFD)
+	if letters == 0 || index > 16384 {
+		return 0, false
+	}
+	return index - 1, true
+}
+
+// xlsxDateStyles returns the styles (cell format indexes) which format numbers as dates
+func xlsxDateStyles(styles xlsxStyles) map[int]bool {
+	customFormats := make(map[int]string, len(styles.NumFmts))
+	for _, numFmt := range styles.NumFmts {
+		customFormats[numFmt.ID] = numFmt.Code
+	}
+
+	dateStyles := make(map[int]bool)
+	for i, xf := range styles.CellXfs {
+		if code, ok := customFormats[xf.NumFmtID]; ok {
+			dateStyles[i] = isDateFormat(code)
+			continue
+		}
+		// Built-in date & time formats
+		id := xf.NumFmtID
+		dateStyles[i] = (id >= 14 && id <= 22) || (id >= 27 && id <= 36) || (id >= 45 && id <= 47) || (id >= 50 && id <= 58)
+	}
+
+	return dateStyles
+}
+
+// isDateFormat checks if a number format code contains date or time parts
+func isDateFormat(code string) bool {
+	inQuotes := false
+	inBrackets := false
+	for i := 0; i < len(code); i++ {
+		ch := code[i]
+		switch {
+		case ch == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case ch == '\\':
+			i++
+		case ch == '[':
+			inBrackets = true
+		case ch == ']':
+			inBrackets = false
+		case inBrackets:
+		case strings.IndexByte("dDmMyYhHsS", ch) >= 0:
+			return true
+		}
+	}
+	return false
+}
+
+// xlsxDecode decodes an XML file of the archive, up to maxBytes of decompressed XML when it is set
+func xlsxDecode(files map[string]*zip.File, name string, v interface{}, maxBytes int64) error {
+	file, ok := files[name]
+	if !ok {
+		return errors.New("Invalid xlsx file: missing " + name)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return xml.NewDecoder(limitReader(reader, maxBytes)).Decode(v)
+}
+
+func closeAll(closers []io.Closer) {
+	for i := len(closers) - 1; i >= 0; i-- {
+		closers[i].Close()
+	}
+}
+
+// NewXLSX is the initialization method for the xlsx reader
+func NewXLSX(options SpreadsheetOptions) Spreadsheet {
+	return &xlsx{
+		csv:     newSpreadsheetReader(options),
+		options: options,
+	}
+}
@@ -0,0 +1,154 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestXLSXFromReader(t *testing.T) {
+	twoSheets := map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/><sheet name="Other" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="/xl/worksheets/other.xml"/></Relationships>`,
+		"xl/worksheets/other.xml":    `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + xlsxTestRow("sheet") + xlsxTestRow("other") + `</sheetData></worksheet>`,
+	}
+	// Style 1 is a built-in date format, style 2 a custom date & time format & style 3 a custom number format
+	styles := map[string]string{
+		"xl/styles.xml": `<styleSheet><numFmts><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/><numFmt numFmtId="165" formatCode="&quot;day&quot; 0.00"/></numFmts><cellXfs><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs></styleSheet>`,
+	}
+	date1904 := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><workbookPr date1904="1"/><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/styles.xml":   styles["xl/styles.xml"],
+	}
+
+	tests := []struct {
+		name      string
+		sheetData string
+		files     map[string]string
+		options   SpreadsheetOptions
+		expected  []map[string]string
+		err       string
+	}{
+		{
+			name:      "inline strings",
+			sheetData: xlsxTestRow("id", "name") + xlsxTestRow("1", "a") + xlsxTestRow("2", "b"),
+			expected:  []map[string]string{{"id": "1", "name": "a"}, {"id": "2", "name": "b"}},
+		},
+		{
+			name:      "shared strings",
+			sheetData: `<row><c t="s"><v>0</v></c><c t="s"><v>1</v></c></row><row><c t="s"><v>2</v></c><c t="s"><v>9</v></c></row>`,
+			files:     map[string]string{"xl/sharedStrings.xml": `<sst><si><t>id</t></si><si><t>name</t></si><si><r><t>rich </t></r><r><t>text</t></r></si></sst>`},
+			expected:  []map[string]string{{"id": "rich text", "name": ""}},
+		},
+		{
+			name:      "value types",
+			sheetData: xlsxTestRow("number", "bool", "formula", "error") + `<row><c><v>3.5</v></c><c t="b"><v>1</v></c><c t="str"><v>sum</v></c><c t="e"><v>#DIV/0!</v></c></row>`,
+			expected:  []map[string]string{{"number": "3.5", "bool": "true", "formula": "sum", "error": "#DIV/0!"}},
+		},
+		{
+			name:      "date styles",
+			sheetData: xlsxTestRow("plain", "date", "time", "number") + `<row><c><v>45000</v></c><c s="1"><v>45000</v></c><c s="2"><v>45000.5</v></c><c s="3"><v>45000</v></c></row>`,
+			files:     styles,
+			expected:  []map[string]string{{"plain": "45000", "date": "2023-03-15T00:00:00Z", "time": "2023-03-15T12:00:00Z", "number": "45000"}},
+		},
+		{
+			name:      "1904 dates",
+			sheetData: xlsxTestRow("date") + `<row><c s="1"><v>0</v></c></row>`,
+			files:     date1904,
+			expected:  []map[string]string{{"date": "1904-01-01T00:00:00Z"}},
+		},
+		{
+			name:      "sparse cells",
+			sheetData: `<row r="1"><c r="A1" t="inlineStr"><is><t>id</t></is></c><c r="B1" t="inlineStr"><is><t>note</t></is></c><c r="C1" t="inlineStr"><is><t>name</t></is></c></row><row r="3"><c r="A3"><v>1</v></c><c r="C3" t="inlineStr"><is><t>a</t></is></c><c r="D3" s="0"/></row><row r="4"/><row r="5"><c r="A5"><v>2</v></c></row>`,
+			expected:  []map[string]string{{"id": "1", "note": "", "name": "a"}, {"id": "2", "note": "", "name": ""}},
+		},
+		{
+			name:      "sheet index",
+			sheetData: xlsxTestRow("sheet") + xlsxTestRow("first"),
+			files:     twoSheets,
+			options:   SpreadsheetOptions{SheetIndex: 1},
+			expected:  []map[string]string{{"sheet": "other"}},
+		},
+		{
+			name:      "sheet name",
+			sheetData: xlsxTestRow("sheet") + xlsxTestRow("first"),
+			files:     twoSheets,
+			options:   SpreadsheetOptions{Sheet: "Other"},
+			expected:  []map[string]string{{"sheet": "other"}},
+		},
+		{
+			name:      "missing sheet",
+			sheetData: xlsxTestRow("sheet"),
+			options:   SpreadsheetOptions{Sheet: "Other"},
+			err:       "Sheet not found in the xlsx file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := xlsxTestFile(t, tt.sheetData, tt.files)
+			records, err := NewXLSX(tt.options).FromReader(context.Background(), bytes.NewReader(data))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Errorf("FromReader() error = %v, want %s", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("FromReader() = %v, want %v", records, tt.expected)
+			}
+		})
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected int
+		ok       bool
+	}{
+		{ref: "A1", expected: 0, ok: true},
+		{ref: "Z10", expected: 25, ok: true},
+		{ref: "AB12", expected: 27, ok: true},
+		{ref: "XFD1", expected: 16383, ok: true},
+		{ref: "XFE1", ok: false},
+		{ref: "12", ok: false},
+		{ref: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			index, ok := xlsxColumnIndex(tt.ref)
+			if ok != tt.ok || (ok && index != tt.expected) {
+				t.Errorf("xlsxColumnIndex() = %d, %v, want %d, %v", index, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestIsDateFormat(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected bool
+	}{
+		{code: "yyyy-mm-dd", expected: true},
+		{code: "h:mm AM/PM", expected: true},
+		{code: "0.00", expected: false},
+		{code: `"days" 0`, expected: false},
+		{code: `\d 0`, expected: false},
+		{code: "[Red]0.00", expected: false},
+		{code: "[$-409]mmm d", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if isDateFormat(tt.code) != tt.expected {
+				t.Errorf("isDateFormat() = %v, want %v", !tt.expected, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package reader
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// XMLOptions consists of the XML reader options available
+// CSVOptions apply to the records of the file, except for the ones specific to delimited text (ex: Delimiter, Headers)
+// RecordPath is the path of the elements read as records (ex: /orders/order), where * matches any element. Default value is the children of the root element
+// KeyDelimiter joins the names of nested elements & the indexes of repeated elements into column names (ex: items.0.sku), which matches the array delimiter of the parser. Default value is "."
+type XMLOptions struct {
+	CSVOptions
+	RecordPath   string
+	KeyDelimiter string
+}
+
+// XML is a lightweight interface for reading tabular data from XML files
+// The child elements & attributes of the record elements are flattened into records. Elements which appear more than once are indexed
+type XML interface {
+	FromPath(ctx context.Context, filePath string) ([]map[string]string, error)
+	FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error)
+	Open(ctx context.Context, src io.Reader) (RecordIterator, error)
+}
+
+type xmlReader struct {
+	csv     *csv
+	options XMLOptions
+}
+
+// FromPath reads an XML file from a file path
+func (x *xmlReader) FromPath(ctx context.Context, filePath string) ([]map[string]string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	it, err := x.open(ctx, filePath, file)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// FromReader reads an XML file from an io.Reader
+func (x *xmlReader) FromReader(ctx context.Context, r io.Reader) ([]map[string]string, error) {
+	it, err := x.open(ctx, "", r)
+	if err != nil {
+		return nil, err
+	}
+
+	return readAll(it)
+}
+
+// Open returns an iterator over the records of the XML file read from src
+func (x *xmlReader) Open(ctx context.Context, src io.Reader) (RecordIterator, error) {
+	return x.open(ctx, "", src)
+}
+
+func (x *xmlReader) open(ctx context.Context, source string, src io.Reader) (*recordIterator, error) {
+	input, err := x.csv.openText(src)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := xml.NewDecoder(input.text)
+	decoder.CharsetReader = func(charset string, r io.Reader) (io.Reader, error) {
+		// The text is already decoded when the encoding is provided or comes from a UTF-16 BOM
+		if x.options.Encoding != "" || input.bom == BOMUTF16LE || input.bom == BOMUTF16BE {
+			return r, nil
+		}
+		return decode(r, charset)
+	}
+
+	rows := &xmlRowReader{
+		decoder:      decoder,
+		path:         strings.Split(strings.Trim(x.options.RecordPath, "/"), "/"),
+		keyDelimiter: x.options.KeyDelimiter,
+	}
+
+	return x.csv.newTextIterator(ctx, source, input, rows)
+}
+
+// xmlRowReader streams the record elements & flattens them into keyed rows
+type xmlRowReader struct {
+	decoder      *xml.Decoder
+	path         []string
+	keyDelimiter string
+	// stack holds the names of the open elements
+	stack []string
+	line  int
+
+	keys   []string
+	fields map[string]string
+}
+
+// xmlNode is an element of a record
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	text     strings.Builder
+	children []*xmlNode
+}
+
+func (r *xmlRowReader) Read() ([]string, error) {
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			r.stack = append(r.stack, element.Name.Local)
+			if !r.isRecord() {
+				continue
+			}
+
+			r.line, _ = r.decoder.InputPos()
+			node, err := r.readNode(element)
+			if err != nil {
+				return nil, err
+			}
+			r.stack = r.stack[:len(r.stack)-1]
+
+			r.keys = nil
+			r.fields = make(map[string]string)
+			r.flatten("", node)
+
+			fields := make([]string, len(r.keys))
+			for i, key := range r.keys {
+				fields[i] = r.fields[key]
+			}
+			return fields, nil
+		case xml.EndElement:
+			r.stack = r.stack[:len(r.stack)-1]
+		}
+	}
+}
+
+// isRecord checks if the open elements match the record path
+func (r *xmlRowReader) isRecord() bool {
+	// The default record path is the children of the root element
+	if len(r.path) == 1 && r.path[0] == "" {
+		return len(r.stack) == 2
+	}
+
+	if len(r.stack) != len(r.path) {
+		return false
+	}
+	for i, name := range r.path {
+		if name != "*" && name != r.stack[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// readNode reads an element up to its end element
+func (r *xmlRowReader) readNode(start xml.StartElement) (*xmlNode, error) {
+	node := &xmlNode{name: start.Name.Local, attrs: start.Attr}
+	for {
+		token, err := r.decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			child, err := r.readNode(element)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		case xml.CharData:
+			node.text.Write(element)
+		case xml.EndElement:
+			return node, nil
+		}
+	}
+}
+
+// flatten adds the attributes, text & children of an element to the record, with the keys joined by the key delimiter
+func (r *xmlRowReader) flatten(prefix string, node *xmlNode) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + r.keyDelimiter + key
+	}
+
+	for _, attr := range node.attrs {
+		// Namespace declarations aren't data
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		r.set(join(attr.Name.Local), attr.Value)
+	}
+
+	text := strings.TrimSpace(node.text.String())
+	switch {
+	case prefix == "":
+		// Records without children are the text of the record element
+		if len(node.children) == 0 && (text != "" || len(node.attrs) == 0) {
+			r.set(node.name, text)
+		}
+	case len(node.children) == 0 || text != "":
+		r.set(prefix, text)
+	}
+
+	counts := make(map[string]int)
+	for _, child := range node.children {
+		counts[child.name]++
+	}
+	indexes := make(map[string]int)
+	for _, child := range node.children {
+		key := join(child.name)
+		if counts[child.name] > 1 {
+			key += r.keyDelimiter + strconv.Itoa(indexes[child.name])
+			indexes[child.name]++
+		}
+		r.flatten(key, child)
+	}
+}
+
+func (r *xmlRowReader) set(key string, value string) {
+	if _, ok := r.fields[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.fields[key] = value
+}
+
+func (r *xmlRowReader) Line() int {
+	return r.line
+}
+
+func (r *xmlRowReader) Keys() []string {
+	return r.keys
+}
+
+// NewXML is the initialization method for the XML reader
+func NewXML(options XMLOptions) XML {
+	if options.KeyDelimiter == "" {
+		options.KeyDelimiter = "."
+	}
+
+	return &xmlReader{
+		csv:     NewCSV(options.CSVOptions).(*csv),
+		options: options,
+	}
+}
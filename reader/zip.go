@@ -0,0 +1,50 @@
+package reader
+
+import (
+	"archive/zip"
+	"context"
+	"path"
+)
+
+// FromZip reads the CSV members of a zip archive whose names match memberPattern
+// The pattern follows the path.Match syntax. The records of all the matching members are merged in archive order
+func (c *csv) FromZip(ctx context.Context, archivePath string, memberPattern string) ([]map[string]string, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var lines []map[string]string
+	for _, member := range archive.File {
+		if member.FileInfo().IsDir() {
+			continue
+		}
+
+		ok, err := path.Match(memberPattern, member.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		records, err := c.readZipMember(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, records...)
+	}
+
+	return lines, nil
+}
+
+func (c *csv) readZipMember(ctx context.Context, member *zip.File) ([]map[string]string, error) {
+	file, err := member.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return c.getRecords(ctx, member.Name, file)
+}